@@ -0,0 +1,270 @@
+package acl
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package acl enforces declarative access rules from the JSON config.
+
+Summary
+-------
+- A Rule maps a path pattern (and optionally methods) to requirements:
+  client IP ranges, roles (any of) and scopes (all of).
+- Rules are checked in order; the first matching rule applies.
+  Requests matching no rule are allowed.
+- Roles and scopes are read from the Bearer claims placed in the context
+  by middleware.BearerContextMap (claims "roles" and "scope" by default);
+  a custom IdentityFunc can be set for other sources.
+- Violations are answered with 401 (no identity) or 403 via the server
+  error helpers.
+
+Patterns:
+
+	/admin          exact path
+	/admin/*        /admin and everything below
+	/files/*.pdf    path.Match glob (one segment per *)
+
+Example config:
+
+	"acl": {
+	  "rules": [
+	    { "pattern": "/admin/*", "roles": ["admin"], "cidrs": ["10.0.0.0/8"] },
+	    { "pattern": "/api/*", "methods": ["POST", "PUT", "DELETE"], "scopes": ["notes:write"] }
+	  ]
+	}
+*/
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Rule defines the requirements for matching requests.
+type Rule struct {
+	Pattern string   `json:"pattern"`           // path pattern (see package doc)
+	Methods []string `json:"methods,omitempty"` // restrict the rule to methods (GET includes HEAD); empty means all
+	Roles   []string `json:"roles,omitempty"`   // caller needs at least one of these roles
+	Scopes  []string `json:"scopes,omitempty"`  // caller needs all of these scopes
+	CIDRs   []string `json:"cidrs,omitempty"`   // client IP must be in one of these ranges
+}
+
+// Config defines the access rules and where identities are read from.
+type Config struct {
+	Rules       []Rule `json:"rules"`
+	RolesClaim  string `json:"roles_claim"`  // claim holding roles (array or space-separated string)
+	ScopesClaim string `json:"scopes_claim"` // claim holding scopes (array or space-separated string)
+}
+
+// DefaultConfig returns a configuration without rules (everything allowed).
+func DefaultConfig() Config {
+	return Config{
+		Rules:       []Rule{},
+		RolesClaim:  "roles",
+		ScopesClaim: "scope",
+	}
+}
+
+// Identity is the authenticated caller as seen by the ACL.
+type Identity struct {
+	Roles  []string
+	Scopes []string
+}
+
+// IdentityFunc returns the caller identity of r, or false if the
+// request is unauthenticated.
+type IdentityFunc func(r *http.Request) (Identity, bool)
+
+// ACL is a compiled set of rules.
+type ACL struct {
+	rules    []rule
+	identity IdentityFunc
+}
+
+// rule is a Rule with parsed IP ranges.
+type rule struct {
+	Rule
+	prefixes []netip.Prefix
+}
+
+// New compiles the rules of cfg. It fails on malformed patterns or CIDRs.
+func New(cfg ...Config) (*ACL, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	d := DefaultConfig()
+	if c.RolesClaim == "" {
+		c.RolesClaim = d.RolesClaim
+	}
+	if c.ScopesClaim == "" {
+		c.ScopesClaim = d.ScopesClaim
+	}
+
+	a := &ACL{identity: ClaimsIdentity(c.RolesClaim, c.ScopesClaim)}
+	for i, r := range c.Rules {
+		if !strings.HasPrefix(r.Pattern, "/") {
+			return nil, fmt.Errorf("acl: rule %d: pattern %q must start with /", i, r.Pattern)
+		}
+		if _, err := path.Match(r.Pattern, "/"); err != nil {
+			return nil, fmt.Errorf("acl: rule %d: %w", i, err)
+		}
+
+		cr := rule{Rule: r}
+		for _, s := range r.CIDRs {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				// Allow single addresses
+				addr, aerr := netip.ParseAddr(s)
+				if aerr != nil {
+					return nil, fmt.Errorf("acl: rule %d: invalid cidr %q", i, s)
+				}
+				p = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			cr.prefixes = append(cr.prefixes, p.Masked())
+		}
+		a.rules = append(a.rules, cr)
+	}
+	return a, nil
+}
+
+// SetIdentity replaces the identity source (default: Bearer claims).
+func (a *ACL) SetIdentity(fn IdentityFunc) {
+	a.identity = fn
+}
+
+// Check returns http.StatusOK if r is allowed, otherwise
+// http.StatusUnauthorized or http.StatusForbidden.
+func (a *ACL) Check(r *http.Request) int {
+	for _, ru := range a.rules {
+		if !ru.matches(r) {
+			continue
+		}
+
+		if len(ru.prefixes) > 0 && !ru.allowsIP(r) {
+			return http.StatusForbidden
+		}
+		if len(ru.Roles) == 0 && len(ru.Scopes) == 0 {
+			return http.StatusOK
+		}
+
+		id, ok := a.identity(r)
+		if !ok {
+			return http.StatusUnauthorized
+		}
+		if len(ru.Roles) > 0 && !slices.ContainsFunc(ru.Roles, func(role string) bool {
+			return slices.Contains(id.Roles, role)
+		}) {
+			return http.StatusForbidden
+		}
+		for _, s := range ru.Scopes {
+			if !slices.Contains(id.Scopes, s) {
+				return http.StatusForbidden
+			}
+		}
+		return http.StatusOK
+	}
+	return http.StatusOK
+}
+
+// Middleware returns a middleware enforcing the rules. Place it after
+// the middleware that establishes the identity (e.g. BearerContextMap).
+func (a *ACL) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if len(a.rules) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch a.Check(r) {
+			case http.StatusUnauthorized:
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				server.Unauthorized(w, r)
+			case http.StatusForbidden:
+				server.Forbidden(w, r)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// ClaimsIdentity reads roles and scopes from the Bearer claims map.
+func ClaimsIdentity(rolesClaim, scopesClaim string) IdentityFunc {
+	return func(r *http.Request) (Identity, bool) {
+		claims, ok := middleware.GetBearerClaimsMap(r.Context())
+		if !ok {
+			return Identity{}, false
+		}
+		return Identity{
+			Roles:  claimList(claims[rolesClaim]),
+			Scopes: claimList(claims[scopesClaim]),
+		}, true
+	}
+}
+
+// claimList converts a claim value into a list of strings. Strings are
+// split on whitespace (OAuth "scope" style).
+func claimList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return strings.Fields(t)
+	case []string:
+		return t
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to r. A rule listing GET
+// also applies to HEAD, as the mux routes HEAD to GET handlers.
+func (ru *rule) matches(r *http.Request) bool {
+	if len(ru.Methods) > 0 && !slices.ContainsFunc(ru.Methods, func(m string) bool {
+		return strings.EqualFold(m, r.Method) ||
+			(r.Method == http.MethodHead && strings.EqualFold(m, http.MethodGet))
+	}) {
+		return false
+	}
+
+	p := path.Clean(r.URL.Path)
+	if base, ok := strings.CutSuffix(ru.Pattern, "/*"); ok {
+		return p == base || strings.HasPrefix(p, base+"/") || (base == "" && p == "/")
+	}
+	ok, _ := path.Match(ru.Pattern, p)
+	return ok
+}
+
+// allowsIP reports whether the client IP is in one of the rule's ranges.
+func (ru *rule) allowsIP(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range ru.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
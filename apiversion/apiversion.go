@@ -0,0 +1,255 @@
+package apiversion
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package apiversion provides routing helpers and middleware for API versioning.
+
+Summary
+-------
+- Path versioning: Group registers routes under a version prefix
+  (/api/v1/..., /api/v2/...) on a server.Server, all sharing one
+  middleware stack.
+- Header versioning: Negotiate selects the version from a vendor media
+  type (Accept: application/vnd.<vendor>.v2+json), a version header or
+  a query parameter; Switch dispatches to the matching handler.
+- Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers are added
+  for deprecated versions in both modes.
+- The selected version is available via FromContext.
+
+Typical usage (path prefix):
+
+	api := middleware.Chain(middleware.RequestID, middleware.Logging)
+
+	v1 := apiversion.NewGroup(srv, "/api", apiversion.Version{Name: "v1", Deprecated: dep, Sunset: sunset}, api)
+	v2 := apiversion.NewGroup(srv, "/api", apiversion.Version{Name: "v2"}, api)
+	v1.HandleFunc("GET /notes", listV1)   // GET /api/v1/notes
+	v2.HandleFunc("GET /notes", listV2)   // GET /api/v2/notes
+
+Typical usage (Accept header):
+
+	neg := apiversion.Negotiate(cfg)
+	srv.Handle("GET /api/notes", api(neg(apiversion.Switch(map[string]http.Handler{
+		"v1": listV1,
+		"v2": listV2,
+	}))))
+*/
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Version describes a single API version.
+type Version struct {
+	Name       string    `json:"name"`       // Version name as used in paths and media types, e.g. "v1"
+	Deprecated time.Time `json:"deprecated"` // Deprecation date (zero = not deprecated)
+	Sunset     time.Time `json:"sunset"`     // Date after which the version is removed (zero = none)
+	Link       string    `json:"link"`       // Migration guide for deprecated versions
+}
+
+// Config defines header-based version negotiation.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	Vendor   string    `json:"vendor"`   // Vendor in application/vnd.<vendor>.<version>+json
+	Header   string    `json:"header"`   // Explicit version header (empty disables)
+	Query    string    `json:"query"`    // Explicit version query parameter (empty disables)
+	Default  string    `json:"default"`  // Version used if the client does not ask for one
+	Versions []Version `json:"versions"` // Known versions
+}
+
+// DefaultConfig returns a default negotiation configuration with a
+// single version "v1".
+func DefaultConfig() Config {
+	return Config{
+		Vendor:   "gobfwebservice",
+		Header:   "X-API-Version",
+		Query:    "",
+		Default:  "v1",
+		Versions: []Version{{Name: "v1"}},
+	}
+}
+
+/* ---------- context ---------- */
+
+// versionKey stores the selected Version.
+var versionKey = ctxutil.NewKey[Version]("api-version")
+
+// FromContext returns the API version selected for the request.
+func FromContext(ctx context.Context) (Version, bool) {
+	return versionKey.Get(ctx)
+}
+
+// Stamp stores v in the request context and adds its deprecation headers.
+func Stamp(v Version) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			setHeaders(w.Header(), v)
+			next.ServeHTTP(w, r.WithContext(versionKey.Set(r.Context(), v)))
+		})
+	}
+}
+
+/* ---------- path versioning ---------- */
+
+// Group registers routes for one API version under a common path prefix.
+type Group struct {
+	srv     *server.Server
+	prefix  string
+	version Version
+	mw      middleware.Middleware
+}
+
+// NewGroup creates a route group for version v. Routes are registered
+// under base + "/" + v.Name and wrapped with mw (may be nil).
+func NewGroup(srv *server.Server, base string, v Version, mw middleware.Middleware) *Group {
+	if mw == nil {
+		mw = middleware.Chain()
+	}
+	return &Group{
+		srv:     srv,
+		prefix:  strings.TrimSuffix(base, "/") + "/" + v.Name,
+		version: v,
+		mw:      middleware.Chain(Stamp(v), mw),
+	}
+}
+
+// Prefix returns the path prefix of the group, e.g. "/api/v1".
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// Version returns the version served by the group.
+func (g *Group) Version() Version {
+	return g.version
+}
+
+// Handle registers handler for pattern ("[METHOD ]/path") below the
+// group prefix. The version name is added to the route tags.
+func (g *Group) Handle(pattern string, handler http.Handler, doc ...server.RouteDoc) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "", pattern
+	}
+
+	full := g.prefix + path
+	if method != "" {
+		full = method + " " + full
+	}
+
+	var d server.RouteDoc
+	if len(doc) > 0 {
+		d = doc[0]
+	}
+	d.Tags = append(d.Tags[:len(d.Tags):len(d.Tags)], g.version.Name)
+
+	g.srv.Handle(full, g.mw(handler), d)
+}
+
+// HandleFunc registers fn for pattern; see Handle.
+func (g *Group) HandleFunc(pattern string, fn http.HandlerFunc, doc ...server.RouteDoc) {
+	g.Handle(pattern, fn, doc...)
+}
+
+/* ---------- header versioning ---------- */
+
+// Negotiate selects the API version from the request headers (see
+// Config). Unknown versions are rejected with 406 Not Acceptable.
+func Negotiate(cfg ...Config) middleware.Middleware {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	known := make(map[string]Version, len(c.Versions))
+	for _, v := range c.Versions {
+		known[v.Name] = v
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+			if c.Header != "" {
+				w.Header().Add("Vary", c.Header)
+			}
+
+			name := requested(r, c)
+			if name == "" {
+				name = c.Default
+			}
+
+			v, ok := known[name]
+			if !ok {
+				server.RenderError(w, r, http.StatusNotAcceptable, "Not Acceptable",
+					"Unsupported API version: "+name)
+				return
+			}
+
+			Stamp(v)(next).ServeHTTP(w, r)
+		})
+	}
+}
+
+// Switch dispatches to the handler registered for the version selected
+// by Negotiate (or a Group). Requests for versions without a handler
+// get 404 Not Found.
+func Switch(handlers map[string]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, _ := FromContext(r.Context())
+		h, ok := handlers[v.Name]
+		if !ok {
+			server.NotFound(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requested extracts the version asked for by the client, in order:
+// query parameter, version header, vendor media type in Accept.
+func requested(r *http.Request, c Config) string {
+	if c.Query != "" {
+		if v := r.URL.Query().Get(c.Query); v != "" {
+			return v
+		}
+	}
+	if c.Header != "" {
+		if v := r.Header.Get(c.Header); v != "" {
+			return v
+		}
+	}
+
+	if c.Vendor == "" {
+		return ""
+	}
+	prefix := "application/vnd." + c.Vendor + "."
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if rest, ok := strings.CutPrefix(mt, prefix); ok {
+			name, _, _ := strings.Cut(rest, "+")
+			return name
+		}
+	}
+	return ""
+}
+
+// setHeaders adds deprecation headers for v.
+func setHeaders(h http.Header, v Version) {
+	if !v.Deprecated.IsZero() {
+		h.Set("Deprecation", "@"+strconv.FormatInt(v.Deprecated.Unix(), 10))
+		if v.Link != "" {
+			h.Add("Link", "<"+v.Link+`>; rel="deprecation"`)
+		}
+	}
+	if !v.Sunset.IsZero() {
+		h.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,301 @@
+package assets
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package assets bundles and minifies CSS/JS without a Node toolchain.
+
+Summary
+-------
+- A Bundle concatenates source files (in order) into one output file;
+  the type (CSS or JS) follows from the bundle name's extension.
+- Bundles are optionally minified (conservatively, see minify.go) and
+  written with a content hash in the file name (app.3f9a1c2b4d.css),
+  so they can be cached forever (see static.Handler).
+- A manifest.json maps bundle names to fingerprinted files. Build writes
+  it; Load reads it, so bundles can be prebuilt by the "assets" command
+  and only looked up at startup.
+- Funcs provides template helpers resolving bundle names to URLs and
+  Subresource Integrity hashes (sha384, computed once per build or load);
+  Rebuild (e.g. on SIGHUP) updates them in place.
+
+Example config:
+
+	"assets": {
+	  "source_dir": "web",
+	  "out_dir": "web/dist",
+	  "url_prefix": "/assets/",
+	  "minify": true,
+	  "bundles": [
+	    { "name": "app.css", "files": ["css/reset.css", "css/site.css"] },
+	    { "name": "app.js",  "files": ["js/htmx.min.js", "js/app.js"] }
+	  ]
+	}
+
+Templates:
+
+	<link rel="stylesheet" href="{{asset "app.css"}}" integrity="{{sri "app.css"}}">
+	{{assetTag "app.js"}}
+*/
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/static"
+)
+
+// ManifestFile is the name of the manifest written into OutDir.
+const ManifestFile = "manifest.json"
+
+// Bundle is a group of source files combined into one output file.
+type Bundle struct {
+	Name  string   `json:"name"`  // Output name, e.g. "app.css" or "app.js"
+	Files []string `json:"files"` // Source files relative to SourceDir, in order
+}
+
+// Config defines the asset pipeline.
+// All fields are JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	SourceDir string   `json:"source_dir"` // Directory containing the source files
+	OutDir    string   `json:"out_dir"`    // Directory receiving bundles and manifest
+	URLPrefix string   `json:"url_prefix"` // URL path under which OutDir is served
+	Minify    bool     `json:"minify"`     // Minify bundles
+	Bundles   []Bundle `json:"bundles"`    // Bundles to build; empty disables the pipeline
+}
+
+// DefaultConfig returns a configuration without bundles.
+func DefaultConfig() Config {
+	return Config{
+		SourceDir: "web",
+		OutDir:    "web/dist",
+		URLPrefix: "/assets/",
+		Minify:    true,
+		Bundles:   []Bundle{},
+	}
+}
+
+// Assets resolves bundle names to fingerprinted URLs.
+type Assets struct {
+	config Config
+
+	mu        sync.RWMutex
+	manifest  map[string]string // bundle name -> fingerprinted file name
+	integrity map[string]string // bundle name -> SRI hash ("sha384-...")
+}
+
+// Build builds all bundles, writes them and the manifest to OutDir and
+// returns the resulting Assets.
+func Build(cfg Config) (*Assets, error) {
+	a := &Assets{config: cfg}
+	if err := a.Rebuild(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Load reads the manifest of previously built bundles from OutDir.
+func Load(cfg Config) (*Assets, error) {
+	b, err := os.ReadFile(filepath.Join(cfg.OutDir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("assets: %w", err)
+	}
+
+	m := map[string]string{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("assets: %s: %w", ManifestFile, err)
+	}
+
+	sri := make(map[string]string, len(m))
+	for name, file := range m {
+		out, err := os.ReadFile(filepath.Join(cfg.OutDir, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("assets: bundle %s: %w", name, err)
+		}
+		sri[name] = integrity(out)
+	}
+	return &Assets{config: cfg, manifest: m, integrity: sri}, nil
+}
+
+// Rebuild builds all bundles again and swaps the manifest. On error the
+// previous manifest stays in use. Outdated bundle files are not removed.
+func (a *Assets) Rebuild() error {
+	// Nothing configured: do not touch OutDir
+	if len(a.config.Bundles) == 0 {
+		a.mu.Lock()
+		a.manifest = map[string]string{}
+		a.integrity = map[string]string{}
+		a.mu.Unlock()
+		return nil
+	}
+
+	if err := os.MkdirAll(a.config.OutDir, 0o755); err != nil {
+		return fmt.Errorf("assets: %w", err)
+	}
+
+	m := map[string]string{}
+	sri := map[string]string{}
+	for _, b := range a.config.Bundles {
+		file, out, err := a.build(b)
+		if err != nil {
+			return fmt.Errorf("assets: bundle %s: %w", b.Name, err)
+		}
+		m[b.Name] = file
+		sri[b.Name] = integrity(out)
+	}
+
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(a.config.OutDir, ManifestFile), append(out, '\n'), 0o644); err != nil {
+		return fmt.Errorf("assets: %w", err)
+	}
+
+	a.mu.Lock()
+	a.manifest = m
+	a.integrity = sri
+	a.mu.Unlock()
+	return nil
+}
+
+// Manifest returns a copy of the bundle name to file name mapping.
+func (a *Assets) Manifest() map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	m := make(map[string]string, len(a.manifest))
+	for k, v := range a.manifest {
+		m[k] = v
+	}
+	return m
+}
+
+// URL returns the URL of bundle name.
+func (a *Assets) URL(name string) (string, error) {
+	a.mu.RLock()
+	file, ok := a.manifest[name]
+	a.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("assets: unknown bundle %q", name)
+	}
+	return path.Join("/", a.config.URLPrefix, file), nil
+}
+
+// Integrity returns the Subresource Integrity hash of bundle name
+// ("sha384-<base64>"), for the integrity attribute.
+func (a *Assets) Integrity(name string) (string, error) {
+	a.mu.RLock()
+	sri, ok := a.integrity[name]
+	a.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("assets: unknown bundle %q", name)
+	}
+	return sri, nil
+}
+
+// Funcs returns the template functions:
+//
+//	asset "app.css"     URL of the bundle
+//	sri "app.js"        Subresource Integrity hash of the bundle
+//	assetTag "app.js"   <link> or <script> element with integrity attribute
+//
+// Unknown bundles fail template execution.
+func (a *Assets) Funcs() template.FuncMap {
+	return template.FuncMap{
+		"asset": a.URL,
+		"sri":   a.Integrity,
+		"assetTag": func(name string) (template.HTML, error) {
+			u, err := a.URL(name)
+			if err != nil {
+				return "", err
+			}
+			sri, err := a.Integrity(name)
+			if err != nil {
+				return "", err
+			}
+			u = template.HTMLEscapeString(u)
+			attrs := ` integrity="` + template.HTMLEscapeString(sri) + `"`
+			if strings.EqualFold(path.Ext(name), ".css") {
+				return template.HTML(`<link rel="stylesheet" href="` + u + `"` + attrs + `>`), nil
+			}
+			return template.HTML(`<script src="` + u + `"` + attrs + ` defer></script>`), nil
+		},
+	}
+}
+
+// Handler serves OutDir with long-lived caching for the fingerprinted
+// bundles. Mount it under URLPrefix with http.StripPrefix.
+func (a *Assets) Handler() http.Handler {
+	c := static.DefaultConfig()
+	c.Dir = a.config.OutDir
+	return static.Handler(c)
+}
+
+// build concatenates, minifies and writes a bundle and returns the
+// fingerprinted file name and the content.
+func (a *Assets) build(b Bundle) (string, []byte, error) {
+	ext := strings.ToLower(path.Ext(b.Name))
+	if ext != ".css" && ext != ".js" {
+		return "", nil, fmt.Errorf("unsupported type %q (want .css or .js)", ext)
+	}
+
+	var buf bytes.Buffer
+	for _, f := range b.Files {
+		src, err := os.ReadFile(filepath.Join(a.config.SourceDir, filepath.FromSlash(f)))
+		if err != nil {
+			return "", nil, err
+		}
+		buf.Write(src)
+		// Separate files: a missing trailing newline or semicolon must
+		// not merge the last statement with the next file
+		if ext == ".js" {
+			buf.WriteString("\n;\n")
+		} else {
+			buf.WriteByte('\n')
+		}
+	}
+
+	out := buf.Bytes()
+	if a.config.Minify {
+		if ext == ".css" {
+			out = MinifyCSS(out)
+		} else {
+			out = MinifyJS(out)
+		}
+	}
+
+	sum := sha256.Sum256(out)
+	base := strings.TrimSuffix(b.Name, path.Ext(b.Name))
+	file := base + "." + hex.EncodeToString(sum[:5]) + ext
+
+	dst := filepath.Join(a.config.OutDir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", nil, err
+	}
+	if err := os.WriteFile(dst, out, 0o644); err != nil {
+		return "", nil, err
+	}
+	return file, out, nil
+}
+
+// integrity returns the SRI hash of content.
+func integrity(content []byte) string {
+	sum := sha512.Sum384(content)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,167 @@
+package assets
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Conservative CSS and JS minification.
+
+The minifiers only remove what is safe to remove without a full parser:
+comments (except /*! license comments), indentation and blank lines,
+and for CSS whitespace around punctuation. String literals are copied
+unchanged. JS line breaks are kept because of automatic semicolon
+insertion; identifiers are never renamed. The result is larger than
+that of dedicated tools but never changes behavior for ordinary code.
+*/
+
+import (
+	"bytes"
+)
+
+// MinifyCSS removes comments and redundant whitespace from CSS.
+func MinifyCSS(src []byte) []byte {
+	var out bytes.Buffer
+	space := false // pending whitespace
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'':
+			flushSpace(&out, &space, c)
+			i = copyString(&out, src, i)
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			if i+2 < len(src) && src[i+2] == '!' {
+				flushSpace(&out, &space, c)
+				out.Write(src[i:min(i+2+end+2, len(src))])
+			}
+			i += 2 + end + 1
+
+		case isSpace(c):
+			space = true
+
+		default:
+			flushSpace(&out, &space, c)
+			// Drop the last semicolon of a block
+			if c == '}' && out.Len() > 0 && out.Bytes()[out.Len()-1] == ';' {
+				out.Truncate(out.Len() - 1)
+			}
+			out.WriteByte(c)
+		}
+	}
+	return bytes.TrimSpace(out.Bytes())
+}
+
+// flushSpace writes a pending space unless it is adjacent to CSS
+// punctuation where whitespace is insignificant.
+func flushSpace(out *bytes.Buffer, space *bool, next byte) {
+	if !*space {
+		return
+	}
+	*space = false
+	if out.Len() == 0 || isCSSPunct(next) || isCSSPunct(out.Bytes()[out.Len()-1]) {
+		return
+	}
+	out.WriteByte(' ')
+}
+
+// isCSSPunct reports whether whitespace around c can be removed.
+// ':' is excluded: "a :hover" and "a:hover" are different selectors.
+func isCSSPunct(c byte) bool {
+	switch c {
+	case '{', '}', ';', ',', '>':
+		return true
+	}
+	return false
+}
+
+// MinifyJS removes comments, indentation and blank lines from JavaScript.
+func MinifyJS(src []byte) []byte {
+	var out bytes.Buffer
+	lineStart := true // only whitespace since the last newline
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			i = copyString(&out, src, i)
+			lineStart = false
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			if i+2 < len(src) && src[i+2] == '!' {
+				out.Write(src[i:min(i+2+end+2, len(src))])
+				lineStart = false
+			}
+			i += 2 + end + 1
+
+		// Line comments only after whitespace or at line start, so
+		// regular expression literals like /a\/\// stay intact
+		case c == '/' && i+1 < len(src) && src[i+1] == '/' && (lineStart || (i > 0 && isSpace(src[i-1]))):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i--
+
+		case c == '\n' || c == '\r':
+			if !lineStart {
+				trimTrailingSpace(&out)
+				out.WriteByte('\n')
+			}
+			lineStart = true
+
+		case isSpace(c):
+			if !lineStart {
+				out.WriteByte(c)
+			}
+
+		default:
+			out.WriteByte(c)
+			lineStart = false
+		}
+	}
+	trimTrailingSpace(&out)
+	return out.Bytes()
+}
+
+// copyString copies the string literal starting at src[i] (including
+// its quotes and escapes) and returns the index of the closing quote.
+func copyString(out *bytes.Buffer, src []byte, i int) int {
+	quote := src[i]
+	out.WriteByte(quote)
+	for i++; i < len(src); i++ {
+		c := src[i]
+		out.WriteByte(c)
+		if c == '\\' && i+1 < len(src) {
+			i++
+			out.WriteByte(src[i])
+			continue
+		}
+		if c == quote {
+			break
+		}
+	}
+	return i
+}
+
+// trimTrailingSpace removes spaces and tabs at the end of out.
+func trimTrailingSpace(out *bytes.Buffer) {
+	b := out.Bytes()
+	n := len(b)
+	for n > 0 && (b[n-1] == ' ' || b[n-1] == '\t') {
+		n--
+	}
+	out.Truncate(n)
+}
+
+// isSpace reports whether c is ASCII whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
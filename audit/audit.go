@@ -0,0 +1,249 @@
+package audit
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package audit records state-changing API calls including a bounded,
+redacted prefix of the request body.
+
+Summary
+-------
+- Opt-in: nothing is captured unless Config.Enabled is set and a rule
+  matches the request (path pattern and methods, as in the acl package).
+- The body is teed while the handler reads it: at most MaxBodyBytes are
+  kept, the handler still sees the complete body, and nothing is read
+  that the handler does not read itself.
+- Values of sensitive fields (RedactFields) are replaced in JSON and
+  form bodies, even if the captured prefix is truncated.
+- One entry per request is written after the handler returned, with
+  method, path, status, request ID, anonymized client IP (see
+  logging.ClientIP) and the subject of the Bearer claims. The default
+  sink writes "audit:" lines to the global logger; SetSink replaces it
+  (e.g. to write to a database).
+
+Example config:
+
+	"audit": {
+	  "enabled": true,
+	  "max_body_bytes": 4096,
+	  "rules": [
+	    { "pattern": "/api/*", "methods": ["POST", "PUT", "PATCH", "DELETE"] }
+	  ],
+	  "redact_fields": ["password", "token", "secret", "totp"]
+	}
+*/
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/logging"
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Rule selects audited requests.
+type Rule struct {
+	Pattern string   `json:"pattern"`           // path pattern: exact, /prefix/* or path.Match glob
+	Methods []string `json:"methods,omitempty"` // restrict the rule to methods; empty means all
+}
+
+// Config defines which requests are audited and how bodies are captured.
+type Config struct {
+	Enabled      bool     `json:"enabled"`
+	MaxBodyBytes int      `json:"max_body_bytes"` // body prefix kept per request; 0 captures no body
+	Rules        []Rule   `json:"rules"`          // audited requests (any rule matches)
+	RedactFields []string `json:"redact_fields"`  // JSON/form fields whose values are replaced (case-insensitive)
+	SubjectClaim string   `json:"subject_claim"`  // Bearer claim naming the caller
+}
+
+// DefaultConfig returns a disabled audit configuration covering all
+// state-changing API calls.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:      false,
+		MaxBodyBytes: 4 << 10,
+		Rules: []Rule{
+			{Pattern: "/api/*", Methods: []string{"POST", "PUT", "PATCH", "DELETE"}},
+		},
+		RedactFields: []string{"password", "token", "secret", "totp", "code"},
+		SubjectClaim: "sub",
+	}
+}
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "REDACTED"
+
+// Entry is a single audited request.
+type Entry struct {
+	Time      time.Time     `json:"time"`
+	RequestID string        `json:"request_id,omitempty"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Status    int           `json:"status"`
+	Duration  time.Duration `json:"duration"`
+	ClientIP  string        `json:"client_ip"` // anonymized per logging config
+	Subject   string        `json:"subject,omitempty"`
+	Body      string        `json:"body,omitempty"` // redacted prefix
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// Sink receives audit entries.
+type Sink func(e Entry)
+
+// Auditor captures audited requests.
+type Auditor struct {
+	config Config
+	redact *regexp.Regexp // nil without redacted fields
+	sink   Sink
+}
+
+// New creates an auditor logging to the global logger.
+func New(cfg ...Config) *Auditor {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	a := &Auditor{config: c, sink: LogSink}
+	if len(c.RedactFields) > 0 {
+		names := make([]string, len(c.RedactFields))
+		for i, f := range c.RedactFields {
+			names[i] = regexp.QuoteMeta(f)
+		}
+		alt := strings.Join(names, "|")
+		// JSON: "field": value    form: field=value
+		a.redact = regexp.MustCompile(
+			`(?i)("(?:` + alt + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)` +
+				`|((?:^|&)(?:` + alt + `)=)[^&]*`)
+	}
+	return a
+}
+
+// SetSink replaces the destination of audit entries.
+// It must be called before the middleware serves requests.
+func (a *Auditor) SetSink(s Sink) {
+	a.sink = s
+}
+
+// LogSink writes e as an "audit:" line to the global logger.
+func LogSink(e Entry) {
+	log.Printf("audit: request %s %s status=%d dur=%s user=%q ip=%s rid=%s truncated=%t body=%q",
+		e.Method, e.Path, e.Status, e.Duration, e.Subject, e.ClientIP, e.RequestID, e.Truncated, e.Body)
+}
+
+// Middleware returns a middleware auditing matching requests.
+// A disabled (or nil) auditor returns the handler unchanged.
+func (a *Auditor) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if a == nil || !a.config.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !a.matches(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			capture := &prefixBuffer{max: a.config.MaxBodyBytes}
+			if r.Body != nil && r.Body != http.NoBody && capture.max > 0 {
+				r.Body = readCloser{io.TeeReader(r.Body, capture), r.Body}
+			}
+
+			rw := middleware.WrapResponseWriter(w)
+			next.ServeHTTP(rw, r)
+
+			a.sink(Entry{
+				Time:      start.UTC(),
+				RequestID: middleware.GetRequestID(r.Context()),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    rw.Status(),
+				Duration:  time.Since(start),
+				ClientIP:  logging.ClientIP(r.RemoteAddr),
+				Subject:   a.subject(r),
+				Body:      a.redactBody(capture.buf.Bytes()),
+				Truncated: capture.truncated,
+			})
+		})
+	}
+}
+
+// matches reports whether any rule applies to r.
+func (a *Auditor) matches(r *http.Request) bool {
+	p := path.Clean(r.URL.Path)
+	for _, ru := range a.config.Rules {
+		if len(ru.Methods) > 0 && !slices.ContainsFunc(ru.Methods, func(m string) bool {
+			return strings.EqualFold(m, r.Method)
+		}) {
+			continue
+		}
+		if base, ok := strings.CutSuffix(ru.Pattern, "/*"); ok {
+			if p == base || strings.HasPrefix(p, base+"/") || (base == "" && p == "/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(ru.Pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// subject returns the caller named by the Bearer claims.
+func (a *Auditor) subject(r *http.Request) string {
+	claims, ok := middleware.GetBearerClaimsMap(r.Context())
+	if !ok {
+		return ""
+	}
+	s, _ := claims[a.config.SubjectClaim].(string)
+	return s
+}
+
+// redactBody replaces the values of redacted fields in body.
+func (a *Auditor) redactBody(body []byte) string {
+	if a.redact == nil || len(body) == 0 {
+		return string(body)
+	}
+	return a.redact.ReplaceAllStringFunc(string(body), func(m string) string {
+		sub := a.redact.FindStringSubmatch(m)
+		if sub[1] != "" {
+			return sub[1] + `"` + Redacted + `"`
+		}
+		return sub[3] + Redacted
+	})
+}
+
+// prefixBuffer keeps the first max bytes written to it.
+type prefixBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+// Write stores as much of p as fits and never fails.
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// readCloser combines a reader with the original body's Close.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
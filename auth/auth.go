@@ -0,0 +1,519 @@
+package auth
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package auth provides user authentication for HTML applications.
+
+Summary
+-------
+- Password hashing with a pluggable Hasher (password.go).
+- Pluggable UserStore with an in-memory implementation (user.go).
+- Server-side sessions on top of the cache package (session.go).
+- Optional TOTP second factor (totp.go).
+- Login, logout, registration and TOTP enrolment handlers rendering
+  views through the templates package.
+- LoadUser / RequireUser middleware and UserFrom for handlers.
+- Login, logout, registration and TOTP events on an optional
+  events.Bus (events.go).
+
+Views (rendered with a map containing Title, Error and view data; the
+{{csrfField}} and {{csrfToken}} functions are bound to the request):
+
+	login.html         form: username, password
+	login_totp.html    form: code
+	register.html      form: username, password, confirm
+	account.html       .User; logout form (POST /logout)
+	account_totp.html  .Secret, .URL; form: code
+
+Typical usage:
+
+	a := auth.New(auth.NewMemoryStore(), auth.NewSessions(nil, cfg), tmpl, cfg)
+	a.Register(srv, middleware.Chain(middleware.Recovery, middleware.Logging, csrf.Protect()))
+
+	srv.Handle("GET /admin", a.LoadUser(a.RequireUser(adminHandler)))
+*/
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bennof/gobfwebservice/csrf"
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/form"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+// Config defines the configuration of the auth subsystem.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	CookieName        string `json:"cookie_name"`        // Session cookie name
+	CookieSecure      bool   `json:"cookie_secure"`      // Send the cookie over HTTPS only
+	SessionTTL        int    `json:"session_ttl"`        // Session lifetime in seconds (0 = browser session, no expiry)
+	AllowRegistration bool   `json:"allow_registration"` // Enable GET/POST /register
+	AfterLogin        string `json:"after_login"`        // Default redirect target after login
+	TOTPIssuer        string `json:"totp_issuer"`        // Issuer name shown in authenticator apps
+	MinPassword       int    `json:"min_password"`       // Minimum password length
+	MaxTOTPFailures   int    `json:"max_totp_failures"`  // Invalid codes before a pending login is discarded (0 uses 5)
+}
+
+// DefaultConfig returns a default auth configuration.
+func DefaultConfig() Config {
+	return Config{
+		CookieName:        "session",
+		CookieSecure:      true,
+		SessionTTL:        7 * 24 * 3600,
+		AllowRegistration: true,
+		AfterLogin:        "/",
+		TOTPIssuer:        "gobfwebservice",
+		MinPassword:       8,
+		MaxTOTPFailures:   5,
+	}
+}
+
+// Auth serves the authentication pages and middleware.
+type Auth struct {
+	config   Config
+	users    UserStore
+	sessions *Sessions
+	tmpl     *templates.TemplateSet
+	bus      *events.Bus
+	totpMu   sync.Mutex // serializes second factor checks (failure counts, last step)
+}
+
+// New creates the auth subsystem.
+func New(users UserStore, sessions *Sessions, tmpl *templates.TemplateSet, cfg ...Config) *Auth {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	return &Auth{config: c, users: users, sessions: sessions, tmpl: tmpl}
+}
+
+// Users returns the user store.
+func (a *Auth) Users() UserStore {
+	return a.users
+}
+
+// Register registers all auth routes on srv. mw wraps every route
+// (e.g. recovery, request ID, logging, CSRF protection).
+func (a *Auth) Register(srv *server.Server, mw middleware.Middleware) {
+	page := func(h http.HandlerFunc) http.Handler { return mw(a.LoadUser(h)) }
+	user := func(h http.HandlerFunc) http.Handler { return mw(a.LoadUser(a.RequireUser(h))) }
+
+	srv.Handle("GET /login", page(a.loginPage), server.RouteDoc{Summary: "Login form", Tags: []string{"auth", "html"}})
+	srv.Handle("POST /login", page(a.login), server.RouteDoc{Summary: "Log in", Tags: []string{"auth", "html"}})
+	srv.Handle("GET /login/totp", page(a.totpPage), server.RouteDoc{Summary: "Second factor form", Tags: []string{"auth", "html"}})
+	srv.Handle("POST /login/totp", page(a.totpLogin), server.RouteDoc{Summary: "Verify second factor", Tags: []string{"auth", "html"}})
+	srv.Handle("POST /logout", page(a.logout), server.RouteDoc{Summary: "Log out", Tags: []string{"auth", "html"}})
+
+	if a.config.AllowRegistration {
+		srv.Handle("GET /register", page(a.registerPage), server.RouteDoc{Summary: "Registration form", Tags: []string{"auth", "html"}})
+		srv.Handle("POST /register", page(a.register), server.RouteDoc{Summary: "Create an account", Tags: []string{"auth", "html"}})
+	}
+
+	srv.Handle("GET /account", user(a.accountPage), server.RouteDoc{Summary: "Account page", Tags: []string{"auth", "html"}})
+	srv.Handle("GET /account/totp", user(a.enrolPage), server.RouteDoc{Summary: "Enable second factor", Tags: []string{"auth", "html"}})
+	srv.Handle("POST /account/totp", user(a.enrol), server.RouteDoc{Summary: "Confirm second factor", Tags: []string{"auth", "html"}})
+}
+
+/* ---------- middleware ---------- */
+
+// userKey stores the authenticated user.
+var userKey = ctxutil.NewKey[User]("auth-user")
+
+// LoadUser resolves the session cookie and stores the logged-in user in
+// the request context. Requests without a (complete) session pass through.
+func (a *Auth) LoadUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sess, ok := a.sessions.Get(r); ok && !sess.Pending {
+			if u, err := a.users.ByID(r.Context(), sess.UserID); err == nil {
+				r = r.WithContext(userKey.Set(r.Context(), u))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireUser redirects anonymous requests to the login page.
+// It must run after LoadUser.
+func (a *Auth) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFrom(r.Context()); !ok {
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserFrom returns the logged-in user stored by LoadUser.
+func UserFrom(ctx context.Context) (User, bool) {
+	return userKey.Get(ctx)
+}
+
+/* ---------- handlers ---------- */
+
+type loginForm struct {
+	Username string `form:"username"`
+	Password string `form:"password"`
+	Next     string `form:"next"`
+}
+
+type registerForm struct {
+	Username string `form:"username"`
+	Password string `form:"password"`
+	Confirm  string `form:"confirm"`
+}
+
+type codeForm struct {
+	Code   string `form:"code"`
+	Secret string `form:"secret"`
+	Next   string `form:"next"`
+}
+
+// loginPage renders the login form.
+func (a *Auth) loginPage(w http.ResponseWriter, r *http.Request) {
+	a.render(w, r, http.StatusOK, "login.html", map[string]any{
+		"Title": "Log in",
+		"Next":  r.URL.Query().Get("next"),
+	})
+}
+
+// login verifies the credentials and starts a (possibly pending) session.
+func (a *Auth) login(w http.ResponseWriter, r *http.Request) {
+	var in loginForm
+	if err := form.Bind(r, &in); err != nil {
+		server.BadRequest(w, r)
+		return
+	}
+
+	u, err := a.authenticate(r.Context(), in.Username, in.Password)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidCredentials) {
+			log.Printf("auth: login %q: %v", in.Username, err)
+		}
+		a.emit(r, EventLoginFailed, User{Username: in.Username})
+		a.render(w, r, http.StatusUnauthorized, "login.html", map[string]any{
+			"Title":    "Log in",
+			"Error":    ErrInvalidCredentials.Error(),
+			"Username": in.Username,
+			"Next":     in.Next,
+		})
+		return
+	}
+
+	if _, err := a.sessions.Start(w, r, u.ID, u.HasTOTP()); err != nil {
+		log.Printf("auth: start session: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+
+	if u.HasTOTP() {
+		// EventLogin follows once the second factor is verified
+		http.Redirect(w, r, "/login/totp?next="+url.QueryEscape(in.Next), http.StatusSeeOther)
+		return
+	}
+	a.emit(r, EventLogin, u)
+	http.Redirect(w, r, a.next(in.Next), http.StatusSeeOther)
+}
+
+// totpPage renders the second factor form for a pending session.
+func (a *Auth) totpPage(w http.ResponseWriter, r *http.Request) {
+	if sess, ok := a.sessions.Get(r); !ok || !sess.Pending {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	a.render(w, r, http.StatusOK, "login_totp.html", map[string]any{
+		"Title": "Verification code",
+		"Next":  r.URL.Query().Get("next"),
+	})
+}
+
+// totpLogin verifies the second factor and completes the login.
+func (a *Auth) totpLogin(w http.ResponseWriter, r *http.Request) {
+	sess, ok := a.sessions.Get(r)
+	if !ok || !sess.Pending {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	var in codeForm
+	if err := form.Bind(r, &in); err != nil {
+		server.BadRequest(w, r)
+		return
+	}
+
+	u, ok, retry := a.checkTOTP(r, in.Code)
+	if !ok {
+		a.emit(r, EventLoginFailed, u)
+		if !retry {
+			// Too many invalid codes: start over with the password
+			a.sessions.Destroy(w, r)
+			a.render(w, r, http.StatusUnauthorized, "login.html", map[string]any{
+				"Title": "Log in",
+				"Error": "too many invalid verification codes",
+				"Next":  in.Next,
+			})
+			return
+		}
+		a.render(w, r, http.StatusUnauthorized, "login_totp.html", map[string]any{
+			"Title": "Verification code",
+			"Error": "invalid verification code",
+			"Next":  in.Next,
+		})
+		return
+	}
+
+	if _, err := a.sessions.Start(w, r, u.ID, false); err != nil {
+		log.Printf("auth: start session: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+	a.emit(r, EventLogin, u)
+	http.Redirect(w, r, a.next(in.Next), http.StatusSeeOther)
+}
+
+// logout ends the session.
+func (a *Auth) logout(w http.ResponseWriter, r *http.Request) {
+	if u, ok := UserFrom(r.Context()); ok {
+		a.emit(r, EventLogout, u)
+	}
+	a.sessions.Destroy(w, r)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// registerPage renders the registration form.
+func (a *Auth) registerPage(w http.ResponseWriter, r *http.Request) {
+	a.render(w, r, http.StatusOK, "register.html", map[string]any{"Title": "Create account"})
+}
+
+// register creates an account and logs it in.
+func (a *Auth) register(w http.ResponseWriter, r *http.Request) {
+	var in registerForm
+	if err := form.Bind(r, &in); err != nil {
+		server.BadRequest(w, r)
+		return
+	}
+	in.Username = strings.TrimSpace(in.Username)
+
+	fail := func(code int, msg string) {
+		a.render(w, r, code, "register.html", map[string]any{
+			"Title":    "Create account",
+			"Error":    msg,
+			"Username": in.Username,
+		})
+	}
+
+	switch {
+	case len(in.Username) < 3 || len(in.Username) > 64:
+		fail(http.StatusUnprocessableEntity, "username must be 3 to 64 characters")
+		return
+	case len(in.Password) < a.config.MinPassword:
+		fail(http.StatusUnprocessableEntity, "password is too short")
+		return
+	case in.Password != in.Confirm:
+		fail(http.StatusUnprocessableEntity, "passwords do not match")
+		return
+	}
+
+	hash, err := HashPassword(in.Password)
+	if err != nil {
+		log.Printf("auth: hash password: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+
+	u, err := a.users.Create(r.Context(), User{Username: in.Username, PasswordHash: hash})
+	if errors.Is(err, ErrUserExists) {
+		fail(http.StatusConflict, "username is already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("auth: create user: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+
+	if _, err := a.sessions.Start(w, r, u.ID, false); err != nil {
+		log.Printf("auth: start session: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+	a.emit(r, EventRegistered, u)
+	http.Redirect(w, r, a.next(""), http.StatusSeeOther)
+}
+
+// accountPage shows the logged-in user.
+func (a *Auth) accountPage(w http.ResponseWriter, r *http.Request) {
+	u, _ := UserFrom(r.Context())
+	a.render(w, r, http.StatusOK, "account.html", map[string]any{"Title": "Account", "User": u})
+}
+
+// enrolPage shows a new TOTP secret to add to an authenticator app.
+func (a *Auth) enrolPage(w http.ResponseWriter, r *http.Request) {
+	u, _ := UserFrom(r.Context())
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		log.Printf("auth: totp secret: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+
+	a.render(w, r, http.StatusOK, "account_totp.html", map[string]any{
+		"Title":  "Two-factor authentication",
+		"Secret": secret,
+		"URL":    TOTPURL(a.config.TOTPIssuer, u.Username, secret),
+	})
+}
+
+// enrol enables TOTP once the user proves possession of the secret.
+func (a *Auth) enrol(w http.ResponseWriter, r *http.Request) {
+	u, _ := UserFrom(r.Context())
+
+	var in codeForm
+	if err := form.Bind(r, &in); err != nil {
+		server.BadRequest(w, r)
+		return
+	}
+
+	step, ok := MatchTOTP(in.Secret, in.Code, time.Now())
+	if !ok {
+		a.render(w, r, http.StatusUnprocessableEntity, "account_totp.html", map[string]any{
+			"Title":  "Two-factor authentication",
+			"Error":  "invalid verification code",
+			"Secret": in.Secret,
+			"URL":    TOTPURL(a.config.TOTPIssuer, u.Username, in.Secret),
+		})
+		return
+	}
+
+	u.TOTPSecret = in.Secret
+	u.TOTPStep = step // the enrolment code cannot be used to log in
+	if err := a.users.Update(r.Context(), u); err != nil {
+		log.Printf("auth: enable totp: %v", err)
+		server.InternalServerError(w, r)
+		return
+	}
+	a.emit(r, EventTOTPEnabled, u)
+	http.Redirect(w, r, "/account", http.StatusSeeOther)
+}
+
+/* ---------- helpers ---------- */
+
+// authenticate checks username and password. Unknown users still cost
+// one hash verification so response times do not reveal valid names.
+func (a *Auth) authenticate(ctx context.Context, username, password string) (User, error) {
+	u, err := a.users.ByUsername(ctx, strings.TrimSpace(username))
+	if errors.Is(err, ErrUserNotFound) {
+		_, _ = VerifyPassword(password, dummyHash())
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	ok, err := VerifyPassword(password, u.PasswordHash)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+// checkTOTP verifies the second factor code of a pending session. A
+// code is accepted once (User.TOTPStep); invalid codes are counted on
+// the session, and retry is false once MaxTOTPFailures is reached.
+func (a *Auth) checkTOTP(r *http.Request, code string) (u User, ok, retry bool) {
+	a.totpMu.Lock()
+	defer a.totpMu.Unlock()
+
+	ctx := r.Context()
+	sess, found := a.sessions.Get(r) // failures counted by concurrent requests
+	if !found || !sess.Pending {
+		return User{}, false, false
+	}
+
+	u, err := a.users.ByID(ctx, sess.UserID)
+	if err == nil {
+		if step, valid := MatchTOTP(u.TOTPSecret, code, time.Now()); valid && step > u.TOTPStep {
+			u.TOTPStep = step
+			if err := a.users.Update(ctx, u); err != nil {
+				log.Printf("auth: totp step: %v", err)
+				return u, false, true
+			}
+			return u, true, false
+		}
+	}
+
+	max := a.config.MaxTOTPFailures
+	if max <= 0 {
+		max = 5
+	}
+	sess.Failures++
+	if sess.Failures >= max {
+		if err := a.sessions.store.Delete(ctx, sess.ID); err != nil {
+			log.Printf("auth: delete session: %v", err)
+		}
+		return u, false, false
+	}
+	if err := a.sessions.Save(ctx, sess); err != nil {
+		log.Printf("auth: save session: %v", err)
+	}
+	return u, false, true
+}
+
+// next returns target if it is a local path, otherwise AfterLogin.
+// Backslashes and control characters are rejected anywhere, since
+// browsers treat \ as / and drop tabs and newlines from URLs.
+func (a *Auth) next(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+		return a.config.AfterLogin
+	}
+	if strings.ContainsFunc(target, func(c rune) bool { return c == '\\' || unicode.IsControl(c) }) {
+		return a.config.AfterLogin
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return a.config.AfterLogin
+	}
+	return target
+}
+
+// render renders an HTML view or falls back to a 500 error page.
+func (a *Auth) render(w http.ResponseWriter, r *http.Request, code int, view string, data map[string]any) {
+	if u, ok := UserFrom(r.Context()); ok {
+		data["CurrentUser"] = u
+	}
+
+	buf, err := a.tmpl.RenderToBytesFuncs(view, csrf.FuncMap(r), data)
+	if err != nil {
+		log.Printf("render %s: %v", view, err)
+		server.InternalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// dummyHash returns a valid hash used to equalize timing for unknown users.
+var dummyHash = sync.OnceValue(func() string {
+	h, _ := HashPassword("dummy password")
+	return h
+})
@@ -0,0 +1,53 @@
+package auth
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Domain events published on an optional events.Bus (see SetEvents).
+*/
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Event describes an authentication event.
+type Event struct {
+	UserID     string    `json:"user_id"` // empty for failed logins of unknown users
+	Username   string    `json:"username"`
+	RemoteAddr string    `json:"remote_addr"`
+	RequestID  string    `json:"request_id"`
+	Time       time.Time `json:"time"`
+}
+
+// Topics published by the auth handlers.
+var (
+	EventLogin       = events.NewTopic[Event]("auth.login")
+	EventLoginFailed = events.NewTopic[Event]("auth.login_failed")
+	EventLogout      = events.NewTopic[Event]("auth.logout")
+	EventRegistered  = events.NewTopic[Event]("auth.registered")
+	EventTOTPEnabled = events.NewTopic[Event]("auth.totp_enabled")
+)
+
+// SetEvents sets the bus the handlers publish events on (nil disables).
+func (a *Auth) SetEvents(bus *events.Bus) {
+	a.bus = bus
+}
+
+// emit publishes an event for u if a bus is configured.
+func (a *Auth) emit(r *http.Request, topic events.Topic[Event], u User) {
+	if a.bus == nil {
+		return
+	}
+	events.Publish(r.Context(), a.bus, topic, Event{
+		UserID:     u.ID,
+		Username:   u.Username,
+		RemoteAddr: r.RemoteAddr,
+		RequestID:  middleware.GetRequestID(r.Context()),
+		Time:       time.Now().UTC(),
+	})
+}
@@ -0,0 +1,199 @@
+package auth
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Password hashing.
+
+Summary
+-------
+- Hasher is the pluggable interface for password hashing schemes.
+- Argon2id (RFC 9106, golang.org/x/crypto/argon2) is the default
+  implementation; PBKDF2 (SHA-256, standard library) is available for
+  environments that require a FIPS-approved scheme. Encoded hashes use
+  the PHC string format and carry their parameters:
+
+	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+	$pbkdf2-sha256$i=600000$<salt>$<hash>
+
+- VerifyPassword accepts hashes of both schemes whatever DefaultHasher
+  is, so stored hashes keep working after the default changes.
+*/
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrUnknownHash is returned if an encoded hash uses an unsupported scheme.
+var ErrUnknownHash = errors.New("auth: unknown password hash format")
+
+// Hasher hashes and verifies passwords.
+type Hasher interface {
+	// Hash returns a self-describing encoded hash of password.
+	Hash(password string) (string, error)
+
+	// Verify reports whether password matches the encoded hash.
+	Verify(password, encoded string) (bool, error)
+}
+
+// DefaultHasher is used by HashPassword and VerifyPassword.
+var DefaultHasher Hasher = Argon2id{Time: 3, Memory: 64 * 1024, Threads: 4, SaltLen: 16, KeyLen: 32}
+
+// hashers verify hashes DefaultHasher does not know. Their parameters
+// are read from the encoded hash.
+var hashers = []Hasher{Argon2id{}, PBKDF2{}}
+
+// HashPassword hashes password using DefaultHasher.
+func HashPassword(password string) (string, error) {
+	return DefaultHasher.Hash(password)
+}
+
+// VerifyPassword checks password against an encoded hash using
+// DefaultHasher, or the built-in scheme of the hash if DefaultHasher
+// does not know it.
+func VerifyPassword(password, encoded string) (bool, error) {
+	ok, err := DefaultHasher.Verify(password, encoded)
+	if !errors.Is(err, ErrUnknownHash) {
+		return ok, err
+	}
+	for _, h := range hashers {
+		if ok, err := h.Verify(password, encoded); !errors.Is(err, ErrUnknownHash) {
+			return ok, err
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+// Argon2id is an argon2id password hasher.
+type Argon2id struct {
+	Time    uint32 // passes over the memory
+	Memory  uint32 // memory in KiB
+	Threads uint8  // parallelism
+	SaltLen int    // salt length in bytes
+	KeyLen  uint32 // derived key length in bytes
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Hash returns the PHC-encoded argon2id hash of password.
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, a.KeyLen)
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		a.Memory, a.Time, a.Threads, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// Verify checks password against a PHC-encoded argon2id hash. The
+// parameters are taken from the encoded hash, so older hashes keep
+// working after they are raised.
+func (a Argon2id) Verify(password, encoded string) (bool, error) {
+	rest, ok := strings.CutPrefix(encoded, argon2idPrefix)
+	if !ok {
+		return false, ErrUnknownHash
+	}
+
+	parts := strings.Split(rest, "$")
+	if len(parts) != 4 {
+		return false, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrUnknownHash
+	}
+	var memory, passes uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &memory, &passes, &threads); err != nil || passes == 0 || threads == 0 {
+		return false, ErrUnknownHash
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false, ErrUnknownHash
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false, ErrUnknownHash
+	}
+
+	got := argon2.IDKey([]byte(password), salt, passes, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// PBKDF2 is a PBKDF2-HMAC-SHA256 password hasher.
+type PBKDF2 struct {
+	Iterations int // work factor
+	SaltLen    int // salt length in bytes
+	KeyLen     int // derived key length in bytes
+}
+
+const pbkdf2Prefix = "$pbkdf2-sha256$"
+
+// Hash returns the PHC-encoded PBKDF2 hash of password.
+func (p PBKDF2) Hash(password string) (string, error) {
+	salt := make([]byte, p.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, p.Iterations, p.KeyLen)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%si=%d$%s$%s", pbkdf2Prefix, p.Iterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// Verify checks password against a PHC-encoded PBKDF2 hash. The work
+// factor is taken from the encoded hash, so older hashes keep working
+// after Iterations is raised.
+func (p PBKDF2) Verify(password, encoded string) (bool, error) {
+	rest, ok := strings.CutPrefix(encoded, pbkdf2Prefix)
+	if !ok {
+		return false, ErrUnknownHash
+	}
+
+	parts := strings.Split(rest, "$")
+	if len(parts) != 3 {
+		return false, ErrUnknownHash
+	}
+
+	var iter int
+	if _, err := fmt.Sscanf(parts[0], "i=%d", &iter); err != nil || iter <= 0 {
+		return false, ErrUnknownHash
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return false, ErrUnknownHash
+	}
+	want, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false, ErrUnknownHash
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
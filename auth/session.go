@@ -0,0 +1,140 @@
+package auth
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Server-side sessions.
+
+Summary
+-------
+- The browser holds only a random session ID in an HttpOnly cookie.
+- Session data lives in any cache.Cache[Session] (in-memory by default,
+  Redis for multiple instances).
+- A new session ID is issued on every login (no session fixation).
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/bennof/gobfwebservice/cache"
+)
+
+// Session is the server-side state of a browser session.
+type Session struct {
+	ID       string    `json:"id"`
+	UserID   string    `json:"user_id"`
+	Pending  bool      `json:"pending"`  // password verified, second factor outstanding
+	Failures int       `json:"failures"` // invalid second factor codes
+	Created  time.Time `json:"created"`
+}
+
+// Sessions manages session cookies and their backing store.
+type Sessions struct {
+	config Config
+	store  cache.Cache[Session]
+}
+
+// NewSessions creates a session manager. If store is nil, an in-memory
+// cache is used.
+func NewSessions(store cache.Cache[Session], cfg ...Config) *Sessions {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if store == nil {
+		store = cache.NewMemory[Session](cache.Config{MaxEntries: 100_000})
+	}
+	return &Sessions{config: c, store: store}
+}
+
+// Get returns the session referenced by the request cookie.
+func (s *Sessions) Get(r *http.Request) (Session, bool) {
+	ck, err := r.Cookie(s.config.CookieName)
+	if err != nil || ck.Value == "" {
+		return Session{}, false
+	}
+
+	sess, ok, err := s.store.Get(r.Context(), ck.Value)
+	if err != nil || !ok {
+		return Session{}, false
+	}
+	return sess, true
+}
+
+// Start replaces any existing session with a new one for userID and
+// sets the session cookie.
+func (s *Sessions) Start(w http.ResponseWriter, r *http.Request, userID string, pending bool) (Session, error) {
+	s.Destroy(w, r)
+
+	id, err := sessionID()
+	if err != nil {
+		return Session{}, err
+	}
+
+	sess := Session{ID: id, UserID: userID, Pending: pending, Created: time.Now().UTC()}
+	if err := s.store.Set(r.Context(), id, sess, s.ttl()); err != nil {
+		return Session{}, err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.config.CookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   s.config.SessionTTL,
+		HttpOnly: true,
+		Secure:   s.config.CookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return sess, nil
+}
+
+// Save stores changes to sess, keeping its expiry.
+func (s *Sessions) Save(ctx context.Context, sess Session) error {
+	ttl := s.ttl()
+	if ttl > 0 {
+		ttl -= time.Since(sess.Created)
+		if ttl <= 0 {
+			return s.store.Delete(ctx, sess.ID)
+		}
+	}
+	return s.store.Set(ctx, sess.ID, sess, ttl)
+}
+
+// Destroy deletes the current session and clears the cookie.
+func (s *Sessions) Destroy(w http.ResponseWriter, r *http.Request) {
+	if ck, err := r.Cookie(s.config.CookieName); err == nil && ck.Value != "" {
+		_ = s.store.Delete(context.WithoutCancel(r.Context()), ck.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.config.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.config.CookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ttl returns the session lifetime (negative = no expiry).
+func (s *Sessions) ttl() time.Duration {
+	if s.config.SessionTTL <= 0 {
+		return -1
+	}
+	return time.Duration(s.config.SessionTTL) * time.Second
+}
+
+// sessionID returns a new random, URL-safe session ID.
+func sessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,136 @@
+package auth
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Users and the pluggable user store.
+
+Summary
+-------
+- User holds the account data needed for authentication.
+- UserStore is the persistence interface; implement it for a database.
+- MemoryStore is a concurrency-safe in-memory implementation for
+  development and tests.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned by user stores and the auth handlers.
+var (
+	ErrUserNotFound       = errors.New("auth: user not found")
+	ErrUserExists         = errors.New("auth: user already exists")
+	ErrInvalidCredentials = errors.New("auth: invalid username or password")
+)
+
+// User is an account that can log in.
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	TOTPSecret   string    `json:"-"` // empty if second factor is disabled
+	TOTPStep     int64     `json:"-"` // time step of the last accepted code (see MatchTOTP)
+	Created      time.Time `json:"created"`
+}
+
+// HasTOTP reports whether the second factor is enabled.
+func (u User) HasTOTP() bool {
+	return u.TOTPSecret != ""
+}
+
+// UserStore persists users. Usernames are compared case-insensitively.
+type UserStore interface {
+	ByID(ctx context.Context, id string) (User, error)
+	ByUsername(ctx context.Context, username string) (User, error)
+	Create(ctx context.Context, u User) (User, error) // assigns ID and Created
+	Update(ctx context.Context, u User) error
+}
+
+// MemoryStore is an in-memory, concurrency-safe UserStore.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	byID   map[string]User
+	byName map[string]string // lower-case username -> ID
+}
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{byID: map[string]User{}, byName: map[string]string{}}
+}
+
+// ByID returns the user with the given ID.
+func (s *MemoryStore) ByID(_ context.Context, id string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.byID[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// ByUsername returns the user with the given username.
+func (s *MemoryStore) ByUsername(_ context.Context, username string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byName[strings.ToLower(username)]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return s.byID[id], nil
+}
+
+// Create stores a new user.
+func (s *MemoryStore) Create(_ context.Context, u User) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.ToLower(u.Username)
+	if _, ok := s.byName[name]; ok {
+		return User{}, ErrUserExists
+	}
+
+	id, err := randomID(16)
+	if err != nil {
+		return User{}, err
+	}
+	u.ID = id
+	u.Created = time.Now().UTC()
+
+	s.byID[u.ID] = u
+	s.byName[name] = u.ID
+	return u, nil
+}
+
+// Update replaces an existing user. The username cannot be changed.
+func (s *MemoryStore) Update(_ context.Context, u User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.byID[u.ID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.Username = old.Username
+	s.byID[u.ID] = u
+	return nil
+}
+
+// randomID returns n random bytes, hex-encoded.
+func randomID(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
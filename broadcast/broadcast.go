@@ -0,0 +1,355 @@
+package broadcast
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package broadcast bridges in-memory publish/subscribe to connected
+browsers via Server-Sent Events and WebSockets.
+
+Summary
+-------
+- Handlers publish messages by topic; every connected client subscribed
+  to the topic receives them (SSE: Hub.SSE, WebSocket: Hub.WebSocket).
+- Clients choose topics with the "topic" query parameter
+  (?topic=notes&topic=chat or ?topic=notes,chat).
+- Claims are read per connection (default: middleware.GetBearerClaimsMap);
+  an Authorizer decides which topics a client may join, and PublishTo
+  delivers only to clients whose claims match a Filter.
+- Each client has a bounded buffer. A client that cannot keep up (full
+  buffer) is evicted: its connection is closed instead of blocking the
+  publisher or growing memory.
+- Hub implements lifecycle.Runner: on shutdown all clients are
+  disconnected.
+
+Typical usage:
+
+	hub := broadcast.New(broadcast.Config{Buffer: 32, Heartbeat: 25})
+	m.Add("broadcast", hub)
+
+	srv.Handle("GET /events", middleware.BearerContextMap(parse)(hub.SSE()))
+	srv.Handle("GET /ws", middleware.BearerContextMap(parse)(hub.WebSocket()))
+
+	// in a handler
+	hub.Publish("notes", note)
+	hub.PublishTo("notes", note, broadcast.HasClaim("sub", ownerID))
+
+Note: streaming connections are long-lived; do not wrap these handlers
+with middleware.Timeout.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// ErrClosed is returned when publishing on a closed hub.
+var ErrClosed = errors.New("broadcast: hub closed")
+
+// Config defines client buffering and keep-alive behavior.
+type Config struct {
+	Buffer       int `json:"buffer"`        // messages buffered per client before it is evicted
+	Heartbeat    int `json:"heartbeat"`     // seconds between keep-alives; 0 disables
+	WriteTimeout int `json:"write_timeout"` // seconds allowed for a single write; 0 disables
+	MaxClients   int `json:"max_clients"`   // 0 means unlimited
+}
+
+// DefaultConfig returns settings suitable for browser clients behind
+// typical proxies (which close idle connections after about 60s).
+func DefaultConfig() Config {
+	return Config{
+		Buffer:       64,
+		Heartbeat:    25,
+		WriteTimeout: 10,
+		MaxClients:   10_000,
+	}
+}
+
+// Message is a published event.
+type Message struct {
+	ID    string          `json:"id"`
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Filter selects recipients by their claims (nil if unauthenticated).
+type Filter func(claims map[string]any) bool
+
+// Authorizer reports whether a client with claims may join topic.
+type Authorizer func(claims map[string]any, topic string) bool
+
+// ClaimsFunc extracts the claims of a connecting client.
+type ClaimsFunc func(r *http.Request) map[string]any
+
+// Stats is a snapshot of hub counters.
+type Stats struct {
+	Clients   int    `json:"clients"`
+	Published uint64 `json:"published"`
+	Delivered uint64 `json:"delivered"`
+	Evicted   uint64 `json:"evicted"` // clients dropped because their buffer was full
+	Rejected  uint64 `json:"rejected"`
+}
+
+// Hub keeps track of connected clients and fans out messages.
+type Hub struct {
+	config    Config
+	claims    ClaimsFunc
+	authorize Authorizer
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+
+	published, delivered, evicted, rejected atomic.Uint64
+}
+
+// client is a single connection.
+type client struct {
+	topics map[string]bool
+	claims map[string]any
+	send   chan *Message
+	done   chan struct{} // closed on eviction or hub shutdown
+	once   sync.Once
+}
+
+// stop closes the client's done channel once.
+func (c *client) stop() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// New creates a hub. Claims are read with middleware.GetBearerClaimsMap
+// and every client may join every topic until SetClaims / SetAuthorizer
+// are used.
+func New(cfg ...Config) *Hub {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Buffer <= 0 {
+		c.Buffer = 1
+	}
+
+	return &Hub{
+		config: c,
+		claims: func(r *http.Request) map[string]any {
+			claims, _ := middleware.GetBearerClaimsMap(r.Context())
+			return claims
+		},
+		clients: map[*client]struct{}{},
+	}
+}
+
+// SetClaims replaces the function reading claims from requests.
+// It must be called before the handlers serve requests.
+func (h *Hub) SetClaims(fn ClaimsFunc) {
+	h.claims = fn
+}
+
+// SetAuthorizer restricts which topics clients may join. Requests for
+// forbidden topics are answered with 403. It must be called before the
+// handlers serve requests.
+func (h *Hub) SetAuthorizer(fn Authorizer) {
+	h.authorize = fn
+}
+
+// Publish sends v (JSON encoded) to all clients subscribed to topic.
+func (h *Hub) Publish(topic string, v any) error {
+	return h.PublishTo(topic, v, nil)
+}
+
+// PublishTo sends v (JSON encoded) to all clients subscribed to topic
+// whose claims match filter (nil matches everyone). It never blocks:
+// clients with a full buffer are evicted.
+func (h *Hub) PublishTo(topic string, v any, filter Filter) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return ErrClosed
+	}
+
+	m := &Message{
+		ID:    strconv.FormatUint(h.published.Add(1), 10),
+		Topic: topic,
+		Data:  data,
+	}
+
+	for c := range h.clients {
+		if !c.topics[topic] || (filter != nil && !filter(c.claims)) {
+			continue
+		}
+
+		select {
+		case c.send <- m:
+			h.delivered.Add(1)
+		case <-c.done:
+			// already evicted
+		default:
+			h.evicted.Add(1)
+			c.stop()
+		}
+	}
+	return nil
+}
+
+// Stats returns a snapshot of the hub counters.
+func (h *Hub) Stats() Stats {
+	h.mu.RLock()
+	n := len(h.clients)
+	h.mu.RUnlock()
+
+	return Stats{
+		Clients:   n,
+		Published: h.published.Load(),
+		Delivered: h.delivered.Load(),
+		Evicted:   h.evicted.Load(),
+		Rejected:  h.rejected.Load(),
+	}
+}
+
+// Close disconnects all clients and waits until their handlers returned.
+// Publishing on a closed hub returns ErrClosed.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.closed = true
+	for c := range h.clients {
+		c.stop()
+	}
+	h.mu.Unlock()
+
+	h.wg.Wait()
+}
+
+// Run blocks until ctx is cancelled and then closes the hub.
+// It implements lifecycle.Runner.
+func (h *Hub) Run(ctx context.Context) error {
+	<-ctx.Done()
+	h.Close()
+	return nil
+}
+
+// HasClaim returns a filter matching clients whose claim name equals
+// value (string claims) or contains it (array claims).
+func HasClaim(name, value string) Filter {
+	return func(claims map[string]any) bool {
+		switch v := claims[name].(type) {
+		case string:
+			return v == value
+		case []any:
+			for _, x := range v {
+				if s, ok := x.(string); ok && s == value {
+					return true
+				}
+			}
+		case []string:
+			for _, s := range v {
+				if s == value {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// subscribe validates the requested topics and registers a client.
+// On failure it writes the error response and returns nil.
+func (h *Hub) subscribe(w http.ResponseWriter, r *http.Request) *client {
+	topics := map[string]bool{}
+	for _, v := range r.URL.Query()["topic"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				topics[t] = true
+			}
+		}
+	}
+	if len(topics) == 0 {
+		h.rejected.Add(1)
+		http.Error(w, "missing topic", http.StatusBadRequest)
+		return nil
+	}
+
+	claims := h.claims(r)
+	if h.authorize != nil {
+		for t := range topics {
+			if !h.authorize(claims, t) {
+				h.rejected.Add(1)
+				http.Error(w, "forbidden topic: "+t, http.StatusForbidden)
+				return nil
+			}
+		}
+	}
+
+	c := &client{
+		topics: topics,
+		claims: claims,
+		send:   make(chan *Message, h.config.Buffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		h.rejected.Add(1)
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return nil
+	}
+	if h.config.MaxClients > 0 && len(h.clients) >= h.config.MaxClients {
+		h.rejected.Add(1)
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "too many clients", http.StatusServiceUnavailable)
+		return nil
+	}
+
+	h.clients[c] = struct{}{}
+	h.wg.Add(1)
+	return c
+}
+
+// unsubscribe removes a client after its handler finished.
+func (h *Hub) unsubscribe(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+
+	c.stop()
+	h.wg.Done()
+}
+
+// heartbeat returns a ticker channel for keep-alives (nil if disabled)
+// and a function stopping it.
+func (h *Hub) heartbeat() (<-chan time.Time, func()) {
+	if h.config.Heartbeat <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTicker(time.Duration(h.config.Heartbeat) * time.Second)
+	return t.C, t.Stop
+}
+
+// deadline returns the write deadline for the next write (zero if disabled).
+func (h *Hub) deadline() time.Time {
+	if h.config.WriteTimeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(h.config.WriteTimeout) * time.Second)
+}
@@ -0,0 +1,69 @@
+package broadcast
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Server-Sent Events transport.
+
+Each message is written as one event:
+
+	id: 42
+	event: notes
+	data: {"title":"..."}
+
+Keep-alives are sent as comment lines (": ping"). Evicted clients are
+disconnected; browsers reconnect automatically (EventSource).
+*/
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// SSE returns a handler streaming messages as text/event-stream.
+func (h *Hub) SSE() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+
+		c := h.subscribe(w, r)
+		if c == nil {
+			return
+		}
+		defer h.unsubscribe(c)
+
+		hdr := w.Header()
+		hdr.Set("Content-Type", "text/event-stream")
+		hdr.Set("Cache-Control", "no-cache")
+		hdr.Set("X-Accel-Buffering", "no") // disable nginx response buffering
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return // streaming not supported by the writer chain
+		}
+
+		beat, stop := h.heartbeat()
+		defer stop()
+
+		for {
+			var err error
+			select {
+			case <-r.Context().Done():
+				return
+			case <-c.done:
+				return
+			case <-beat:
+				_ = rc.SetWriteDeadline(h.deadline())
+				_, err = fmt.Fprint(w, ": ping\n\n")
+			case m := <-c.send:
+				_ = rc.SetWriteDeadline(h.deadline())
+				_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", m.ID, m.Topic, m.Data)
+			}
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				return
+			}
+		}
+	})
+}
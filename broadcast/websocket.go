@@ -0,0 +1,254 @@
+package broadcast
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Minimal server-side WebSocket transport (RFC 6455), standard library only.
+
+Messages are sent as text frames containing the JSON encoded Message
+({"id":"42","topic":"notes","data":{...}}). The connection is
+send-only: frames from the client are read to answer pings and close
+requests, data frames are discarded. Keep-alives are sent as pings.
+*/
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RFC 6455 constants.
+const (
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+
+	maxControlPayload = 125
+	maxClientFrame    = 64 << 10 // larger client frames close the connection
+)
+
+// errProtocol is returned for unmasked or oversized client frames.
+var errProtocol = errors.New("broadcast: websocket protocol error")
+
+// WebSocket returns a handler upgrading the connection to a WebSocket
+// and streaming messages as text frames.
+func (h *Hub) WebSocket() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if !headerContains(r.Header, "Connection", "upgrade") ||
+			!headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+			http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Sec-WebSocket-Version") != "13" {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+			http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+			return
+		}
+
+		c := h.subscribe(w, r)
+		if c == nil {
+			return
+		}
+		defer h.unsubscribe(c)
+
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			http.Error(w, "websocket not supported", http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+
+		// Hijacked connections may keep deadlines set by the server
+		_ = conn.SetDeadline(time.Time{})
+
+		ws := &wsConn{conn: conn, rw: brw, hub: h}
+		if err := ws.handshake(key); err != nil {
+			return
+		}
+
+		// Reader: answers pings, detects close and disconnects
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			ws.readLoop()
+		}()
+
+		beat, stop := h.heartbeat()
+		defer stop()
+
+		for {
+			var err error
+			select {
+			case <-closed:
+				return
+			case <-c.done:
+				_ = ws.write(opClose, closePayload(1001, "going away"))
+				return
+			case <-beat:
+				err = ws.write(opPing, nil)
+			case m := <-c.send:
+				var b []byte
+				if b, err = json.Marshal(m); err == nil {
+					err = ws.write(opText, b)
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	})
+}
+
+// wsConn is a hijacked WebSocket connection.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	hub  *Hub
+	mu   sync.Mutex // serializes frame writes (writer loop and pongs)
+}
+
+// handshake writes the 101 Switching Protocols response.
+func (ws *wsConn) handshake(key string) error {
+	sum := sha1.Sum([]byte(key + wsGUID))
+
+	_ = ws.conn.SetWriteDeadline(ws.hub.deadline())
+	ws.rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	return ws.rw.Flush()
+}
+
+// write sends a single unmasked frame.
+func (ws *wsConn) write(op byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	var hdr [10]byte
+	hdr[0] = 0x80 | op // FIN
+	n := 2
+	switch l := len(payload); {
+	case l <= 125:
+		hdr[1] = byte(l)
+	case l <= 0xFFFF:
+		hdr[1] = 126
+		binary.BigEndian.PutUint16(hdr[2:], uint16(l))
+		n = 4
+	default:
+		hdr[1] = 127
+		binary.BigEndian.PutUint64(hdr[2:], uint64(l))
+		n = 10
+	}
+
+	_ = ws.conn.SetWriteDeadline(ws.hub.deadline())
+	ws.rw.Write(hdr[:n])
+	ws.rw.Write(payload)
+	return ws.rw.Flush()
+}
+
+// readLoop reads client frames until the connection fails or the client
+// sends a close frame.
+func (ws *wsConn) readLoop() {
+	for {
+		op, payload, err := ws.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch op {
+		case opClose:
+			_ = ws.write(opClose, payload[:min(len(payload), 2)])
+			return
+		case opPing:
+			if ws.write(opPong, payload) != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame reads a single masked client frame. Payloads of data frames
+// are discarded; control frame payloads are returned.
+func (ws *wsConn) readFrame() (byte, []byte, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(ws.rw, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+
+	op := hdr[0] & 0x0F
+	masked := hdr[1]&0x80 != 0
+	length := uint64(hdr[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(ws.rw, b[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(ws.rw, b[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(b[:])
+	}
+
+	if !masked || length > maxClientFrame || (op >= opClose && length > maxControlPayload) {
+		_ = ws.write(opClose, closePayload(1002, "protocol error"))
+		return 0, nil, errProtocol
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if op < opClose {
+		_, err := io.CopyN(io.Discard, ws.rw, int64(length))
+		return op, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
+
+// closePayload builds a close frame payload.
+func closePayload(code uint16, reason string) []byte {
+	b := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(b, code)
+	return append(b, reason...)
+}
+
+// headerContains reports whether a comma-separated header contains token
+// (case-insensitive).
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
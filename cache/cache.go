@@ -0,0 +1,105 @@
+package cache
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package cache provides a generic key/value cache interface with an
+in-memory LRU+TTL implementation and a Redis-backed implementation.
+
+Summary
+-------
+- Cache[V] is the common interface (context-aware, string keys).
+- Memory[V] is a size-bounded LRU with per-entry TTL (memory.go).
+- Redis[V] stores values in Redis using a codec (redis.go); it needs no
+  third-party client.
+- Loader[V] adds singleflight loading on top of any Cache: concurrent
+  misses for the same key trigger a single load (loader.go).
+- Invalidator[V] adds invalidation by key, prefix and tag, also driven
+  by events published on an events.Bus (invalidate.go).
+- All implementations expose Stats for metrics.
+
+Typical usage:
+
+	c := cache.NewMemory[[]byte](cache.Config{MaxEntries: 1000, TTL: 60})
+	pages := cache.NewLoader[[]byte](c)
+
+	html, err := pages.Get(ctx, "/about", 0, func(ctx context.Context) ([]byte, error) {
+		return render("about.html")
+	})
+*/
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Cache is the common interface of all cache implementations.
+type Cache[V any] interface {
+	// Get returns the value for key and whether it was found.
+	Get(ctx context.Context, key string) (V, bool, error)
+
+	// Set stores a value. A ttl of 0 uses the cache's default TTL;
+	// a negative ttl stores the value without expiry.
+	Set(ctx context.Context, key string, value V, ttl time.Duration) error
+
+	// Delete removes a key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Stats returns a snapshot of the cache counters.
+	Stats() Stats
+}
+
+// Config defines the configuration of a cache.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	MaxEntries int `json:"max_entries"` // Maximum number of entries (memory cache); 0 is unbounded
+	TTL        int `json:"ttl"`         // Default time-to-live in seconds; 0 means no expiry
+}
+
+// DefaultConfig returns a conservative default cache configuration.
+func DefaultConfig() Config {
+	return Config{
+		MaxEntries: 1000,
+		TTL:        300,
+	}
+}
+
+// Stats is a snapshot of cache counters.
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`   // entries removed to respect MaxEntries
+	Expirations uint64 `json:"expirations"` // entries removed because their TTL passed
+	Loads       uint64 `json:"loads"`       // loader calls (Loader only)
+	LoadErrors  uint64 `json:"load_errors"` // failed loader calls (Loader only)
+	Entries     int    `json:"entries"`     // current number of entries (-1 if unknown)
+}
+
+// counters holds the atomic counters shared by implementations.
+type counters struct {
+	hits, misses, evictions, expirations atomic.Uint64
+}
+
+// snapshot converts the counters into Stats.
+func (c *counters) snapshot(entries int) Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Entries:     entries,
+	}
+}
+
+// expiry computes the absolute expiry for a ttl (zero time = never).
+func expiry(ttl, def time.Duration) time.Time {
+	if ttl == 0 {
+		ttl = def
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,198 @@
+package cache
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Cache invalidation by key, prefix or tag.
+
+Summary
+-------
+- Invalidator wraps a Cache and removes entries selected by an
+  Invalidation: exact keys, key prefixes and tags.
+- Tags are attached when storing (SetTagged) and indexed in process; an
+  entry may carry several tags (e.g. "notes" and "note:42").
+- Prefixes need a cache implementing PrefixDeleter (Memory and Redis do).
+- Invalidations can be triggered directly from handlers or published on
+  an events.Bus (topic Invalidated); Subscribe applies them, so a content
+  update purges every cache listening on the bus.
+
+Typical usage:
+
+	pages := cache.NewInvalidator[[]byte](cache.NewMemory[[]byte]())
+	pages.Subscribe(bus)
+
+	// after a note was updated
+	events.Publish(ctx, bus, cache.Invalidated, cache.Invalidation{Tags: []string{"note:" + id}})
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bennof/gobfwebservice/events"
+)
+
+// Invalidation selects cache entries to remove.
+type Invalidation struct {
+	Keys     []string `json:"keys,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// Invalidated is the topic on which invalidations are published.
+var Invalidated = events.NewTopic[Invalidation]("cache.invalidate")
+
+// PrefixDeleter is implemented by caches that can remove keys by prefix.
+type PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// ErrNoPrefixDelete is returned when invalidating a prefix on a cache
+// that does not implement PrefixDeleter.
+var ErrNoPrefixDelete = errors.New("cache: prefix invalidation not supported")
+
+// Invalidator is a Cache with invalidation by key, prefix and tag.
+type Invalidator[V any] struct {
+	Cache[V]
+
+	mu   sync.Mutex
+	tags map[string]map[string]struct{} // tag -> keys
+	keys map[string][]string            // key -> tags
+}
+
+// NewInvalidator wraps c.
+func NewInvalidator[V any](c Cache[V]) *Invalidator[V] {
+	return &Invalidator[V]{
+		Cache: c,
+		tags:  map[string]map[string]struct{}{},
+		keys:  map[string][]string{},
+	}
+}
+
+// Set stores a value without tags (replacing previous tags of key).
+func (i *Invalidator[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) error {
+	return i.SetTagged(ctx, key, value, ttl)
+}
+
+// SetTagged stores a value and tags it.
+func (i *Invalidator[V]) SetTagged(ctx context.Context, key string, value V, ttl time.Duration, tags ...string) error {
+	if err := i.Cache.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.untag(key)
+	if len(tags) == 0 {
+		return nil
+	}
+	i.keys[key] = tags
+	for _, t := range tags {
+		if i.tags[t] == nil {
+			i.tags[t] = map[string]struct{}{}
+		}
+		i.tags[t][key] = struct{}{}
+	}
+	return nil
+}
+
+// Delete removes a key and its tags.
+func (i *Invalidator[V]) Delete(ctx context.Context, key string) error {
+	i.mu.Lock()
+	i.untag(key)
+	i.mu.Unlock()
+	return i.Cache.Delete(ctx, key)
+}
+
+// Invalidate removes the selected entries and returns how many were
+// removed (keys and tagged entries are counted whether or not they were
+// still cached). All parts are attempted; errors are joined.
+func (i *Invalidator[V]) Invalidate(ctx context.Context, inv Invalidation) (int, error) {
+	keys := append([]string(nil), inv.Keys...)
+
+	i.mu.Lock()
+	for _, t := range inv.Tags {
+		for k := range i.tags[t] {
+			keys = append(keys, k)
+		}
+	}
+	i.mu.Unlock()
+
+	n := 0
+	var errs []error
+	for _, k := range keys {
+		if err := i.Delete(ctx, k); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+
+	for _, p := range inv.Prefixes {
+		pd, ok := i.Cache.(PrefixDeleter)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrNoPrefixDelete, p))
+			continue
+		}
+		m, err := pd.DeletePrefix(ctx, p)
+		n += m
+		if err != nil {
+			errs = append(errs, err)
+		}
+		i.mu.Lock()
+		for k := range i.keys {
+			if strings.HasPrefix(k, p) {
+				i.untag(k)
+			}
+		}
+		i.mu.Unlock()
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// InvalidateKeys removes the given keys.
+func (i *Invalidator[V]) InvalidateKeys(ctx context.Context, keys ...string) error {
+	_, err := i.Invalidate(ctx, Invalidation{Keys: keys})
+	return err
+}
+
+// InvalidatePrefix removes all keys starting with prefix.
+func (i *Invalidator[V]) InvalidatePrefix(ctx context.Context, prefix string) error {
+	_, err := i.Invalidate(ctx, Invalidation{Prefixes: []string{prefix}})
+	return err
+}
+
+// InvalidateTags removes all entries carrying one of the tags.
+func (i *Invalidator[V]) InvalidateTags(ctx context.Context, tags ...string) error {
+	_, err := i.Invalidate(ctx, Invalidation{Tags: tags})
+	return err
+}
+
+// Subscribe applies invalidations published on bus (synchronously, so
+// the purge is done when Publish returns).
+func (i *Invalidator[V]) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, Invalidated, func(ctx context.Context, inv Invalidation) {
+		if _, err := i.Invalidate(ctx, inv); err != nil {
+			log.Printf("cache: invalidate: %v", err)
+		}
+	})
+}
+
+// untag removes key from the tag index. The caller must hold mu.
+func (i *Invalidator[V]) untag(key string) {
+	for _, t := range i.keys[key] {
+		delete(i.tags[t], key)
+		if len(i.tags[t]) == 0 {
+			delete(i.tags, t)
+		}
+	}
+	delete(i.keys, key)
+}
@@ -0,0 +1,100 @@
+package cache
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Singleflight loading on top of any Cache.
+
+Summary
+-------
+- Loader.Get returns cached values and loads missing ones via a callback.
+- Concurrent misses for the same key share a single load (singleflight),
+  protecting backends from thundering herds after expiry or deploys.
+- Load counts and errors are added to the cache Stats.
+*/
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoadFunc loads the value for a cache miss.
+type LoadFunc[V any] func(ctx context.Context) (V, error)
+
+// Loader wraps a Cache with singleflight loading.
+type Loader[V any] struct {
+	cache Cache[V]
+
+	mu     sync.Mutex
+	flight map[string]*call[V]
+
+	loads, loadErrors atomic.Uint64
+}
+
+// call is an in-flight load shared by concurrent callers.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewLoader wraps c with singleflight loading.
+func NewLoader[V any](c Cache[V]) *Loader[V] {
+	return &Loader[V]{cache: c, flight: map[string]*call[V]{}}
+}
+
+// Cache returns the underlying cache.
+func (l *Loader[V]) Cache() Cache[V] {
+	return l.cache
+}
+
+// Get returns the cached value for key or loads, stores and returns it.
+// Failed loads are not cached. Cache read/write errors fall back to the
+// loader so a broken cache backend degrades to uncached operation.
+func (l *Loader[V]) Get(ctx context.Context, key string, ttl time.Duration, load LoadFunc[V]) (V, error) {
+	if v, ok, err := l.cache.Get(ctx, key); err == nil && ok {
+		return v, nil
+	}
+
+	l.mu.Lock()
+	if c, ok := l.flight[key]; ok {
+		l.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.value, c.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+
+	c := &call[V]{done: make(chan struct{})}
+	l.flight[key] = c
+	l.mu.Unlock()
+
+	l.loads.Add(1)
+	c.value, c.err = load(ctx)
+	if c.err != nil {
+		l.loadErrors.Add(1)
+	} else {
+		_ = l.cache.Set(ctx, key, c.value, ttl)
+	}
+
+	l.mu.Lock()
+	delete(l.flight, key)
+	l.mu.Unlock()
+	close(c.done)
+
+	return c.value, c.err
+}
+
+// Stats returns the underlying cache stats including load counters.
+func (l *Loader[V]) Stats() Stats {
+	s := l.cache.Stats()
+	s.Loads = l.loads.Load()
+	s.LoadErrors = l.loadErrors.Load()
+	return s
+}
@@ -0,0 +1,179 @@
+package cache
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+In-memory LRU cache with per-entry TTL.
+
+Summary
+-------
+- Bounded by Config.MaxEntries; the least recently used entry is evicted.
+- Expired entries are removed lazily on access and by Purge.
+- Safe for concurrent use (single mutex, O(1) operations).
+*/
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory LRU cache with TTL support.
+type Memory[V any] struct {
+	config Config
+
+	mu    sync.Mutex
+	ll    *list.List               // front = most recently used
+	items map[string]*list.Element // key -> element holding *entry[V]
+
+	stats counters
+}
+
+// entry is a single cached value.
+type entry[V any] struct {
+	key     string
+	value   V
+	expires time.Time // zero means no expiry
+}
+
+// NewMemory creates an in-memory cache using the provided configuration.
+// If no configuration is supplied, DefaultConfig() is used.
+func NewMemory[V any](cfg ...Config) *Memory[V] {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return &Memory[V]{
+		config: c,
+		ll:     list.New(),
+		items:  map[string]*list.Element{},
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+func (m *Memory[V]) Get(_ context.Context, key string) (V, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var zero V
+	el, ok := m.items[key]
+	if !ok {
+		m.stats.misses.Add(1)
+		return zero, false, nil
+	}
+
+	e := el.Value.(*entry[V])
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		m.removeElement(el)
+		m.stats.expirations.Add(1)
+		m.stats.misses.Add(1)
+		return zero, false, nil
+	}
+
+	m.ll.MoveToFront(el)
+	m.stats.hits.Add(1)
+	return e.value, true, nil
+}
+
+// Set stores a value, evicting the least recently used entry if needed.
+func (m *Memory[V]) Set(_ context.Context, key string, value V, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exp := expiry(ttl, time.Duration(m.config.TTL)*time.Second)
+
+	if el, ok := m.items[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value = value
+		e.expires = exp
+		m.ll.MoveToFront(el)
+		return nil
+	}
+
+	m.items[key] = m.ll.PushFront(&entry[V]{key: key, value: value, expires: exp})
+
+	for m.config.MaxEntries > 0 && m.ll.Len() > m.config.MaxEntries {
+		m.removeElement(m.ll.Back())
+		m.stats.evictions.Add(1)
+	}
+	return nil
+}
+
+// Delete removes a key.
+func (m *Memory[V]) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.removeElement(el)
+	}
+	return nil
+}
+
+// DeletePrefix removes all keys starting with prefix and returns how
+// many were removed.
+func (m *Memory[V]) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for key, el := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.removeElement(el)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Len returns the current number of entries (including expired ones
+// not yet purged).
+func (m *Memory[V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// Purge removes all expired entries and returns how many were removed.
+func (m *Memory[V]) Purge() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for el := m.ll.Back(); el != nil; {
+		prev := el.Prev()
+		e := el.Value.(*entry[V])
+		if !e.expires.IsZero() && now.After(e.expires) {
+			m.removeElement(el)
+			m.stats.expirations.Add(1)
+			n++
+		}
+		el = prev
+	}
+	return n
+}
+
+// Clear removes all entries.
+func (m *Memory[V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ll.Init()
+	m.items = map[string]*list.Element{}
+}
+
+// Stats returns a snapshot of the cache counters.
+func (m *Memory[V]) Stats() Stats {
+	return m.stats.snapshot(m.Len())
+}
+
+// removeElement unlinks an element. The caller must hold mu.
+func (m *Memory[V]) removeElement(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.items, el.Value.(*entry[V]).key)
+}
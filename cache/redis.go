@@ -0,0 +1,359 @@
+package cache
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Redis-backed cache.
+
+Summary
+-------
+- Implements Cache[V] on top of Redis using a minimal RESP client over
+  net (no third-party dependency).
+- Values are encoded with a Codec (JSON by default).
+- Keys are namespaced with RedisConfig.Prefix.
+- Connections are pooled; broken connections are discarded.
+
+Only the commands needed by the cache are supported
+(AUTH, SELECT, GET, SET ... PX, DEL, SCAN, PING).
+*/
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedisConfig defines the configuration of a Redis cache.
+type RedisConfig struct {
+	Addr        string `json:"addr"`         // host:port
+	Password    string `json:"password"`     // optional AUTH password
+	DB          int    `json:"db"`           // database index (SELECT)
+	Prefix      string `json:"prefix"`       // key prefix, e.g. "app:"
+	TTL         int    `json:"ttl"`          // default time-to-live in seconds; 0 means no expiry
+	PoolSize    int    `json:"pool_size"`    // maximum idle connections
+	DialTimeout int    `json:"dial_timeout"` // connect timeout in seconds
+}
+
+// DefaultRedisConfig returns a default configuration for a local Redis.
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Addr:        "127.0.0.1:6379",
+		Prefix:      "cache:",
+		TTL:         300,
+		PoolSize:    8,
+		DialTimeout: 5,
+	}
+}
+
+// Codec encodes values for storage in Redis.
+type Codec[V any] interface {
+	Marshal(V) ([]byte, error)
+	Unmarshal([]byte, *V) error
+}
+
+// JSONCodec encodes values as JSON.
+type JSONCodec[V any] struct{}
+
+// Marshal encodes v as JSON.
+func (JSONCodec[V]) Marshal(v V) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON into v.
+func (JSONCodec[V]) Unmarshal(b []byte, v *V) error { return json.Unmarshal(b, v) }
+
+// ErrRedis is returned for error replies from the Redis server.
+var ErrRedis = errors.New("redis error")
+
+// Redis is a Cache backed by a Redis server.
+type Redis[V any] struct {
+	config RedisConfig
+	codec  Codec[V]
+	pool   chan *redisConn
+
+	stats counters
+}
+
+// NewRedis creates a Redis cache. Connections are opened lazily.
+// If codec is nil, JSONCodec is used.
+func NewRedis[V any](cfg RedisConfig, codec Codec[V]) *Redis[V] {
+	if codec == nil {
+		codec = JSONCodec[V]{}
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 1
+	}
+
+	return &Redis[V]{
+		config: cfg,
+		codec:  codec,
+		pool:   make(chan *redisConn, cfg.PoolSize),
+	}
+}
+
+// Get returns the decoded value for key.
+func (c *Redis[V]) Get(ctx context.Context, key string) (V, bool, error) {
+	var zero V
+
+	reply, err := c.do(ctx, "GET", c.config.Prefix+key)
+	if err != nil {
+		return zero, false, err
+	}
+	if reply == nil {
+		c.stats.misses.Add(1)
+		return zero, false, nil
+	}
+
+	b, ok := reply.([]byte)
+	if !ok {
+		return zero, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+
+	var v V
+	if err := c.codec.Unmarshal(b, &v); err != nil {
+		return zero, false, err
+	}
+
+	c.stats.hits.Add(1)
+	return v, true, nil
+}
+
+// Set encodes and stores a value.
+func (c *Redis[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) error {
+	b, err := c.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"SET", c.config.Prefix + key, string(b)}
+	if exp := expiry(ttl, time.Duration(c.config.TTL)*time.Second); !exp.IsZero() {
+		ms := time.Until(exp).Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+
+	_, err = c.do(ctx, args...)
+	return err
+}
+
+// Delete removes a key.
+func (c *Redis[V]) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", c.config.Prefix+key)
+	return err
+}
+
+// DeletePrefix removes all keys starting with prefix and returns how
+// many were removed. It walks the keyspace with SCAN, so it does not
+// block the server but is not atomic: keys written meanwhile may survive.
+func (c *Redis[V]) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	match := globEscaper.Replace(c.config.Prefix+prefix) + "*"
+	cursor, n := "0", 0
+	for {
+		reply, err := c.do(ctx, "SCAN", cursor, "MATCH", match, "COUNT", "500")
+		if err != nil {
+			return n, err
+		}
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 2 {
+			return n, fmt.Errorf("redis: unexpected SCAN reply %T", reply)
+		}
+		next, _ := parts[0].([]byte)
+		keys, _ := parts[1].([]any)
+
+		if len(keys) > 0 {
+			args := []string{"DEL"}
+			for _, k := range keys {
+				if b, ok := k.([]byte); ok {
+					args = append(args, string(b))
+				}
+			}
+			reply, err := c.do(ctx, args...)
+			if err != nil {
+				return n, err
+			}
+			if d, ok := reply.(int64); ok {
+				n += int(d)
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return n, nil
+		}
+	}
+}
+
+// globEscaper escapes Redis glob pattern characters.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// Ping checks the connection to the Redis server.
+func (c *Redis[V]) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Stats returns a snapshot of the cache counters.
+// Entries is -1 because the size is not tracked locally.
+func (c *Redis[V]) Stats() Stats {
+	return c.stats.snapshot(-1)
+}
+
+// Close closes all idle connections.
+func (c *Redis[V]) Close() error {
+	for {
+		select {
+		case rc := <-c.pool:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs a single command on a pooled connection.
+func (c *Redis[V]) do(ctx context.Context, args ...string) (any, error) {
+	rc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if dl, ok := ctx.Deadline(); ok {
+		rc.conn.SetDeadline(dl)
+	} else {
+		rc.conn.SetDeadline(time.Time{})
+	}
+
+	reply, err := rc.do(args...)
+	if err != nil && !errors.Is(err, ErrRedis) {
+		// Protocol or network failure: connection state is unknown.
+		rc.conn.Close()
+		return nil, err
+	}
+
+	c.put(rc)
+	return reply, err
+}
+
+// get returns an idle connection or dials a new one.
+func (c *Redis[V]) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case rc := <-c.pool:
+		return rc, nil
+	default:
+	}
+
+	d := net.Dialer{Timeout: time.Duration(c.config.DialTimeout) * time.Second}
+	conn, err := d.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if c.config.Password != "" {
+		if _, err := rc.do("AUTH", c.config.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := rc.do("SELECT", strconv.Itoa(c.config.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+// put returns a connection to the pool or closes it if the pool is full.
+func (c *Redis[V]) put(rc *redisConn) {
+	select {
+	case c.pool <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+// redisConn is a single RESP connection.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// do writes a command and reads its reply.
+func (rc *redisConn) do(args ...string) (any, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, a := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(a)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, a...)
+		buf = append(buf, '\r', '\n')
+	}
+
+	if _, err := rc.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return rc.read()
+}
+
+// read parses a single RESP reply. Bulk strings are returned as []byte,
+// nil bulk strings as nil, integers as int64 and arrays as []any.
+func (rc *redisConn) read() (any, error) {
+	line, err := rc.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	body := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, fmt.Errorf("%w: %s", ErrRedis, body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rc.r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		out := make([]any, n)
+		for i := range out {
+			if out[i], err = rc.read(); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+}
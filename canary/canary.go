@@ -0,0 +1,171 @@
+package canary
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package canary splits traffic between a stable and a canary handler.
+
+Summary
+-------
+- Split routes a configurable percentage of requests to an alternate
+  handler (e.g. a new implementation or a reverse proxy to an upstream
+  from Proxy); everything else goes to the stable handler.
+- Assignment is sticky: each client gets a random bucket (0-99) stored
+  in a cookie and is routed to the canary while bucket < Percent. Raising
+  the percentage only moves clients from stable to canary, never back
+  and forth.
+- A request header (e.g. X-Canary: always|never) or a cookie value can
+  force the decision for testing.
+- SplitFrom reads the configuration from a middleware.Reloadable, so a
+  rollout can be advanced with a config reload.
+- The chosen variant is exposed in the X-Canary response header and via
+  FromContext for logging.
+
+Typical usage:
+
+	next, _ := canary.Proxy("http://127.0.0.1:9090")
+	mux.Handle("/api/", stack(canary.Split(stable, next, canary.Config{Percent: 5})))
+*/
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Config defines the configuration of a traffic split.
+type Config struct {
+	Percent      float64 `json:"percent"`        // share of clients routed to the canary (0-100)
+	Cookie       string  `json:"cookie"`         // sticky bucket cookie; empty disables stickiness
+	CookieMaxAge int     `json:"cookie_max_age"` // seconds
+	CookieSecure bool    `json:"cookie_secure"`
+	Header       string  `json:"header"` // override header: "always" or "never"
+}
+
+// DefaultConfig returns a configuration routing nothing to the canary.
+func DefaultConfig() Config {
+	return Config{
+		Percent:      0,
+		Cookie:       "canary_bucket",
+		CookieMaxAge: 30 * 24 * 3600,
+		CookieSecure: true,
+		Header:       "X-Canary",
+	}
+}
+
+// Variant names.
+const (
+	Stable = "stable"
+	Canary = "canary"
+)
+
+// variantKey stores the selected variant.
+var variantKey = ctxutil.NewKey[string]("canary-variant")
+
+// FromContext returns the variant (Stable or Canary) chosen for the
+// request, or "" if the request did not pass a split.
+func FromContext(ctx context.Context) string {
+	return variantKey.Value(ctx)
+}
+
+// Split routes requests between stable and canary according to cfg.
+func Split(stable, canary http.Handler, cfg ...Config) http.Handler {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	return SplitFrom(stable, canary, middleware.NewReloadable(c))
+}
+
+// SplitFrom is like Split but reads the configuration on every request.
+func SplitFrom(stable, canary http.Handler, src *middleware.Reloadable[Config]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		variant := choose(w, r, src.Load())
+
+		w.Header().Set("X-Canary", variant)
+		r = r.WithContext(variantKey.Set(r.Context(), variant))
+
+		if variant == Canary {
+			canary.ServeHTTP(w, r)
+			return
+		}
+		stable.ServeHTTP(w, r)
+	})
+}
+
+// Middleware returns a middleware sending the canary share of requests
+// to canary instead of the wrapped handler.
+func Middleware(canary http.Handler, cfg ...Config) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return Split(next, canary, cfg...)
+	}
+}
+
+// Proxy returns a reverse proxy to upstream (scheme://host[:port][/base]).
+func Proxy(upstream string) (http.Handler, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	p := httputil.NewSingleHostReverseProxy(u)
+	base := p.Director
+	p.Director = func(r *http.Request) {
+		base(r)
+		r.Host = u.Host
+	}
+	return p, nil
+}
+
+// choose selects the variant for r and (re)sets the bucket cookie.
+func choose(w http.ResponseWriter, r *http.Request, c Config) string {
+	if c.Header != "" {
+		switch strings.ToLower(r.Header.Get(c.Header)) {
+		case "always", Canary:
+			return Canary
+		case "never", Stable:
+			return Stable
+		}
+	}
+
+	bucket := -1
+	if c.Cookie != "" {
+		if ck, err := r.Cookie(c.Cookie); err == nil {
+			if b, err := strconv.Atoi(ck.Value); err == nil && b >= 0 && b < 100 {
+				bucket = b
+			}
+		}
+	}
+
+	if bucket < 0 {
+		if c.Percent <= 0 {
+			// Nothing to split; do not hand out cookies
+			return Stable
+		}
+		bucket = rand.IntN(100)
+		if c.Cookie != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     c.Cookie,
+				Value:    strconv.Itoa(bucket),
+				Path:     "/",
+				MaxAge:   c.CookieMaxAge,
+				HttpOnly: true,
+				Secure:   c.CookieSecure,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+	}
+
+	if float64(bucket) < c.Percent {
+		return Canary
+	}
+	return Stable
+}
@@ -0,0 +1,238 @@
+package challenge
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package challenge lets clients over the rate limit prove they are not a
+bot instead of being turned away.
+
+Summary
+-------
+- ProofOfWork implements middleware.Challenge: clients over their limit
+  get a challenge string and have to find a counter n such that
+  SHA-256(challenge + "." + n) starts with Difficulty zero bits. The
+  solution goes into a cookie; middleware.RateLimiter then grants the
+  client a fresh budget, once per solution and window.
+- Browsers receive a small page (status 429) solving the challenge in
+  JavaScript and reloading; other clients receive JSON with the
+  challenge and solve it themselves.
+- Challenges are signed (HMAC-SHA256 over expiry and client IP), so no
+  state is kept and a solution works only for the client it was issued
+  to, until it expires.
+- A CAPTCHA service fits the same interface: serve its widget in
+  ServeHTTP and validate its token in Solved.
+
+Example config:
+
+	"challenge": { "enabled": true, "difficulty": 16, "ttl": 3600, "secret": "..." }
+
+Typical usage:
+
+	limiter := middleware.NewRateLimiter(rates)
+	limiter.SetChallenge(challenge.NewProofOfWork(cfg.Challenge))
+
+Example JSON response:
+
+	{"error":"rate limit exceeded","challenge":"1792137600.Xk2...","difficulty":16,"cookie":"pow"}
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/bits"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config defines the proof-of-work challenge.
+type Config struct {
+	Enabled    bool   `json:"enabled"`    // Challenge clients over the rate limit instead of rejecting them
+	Difficulty int    `json:"difficulty"` // Leading zero bits of the hash (each bit doubles the work)
+	TTL        int    `json:"ttl"`        // Seconds a challenge can be solved and redeemed
+	Cookie     string `json:"cookie"`     // Cookie carrying the solution
+	Secret     string `json:"secret"`     // HMAC key; empty uses a random key per process
+}
+
+// DefaultConfig returns a disabled challenge of 16 bits (about 65000
+// hashes, a second or two in a browser), valid for an hour.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:    false,
+		Difficulty: 16,
+		TTL:        3600,
+		Cookie:     "pow",
+	}
+}
+
+// ProofOfWork is a stateless proof-of-work challenge.
+type ProofOfWork struct {
+	config Config
+	key    []byte
+	now    func() time.Time
+}
+
+// NewProofOfWork creates a challenge using the provided configuration,
+// or DefaultConfig if none is given.
+func NewProofOfWork(cfg ...Config) *ProofOfWork {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	d := DefaultConfig()
+	if c.Difficulty <= 0 || c.Difficulty > 32 {
+		c.Difficulty = d.Difficulty
+	}
+	if c.TTL <= 0 {
+		c.TTL = d.TTL
+	}
+	if c.Cookie == "" {
+		c.Cookie = d.Cookie
+	}
+
+	key := []byte(c.Secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+		log.Println("challenge: no secret configured, using a random key (solutions end with the process)")
+	}
+	return &ProofOfWork{config: c, key: key, now: time.Now}
+}
+
+// Solved reports whether r carries a valid, unexpired solution for its
+// client IP. The id is the solved challenge.
+func (p *ProofOfWork) Solved(r *http.Request) (string, bool) {
+	ck, err := r.Cookie(p.config.Cookie)
+	if err != nil {
+		return "", false
+	}
+
+	// "<expiry>.<signature>.<counter>"
+	i := strings.LastIndexByte(ck.Value, '.')
+	if i < 0 {
+		return "", false
+	}
+	ch := ck.Value[:i]
+	exp, sig, ok := strings.Cut(ch, ".")
+	if !ok {
+		return "", false
+	}
+
+	expiry, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || p.now().Unix() > expiry {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(p.sign(exp, clientIP(r)))) {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(ck.Value))
+	if leadingZeros(sum[:]) < p.config.Difficulty {
+		return "", false
+	}
+	return ch, true
+}
+
+// ServeHTTP answers with a new challenge for the client: a page solving
+// it for browsers, JSON otherwise. The status is 429 either way.
+func (p *ProofOfWork) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	exp := strconv.FormatInt(p.now().Add(time.Duration(p.config.TTL)*time.Second).Unix(), 10)
+	ch := exp + "." + p.sign(exp, clientIP(r))
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", "1")
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error":      "rate limit exceeded",
+			"challenge":  ch,
+			"difficulty": p.config.Difficulty,
+			"cookie":     p.config.Cookie,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusTooManyRequests)
+	page.Execute(w, map[string]any{
+		"Challenge":  ch,
+		"Difficulty": p.config.Difficulty,
+		"Cookie":     p.config.Cookie,
+		"TTL":        p.config.TTL,
+	})
+}
+
+// sign returns the signature binding a challenge to its expiry and
+// client IP.
+func (p *ProofOfWork) sign(exp, ip string) string {
+	m := hmac.New(sha256.New, p.key)
+	m.Write([]byte(exp + "|" + ip))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil)[:16])
+}
+
+// clientIP returns the IP of RemoteAddr, as used by the rate limiter.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// leadingZeros counts the leading zero bits of b.
+func leadingZeros(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != 0 {
+			return n + bits.LeadingZeros8(c)
+		}
+		n += 8
+	}
+	return n
+}
+
+// page solves the challenge in the browser, stores the solution and
+// reloads. crypto.subtle needs HTTPS (or localhost).
+var page = template.Must(template.New("challenge").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Checking your browser</title></head>
+<body>
+<p>Too many requests from your network. Checking your browser, this takes a moment&hellip;</p>
+<noscript><p>Please enable JavaScript or try again later.</p></noscript>
+<script>
+(async () => {
+  const challenge = {{.Challenge}}, difficulty = {{.Difficulty}};
+  const enc = new TextEncoder();
+  const zeros = (h) => {
+    let n = 0;
+    for (const b of h) {
+      if (b) return n + Math.clz32(b) - 24;
+      n += 8;
+    }
+    return n;
+  };
+  for (let i = 0; ; i++) {
+    const value = challenge + "." + i;
+    const h = new Uint8Array(await crypto.subtle.digest("SHA-256", enc.encode(value)));
+    if (zeros(h) >= difficulty) {
+      document.cookie = {{.Cookie}} + "=" + value + "; path=/; max-age=" + {{.TTL}} + "; SameSite=Lax";
+      location.reload();
+      return;
+    }
+  }
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,206 @@
+package client
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package client provides an outbound HTTP client: the twin of the
+middleware stack for requests the service makes to other services.
+
+Summary
+-------
+- Client wraps http.Client with a composable RoundTripper chain
+  (Transport, analogous to middleware.Middleware).
+- Per-request timeouts (Config.Timeout, or WithTimeout per call).
+- Retries with exponential backoff and full jitter for idempotent
+  requests on network errors and retryable status codes; Retry-After
+  is honored.
+- Propagates the request ID (X-Request-ID) and trace headers
+  (traceparent, tracestate) from the incoming request context; Propagate
+  adds the same to any other http.Client.
+- Logs one line per outbound request via the global logger.
+- Stats exposes request, error and retry counters.
+
+Typical usage:
+
+	api := client.New(client.DefaultConfig())
+
+	// in a handler: ctx carries request ID and trace headers
+	req, _ := http.NewRequestWithContext(r.Context(), "GET", "http://users/api/me", nil)
+	resp, err := api.Do(req)
+
+The server side must run middleware.RequestID and client.Capture so the
+incoming values are available in the context.
+*/
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Config defines the outbound client behavior.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	Timeout       int      `json:"timeout"`         // Per-request timeout in seconds including retries (0 = none)
+	MaxRetries    int      `json:"max_retries"`     // Retries after the first attempt
+	BackoffBase   int      `json:"backoff_base_ms"` // Initial backoff in milliseconds
+	BackoffMax    int      `json:"backoff_max_ms"`  // Maximum backoff in milliseconds (also caps Retry-After)
+	RetryStatuses []int    `json:"retry_statuses"`  // Status codes that trigger a retry
+	TraceHeaders  []string `json:"trace_headers"`   // Incoming headers propagated to outbound requests
+	Log           bool     `json:"log"`             // Log one line per outbound request
+}
+
+// DefaultConfig returns a conservative default client configuration.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:       10,
+		MaxRetries:    2,
+		BackoffBase:   100,
+		BackoffMax:    2000,
+		RetryStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		TraceHeaders:  []string{"traceparent", "tracestate"},
+		Log:           true,
+	}
+}
+
+// Transport defines an outbound middleware (RoundTripper decorator).
+type Transport func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Stats is a snapshot of client counters.
+type Stats struct {
+	Requests  uint64 `json:"requests"` // logical requests (Do calls)
+	Attempts  uint64 `json:"attempts"` // network attempts including retries
+	Retries   uint64 `json:"retries"`
+	Errors    uint64 `json:"errors"`     // requests failing with a transport error
+	Status5xx uint64 `json:"status_5xx"` // final responses with a 5xx status
+}
+
+// stats holds the atomic counters of a Client.
+type stats struct {
+	requests, attempts, retries, errors, status5xx atomic.Uint64
+}
+
+// Client is an instrumented HTTP client.
+type Client struct {
+	config Config
+	http   *http.Client
+	stats  *stats
+}
+
+// New creates a client using the provided configuration
+// (see DefaultConfig). Extra transports
+// wrap the base transport (first is outermost) inside retries, so they
+// run once per attempt.
+func New(cfg Config, transports ...Transport) *Client {
+	st := &stats{}
+
+	var rt http.RoundTripper = http.DefaultTransport
+	for i := len(transports) - 1; i >= 0; i-- {
+		rt = transports[i](rt)
+	}
+	rt = count(st)(rt)
+	rt = retry(cfg, st)(rt)
+	rt = propagate(cfg.TraceHeaders)(rt)
+	if cfg.Log {
+		rt = logging(rt)
+	}
+
+	return &Client{
+		config: cfg,
+		http:   &http.Client{Transport: rt},
+		stats:  st,
+	}
+}
+
+// HTTPClient returns the underlying *http.Client (e.g. for SDKs that
+// accept one). Its requests do not get the per-request timeout.
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// Do sends a request, applying the configured timeout unless the
+// context already carries an earlier deadline.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	c.stats.requests.Add(1)
+
+	ctx := req.Context()
+	if c.config.Timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(c.config.Timeout)*time.Second)
+			req = req.WithContext(ctx)
+
+			resp, err := c.http.Do(req)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+	}
+
+	return c.http.Do(req)
+}
+
+// Get issues a GET request with the given context.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Stats returns a snapshot of the client counters.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:  c.stats.requests.Load(),
+		Attempts:  c.stats.attempts.Load(),
+		Retries:   c.stats.retries.Load(),
+		Errors:    c.stats.errors.Load(),
+		Status5xx: c.stats.status5xx.Load(),
+	}
+}
+
+/* ---------- incoming context ---------- */
+
+// traceKey stores captured incoming trace headers.
+var traceKey = ctxutil.NewKey[http.Header]("client-trace")
+
+// Capture is a server middleware that stores the given incoming headers
+// (default: traceparent, tracestate) in the request context so outbound
+// requests made with that context propagate them.
+func Capture(headers ...string) middleware.Middleware {
+	if len(headers) == 0 {
+		headers = DefaultConfig().TraceHeaders
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := http.Header{}
+			for _, name := range headers {
+				if v := r.Header.Get(name); v != "" {
+					h.Set(name, v)
+				}
+			}
+			if len(h) > 0 {
+				r = r.WithContext(traceKey.Set(r.Context(), h))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
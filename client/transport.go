@@ -0,0 +1,189 @@
+package client
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Built-in transports: retries, header propagation, logging and counters.
+*/
+
+import (
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// retry retries idempotent requests on transport errors and retryable
+// status codes with exponential backoff and full jitter.
+func retry(cfg Config, st *stats) Transport {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			canRetry := cfg.MaxRetries > 0 && idempotent(req) &&
+				(req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+			for attempt := 0; ; attempt++ {
+				resp, err := next.RoundTrip(req)
+
+				if !canRetry || attempt >= cfg.MaxRetries || req.Context().Err() != nil || !shouldRetry(cfg, resp, err) {
+					return resp, err
+				}
+
+				wait := backoff(cfg, attempt, resp)
+				if resp != nil {
+					// Drain so the connection can be reused
+					_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+					resp.Body.Close()
+				}
+
+				t := time.NewTimer(wait)
+				select {
+				case <-req.Context().Done():
+					t.Stop()
+					return nil, req.Context().Err()
+				case <-t.C:
+				}
+
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+				st.retries.Add(1)
+			}
+		})
+	}
+}
+
+// Propagate wraps rt (http.DefaultTransport if nil) so that requests
+// forward the request ID and the captured trace headers of their context,
+// e.g. for the *http.Client of a third-party SDK.
+func Propagate(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return propagate(DefaultConfig().TraceHeaders)(rt)
+}
+
+// propagate copies the request ID and captured trace headers from the
+// request context onto the outbound request.
+func propagate(headers []string) Transport {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			rid := middleware.GetRequestID(ctx)
+			trace := traceKey.Value(ctx)
+
+			if rid == "" && len(trace) == 0 {
+				return next.RoundTrip(req)
+			}
+
+			// RoundTrippers must not modify the caller's request
+			req = req.Clone(ctx)
+			if rid != "" && req.Header.Get("X-Request-ID") == "" {
+				req.Header.Set("X-Request-ID", rid)
+			}
+			for _, name := range headers {
+				if v := trace.Get(name); v != "" && req.Header.Get(name) == "" {
+					req.Header.Set(name, v)
+				}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// logging logs one line per outbound request (after all retries).
+func logging(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		dur := time.Since(start)
+
+		rid := middleware.GetRequestID(req.Context())
+		if err != nil {
+			log.Printf("client %s %s error=%v %s rid=%s", req.Method, req.URL.Redacted(), err, dur, rid)
+		} else {
+			log.Printf("client %s %s %d %s rid=%s", req.Method, req.URL.Redacted(), resp.StatusCode, dur, rid)
+		}
+		return resp, err
+	})
+}
+
+// count updates the per-attempt counters.
+func count(st *stats) Transport {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			st.attempts.Add(1)
+			resp, err := next.RoundTrip(req)
+			switch {
+			case err != nil:
+				st.errors.Add(1)
+			case resp.StatusCode >= 500:
+				st.status5xx.Add(1)
+			}
+			return resp, err
+		})
+	}
+}
+
+// idempotent reports whether a request may be sent more than once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry reports whether the attempt failed in a retryable way.
+func shouldRetry(cfg Config, resp *http.Response, err error) bool {
+	if err != nil {
+		// Timeouts are final: the next attempt would likely time out too
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return false
+		}
+		return true
+	}
+	return slices.Contains(cfg.RetryStatuses, resp.StatusCode)
+}
+
+// backoff returns the wait before the next attempt: Retry-After if the
+// server sent one, otherwise a random duration in [0, base*2^attempt].
+func backoff(cfg Config, attempt int, resp *http.Response) time.Duration {
+	maxWait := time.Duration(cfg.BackoffMax) * time.Millisecond
+	if resp != nil {
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+			return min(time.Duration(s)*time.Second, maxWait)
+		}
+	}
+
+	d := (time.Duration(cfg.BackoffBase) * time.Millisecond) << attempt
+	if d <= 0 || d > maxWait {
+		d = maxWait
+	}
+	return rand.N(d + 1)
+}
+
+// cancelBody releases the per-request timeout once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+// Close closes the body and cancels the request context.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
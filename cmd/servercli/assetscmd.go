@@ -0,0 +1,46 @@
+package main
+
+/*
+Asset bundling for the "assets" command.
+
+Summary
+-------
+- Builds the CSS/JS bundles configured in the "assets" config section
+  and writes the fingerprinted files and manifest.json, e.g. as a
+  deployment step. serve builds them at startup as well.
+*/
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bennof/gobfwebservice/assets"
+)
+
+func runAssets(args []string) {
+	fs := flag.NewFlagSet("assets", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	fs.Parse(args)
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+	cfg := CFG.Get()
+
+	if len(cfg.Assets.Bundles) == 0 {
+		fmt.Println("No bundles configured (assets.bundles)")
+		return
+	}
+
+	a, err := assets.Build(cfg.Assets)
+	if err != nil {
+		fatal(err)
+	}
+
+	for name, file := range a.Manifest() {
+		sri, _ := a.Integrity(name)
+		fmt.Printf("  bundle %s -> %s (%s)\n", name, filepath.Join(cfg.Assets.OutDir, file), sri)
+	}
+	fmt.Printf("Built %d bundles in %s\n", len(cfg.Assets.Bundles), cfg.Assets.OutDir)
+}
@@ -0,0 +1,204 @@
+package main
+
+/*
+Load testing for the "bench" command.
+
+Summary
+-------
+- Fires requests at a target URL from a configurable number of workers,
+  either a fixed request count or for a fixed duration.
+- Reports throughput, status code distribution, transport errors and
+  latency percentiles.
+- Handy for verifying rate-limit and timeout settings of a deployed service.
+*/
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// benchResult is the outcome of a single request.
+type benchResult struct {
+	status  int // 0 on transport errors
+	latency time.Duration
+	err     error
+}
+
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	target := fs.String("url", "", "target URL")
+	method := fs.String("method", http.MethodGet, "HTTP method")
+	body := fs.String("body", "", "request body")
+	concurrency := fs.Int("c", 10, "number of concurrent workers")
+	requests := fs.Int("n", 1000, "total number of requests (ignored if -d is set)")
+	duration := fs.Duration("d", 0, "run for a fixed duration instead of -n requests")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-request timeout")
+	var headers stringList
+	fs.Var(&headers, "H", "request header as 'Name: value' (repeatable)")
+	fs.Parse(args)
+
+	if *target == "" {
+		fmt.Println("usage: bench -url URL [-c 10] [-n 1000 | -d 30s] [-method GET] [-H 'Name: value'] [-body data]")
+		os.Exit(1)
+	}
+
+	hdr := http.Header{}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			fatal(fmt.Errorf("invalid header %q", h))
+		}
+		hdr.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: *concurrency,
+		},
+	}
+
+	ctx := context.Background()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	// ------------------------------------------------------------
+	// Workers
+	// ------------------------------------------------------------
+	var (
+		issued  atomic.Int64
+		mu      sync.Mutex
+		results []benchResult
+		wg      sync.WaitGroup
+	)
+
+	start := time.Now()
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if *duration == 0 && issued.Add(1) > int64(*requests) {
+					return
+				}
+
+				res := benchOnce(ctx, client, *method, *target, *body, hdr)
+				if ctx.Err() != nil && res.err != nil {
+					return // aborted at the end of the run
+				}
+
+				mu.Lock()
+				results = append(results, res)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	printBenchReport(results, elapsed)
+}
+
+// benchOnce performs a single request and drains the response body.
+func benchOnce(ctx context.Context, client *http.Client, method, url, body string, hdr http.Header) benchResult {
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		return benchResult{err: err}
+	}
+	req.Header = hdr.Clone()
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return benchResult{latency: time.Since(start), err: err}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return benchResult{status: resp.StatusCode, latency: time.Since(start)}
+}
+
+// printBenchReport prints throughput, status distribution and latency percentiles.
+func printBenchReport(results []benchResult, elapsed time.Duration) {
+	total := len(results)
+	if total == 0 {
+		fmt.Println("no requests completed")
+		return
+	}
+
+	statuses := map[int]int{}
+	errs := map[string]int{}
+	latencies := make([]time.Duration, 0, total)
+
+	for _, r := range results {
+		if r.err != nil {
+			errs[r.err.Error()]++
+			continue
+		}
+		statuses[r.status]++
+		latencies = append(latencies, r.latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	failed := 0
+	for code, n := range statuses {
+		if code >= 400 {
+			failed += n
+		}
+	}
+	for _, n := range errs {
+		failed += n
+	}
+
+	fmt.Printf("Requests:     %d in %s (%.1f req/s)\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+	fmt.Printf("Error rate:   %.2f%% (status >= 400 or transport error)\n", 100*float64(failed)/float64(total))
+
+	fmt.Println("Status codes:")
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("  %d: %d\n", code, statuses[code])
+	}
+
+	if len(errs) > 0 {
+		fmt.Println("Errors:")
+		for msg, n := range errs {
+			fmt.Printf("  %d x %s\n", n, msg)
+		}
+	}
+
+	if len(latencies) > 0 {
+		fmt.Println("Latency:")
+		for _, p := range []float64{50, 90, 95, 99} {
+			fmt.Printf("  p%-4g %s\n", p, percentile(latencies, p))
+		}
+		fmt.Printf("  max   %s\n", latencies[len(latencies)-1])
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies (nearest rank).
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
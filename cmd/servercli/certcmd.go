@@ -0,0 +1,128 @@
+package main
+
+/*
+Self-signed certificate generation for the "cert" command.
+
+Summary
+-------
+- Generates a self-signed certificate and private key (PEM) for local TLS testing.
+- Hosts may be DNS names or IP addresses and end up as subject alternative names.
+- Supports ECDSA (P-256) and RSA keys and a configurable validity period.
+*/
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+func runCert(args []string) {
+	fs := flag.NewFlagSet("cert", flag.ExitOnError)
+	hosts := fs.String("hosts", "localhost,127.0.0.1,::1", "comma-separated DNS names and IP addresses")
+	validFor := fs.Duration("valid-for", 365*24*time.Hour, "certificate validity period")
+	keyType := fs.String("key", "ecdsa", "key type: ecdsa or rsa")
+	rsaBits := fs.Int("rsa-bits", 2048, "RSA key size")
+	certOut := fs.String("cert-out", "cert.pem", "certificate output file")
+	keyOut := fs.String("key-out", "key.pem", "private key output file")
+	fs.Parse(args)
+
+	// ------------------------------------------------------------
+	// Key
+	// ------------------------------------------------------------
+	var (
+		priv crypto.Signer
+		err  error
+	)
+	switch strings.ToLower(*keyType) {
+	case "ecdsa":
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa":
+		priv, err = rsa.GenerateKey(rand.Reader, *rsaBits)
+	default:
+		err = fmt.Errorf("unknown key type: %s", *keyType)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	// ------------------------------------------------------------
+	// Certificate
+	// ------------------------------------------------------------
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		fatal(err)
+	}
+
+	now := time.Now()
+	tmpl := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"gobfwebservice development"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(*validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	// RSA keys are also used for key encipherment
+	if _, ok := priv.(*rsa.PrivateKey); ok {
+		tmpl.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	for _, h := range strings.Split(*hosts, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	if len(tmpl.DNSNames) > 0 {
+		tmpl.Subject.CommonName = tmpl.DNSNames[0]
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, priv.Public(), priv)
+	if err != nil {
+		fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		fatal(err)
+	}
+
+	// ------------------------------------------------------------
+	// Write files
+	// ------------------------------------------------------------
+	if err := writePEM(*certOut, "CERTIFICATE", der, 0644); err != nil {
+		fatal(err)
+	}
+	if err := writePEM(*keyOut, "PRIVATE KEY", keyDER, 0600); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("Certificate written to %s, key written to %s (valid until %s)\n",
+		*certOut, *keyOut, tmpl.NotAfter.Format(time.RFC3339))
+}
+
+// writePEM writes a single PEM block to path with the given permissions.
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	b := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	return os.WriteFile(path, b, perm)
+}
@@ -0,0 +1,118 @@
+package main
+
+/*
+Configuration loading and the "config" command.
+
+Summary
+-------
+- loadConfig resolves the effective configuration in a fixed order:
+  defaults, config file, environment variables, -set flags.
+- The source (file, number of environment and -set overrides) is part
+  of the startup report of serve.
+- "config show" prints the effective configuration as JSON with
+  secrets redacted, so operators can see what serve will actually use.
+*/
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/config"
+	"github.com/bennof/gobfwebservice/example"
+)
+
+// source describes where the configuration loaded by loadConfig came
+// from, e.g. "config.json + 2 env (APP_*) + 1 -set".
+func (f *configFlags) source() string {
+	s := *f.file
+	if n := len(f.env); n > 0 {
+		s += fmt.Sprintf(" + %d env (%s_*)", n, *f.envPrefix)
+	}
+	if n := len(f.sets); n > 0 {
+		s += fmt.Sprintf(" + %d -set", n)
+	}
+	return s
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// configFlags holds the flags shared by all commands that load the config.
+type configFlags struct {
+	file      *string
+	envPrefix *string
+	sets      stringList
+
+	env []string // key paths overridden from the environment by loadConfig
+}
+
+// addConfigFlags registers the config loading flags on fs.
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	f := &configFlags{
+		file:      fs.String("config", "config.json", "path to config file"),
+		envPrefix: fs.String("env-prefix", "APP", "prefix for environment overrides (e.g. APP_SERVER_PORT)"),
+	}
+	fs.Var(&f.sets, "set", "override a config value, e.g. -set server.port=9090 (repeatable)")
+	return f
+}
+
+// loadConfig resolves the effective configuration into c:
+// defaults, then the config file, then environment, then -set flags.
+func loadConfig(f *configFlags, c *config.Config[example.ExampleConfig]) error {
+	*c.Get() = defaultConfig("example/templates")
+
+	if err := c.Load(*f.file); err != nil {
+		return err
+	}
+
+	env, err := c.ApplyEnv(*f.envPrefix)
+	if err != nil {
+		return err
+	}
+	f.env = env
+
+	for _, s := range f.sets {
+		path, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("invalid -set %q (want key=value)", s)
+		}
+		if err := c.Set(path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runConfig(args []string) {
+	if len(args) < 1 || args[0] != "show" {
+		fmt.Println("usage: config show [-config file] [-env-prefix APP] [-set key=value]")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("config show", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	fs.Parse(args[1:])
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+
+	m, err := CFG.Redacted()
+	if err != nil {
+		fatal(err)
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("# effective configuration (file: %s)\n%s\n", CFG.Filename(), b)
+}
@@ -0,0 +1,114 @@
+package main
+
+/*
+Deployment file generation for the "deploy" command.
+
+Summary
+-------
+- Emits a systemd service unit and/or a minimal multi-stage Dockerfile.
+- Both are tailored to the current configuration (port, config path,
+  template folder) and the given service user and paths.
+- Templates are embedded and rendered with [[ ]] delimiters.
+*/
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+//go:embed deploy/*.tmpl
+var deployFS embed.FS
+
+// deployData is passed to the deployment templates.
+type deployData struct {
+	Name      string // service and binary name
+	User      string // system user running the service
+	Binary    string // absolute binary path (systemd)
+	WorkDir   string // working directory
+	Config    string // config file path
+	Templates string // template folder
+	Package   string // Go package to build (Dockerfile)
+	Port      int    // listen port
+}
+
+func runDeploy(args []string) {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	name := fs.String("name", "bfwebservice", "service and binary name")
+	user := fs.String("user", "www-data", "system user for the systemd unit")
+	workDir := fs.String("workdir", "/opt/bfwebservice", "working directory of the service")
+	pkg := fs.String("pkg", "./cmd/servercli", "Go package to build in the Dockerfile")
+	systemd := fs.Bool("systemd", false, "emit <name>.service")
+	docker := fs.Bool("docker", false, "emit Dockerfile")
+	out := fs.String("out", ".", "output directory")
+	fs.Parse(args)
+
+	if !*systemd && !*docker {
+		*systemd, *docker = true, true
+	}
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+	cfg := CFG.Get()
+
+	data := deployData{
+		Name:      *name,
+		User:      *user,
+		Binary:    filepath.Join("/usr/local/bin", *name),
+		WorkDir:   *workDir,
+		Config:    *cf.file,
+		Templates: cfg.TemplateFolder.Folder,
+		Package:   *pkg,
+		Port:      cfg.Server.Port,
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		fatal(err)
+	}
+
+	if *systemd {
+		// The unit references the config relative to the working directory
+		unit := data
+		if !filepath.IsAbs(unit.Config) {
+			unit.Config = filepath.Join(unit.WorkDir, unit.Config)
+		}
+		writeDeployFile("service.tmpl", filepath.Join(*out, *name+".service"), unit)
+	}
+
+	if *docker {
+		writeDeployFile("Dockerfile.tmpl", filepath.Join(*out, "Dockerfile"), data)
+	}
+
+	if cfg.Server.Host == "localhost" || cfg.Server.Host == "127.0.0.1" {
+		fmt.Printf("Note: server.host is %q; the service will not be reachable from outside.\n", cfg.Server.Host)
+	}
+}
+
+// writeDeployFile renders an embedded deployment template to target.
+func writeDeployFile(name, target string, data deployData) {
+	b, err := deployFS.ReadFile("deploy/" + name)
+	if err != nil {
+		fatal(err)
+	}
+
+	tpl, err := template.New(name).Delims("[[", "]]").Parse(string(b))
+	if err != nil {
+		fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		fatal(err)
+	}
+
+	if err := os.WriteFile(target, buf.Bytes(), 0644); err != nil {
+		fatal(err)
+	}
+	fmt.Printf("  create %s\n", target)
+}
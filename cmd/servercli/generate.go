@@ -0,0 +1,161 @@
+package main
+
+/*
+Code generation for the "generate" command.
+
+Summary
+-------
+- "generate handler <name>" emits a handler stub (config struct,
+  Default…Config, constructor, method dispatch).
+- "generate middleware <name>" emits a middleware stub (config struct,
+  Default…Config, variadic constructor, context key and getter).
+- Stubs follow the conventions of this module so downstream code stays consistent.
+*/
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed generate/*.tmpl
+var generateFS embed.FS
+
+// generateData is passed to the generator templates.
+type generateData struct {
+	Name    string // exported Go identifier, e.g. UserProfile
+	Package string // target package name
+}
+
+func runGenerate(args []string) {
+	if len(args) < 1 {
+		fmt.Println("usage: generate handler|middleware [-out dir] [-pkg name] <name>")
+		os.Exit(1)
+	}
+
+	kind := args[0]
+	if kind != "handler" && kind != "middleware" {
+		fatal(fmt.Errorf("unknown generator: %s", kind))
+	}
+
+	fs := flag.NewFlagSet("generate "+kind, flag.ExitOnError)
+	out := fs.String("out", ".", "output directory")
+	pkg := fs.String("pkg", "", "package name (default: output directory name)")
+	force := fs.Bool("force", false, "overwrite an existing file")
+	fs.Parse(args[1:])
+
+	if fs.NArg() != 1 {
+		fmt.Printf("usage: generate %s [-out dir] [-pkg name] <name>\n", kind)
+		os.Exit(1)
+	}
+
+	data := generateData{
+		Name:    goIdentifier(fs.Arg(0)),
+		Package: *pkg,
+	}
+	if data.Name == "" {
+		fatal(fmt.Errorf("invalid name: %q", fs.Arg(0)))
+	}
+	if data.Package == "" {
+		data.Package = packageName(*out)
+	}
+
+	src, err := renderGenerated(kind, data)
+	if err != nil {
+		fatal(err)
+	}
+
+	target := filepath.Join(*out, snakeCase(data.Name)+".go")
+	if _, err := os.Stat(target); err == nil && !*force {
+		fatal(fmt.Errorf("file %s already exists (use -force)", target))
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		fatal(err)
+	}
+	if err := os.WriteFile(target, src, 0644); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("Generated %s %s in %s\n", kind, data.Name, target)
+}
+
+// renderGenerated executes the generator template for kind and gofmts it.
+func renderGenerated(kind string, data generateData) ([]byte, error) {
+	b, err := generateFS.ReadFile("generate/" + kind + ".go.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := template.New(kind).Delims("[[", "]]").Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// goIdentifier converts names like "user-profile" or "user_profile"
+// into an exported Go identifier ("UserProfile").
+func goIdentifier(name string) string {
+	var b strings.Builder
+	upper := true
+
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || (unicode.IsDigit(r) && b.Len() > 0):
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		default:
+			upper = true
+		}
+	}
+
+	return b.String()
+}
+
+// snakeCase converts an identifier like "UserProfile" into "user_profile".
+func snakeCase(name string) string {
+	var b strings.Builder
+
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// packageName derives a package name from the output directory.
+func packageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "main"
+	}
+
+	name := strings.ToLower(goIdentifier(filepath.Base(abs)))
+	if name == "" {
+		return "main"
+	}
+	return name
+}
@@ -0,0 +1,744 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/bennof/gobfwebservice/acl"
+	"github.com/bennof/gobfwebservice/assets"
+	"github.com/bennof/gobfwebservice/audit"
+	"github.com/bennof/gobfwebservice/auth"
+	"github.com/bennof/gobfwebservice/challenge"
+	"github.com/bennof/gobfwebservice/config"
+	"github.com/bennof/gobfwebservice/csrf"
+	"github.com/bennof/gobfwebservice/debuginfo"
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/example"
+	"github.com/bennof/gobfwebservice/jwt"
+	"github.com/bennof/gobfwebservice/keys"
+	"github.com/bennof/gobfwebservice/lifecycle"
+	"github.com/bennof/gobfwebservice/logging"
+	"github.com/bennof/gobfwebservice/metrics"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/mirror"
+	"github.com/bennof/gobfwebservice/module"
+	"github.com/bennof/gobfwebservice/openapi"
+	"github.com/bennof/gobfwebservice/quotas"
+	"github.com/bennof/gobfwebservice/recorder"
+	"github.com/bennof/gobfwebservice/redirects"
+	"github.com/bennof/gobfwebservice/render"
+	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/server/health"
+	"github.com/bennof/gobfwebservice/storage"
+	"github.com/bennof/gobfwebservice/templates"
+	"github.com/bennof/gobfwebservice/timewindow"
+)
+
+var CFG config.Config[example.ExampleConfig]
+
+func main() {
+	// A command is required as the first argument.
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd := os.Args[1]
+	args := os.Args[2:]
+
+	// Dispatch subcommands explicitly.
+	switch cmd {
+	case "init-config":
+		runInitConfig(args)
+
+	case "serve":
+		runServer(args)
+
+	case "new":
+		runNew(args)
+
+	case "generate":
+		runGenerate(args)
+
+	case "config":
+		runConfig(args)
+
+	case "static-gen":
+		runStaticGen(args)
+
+	case "openapi":
+		runOpenAPI(args)
+
+	case "routes":
+		runRoutes(args)
+
+	case "token":
+		runToken(args)
+
+	case "deploy":
+		runDeploy(args)
+
+	case "cert":
+		runCert(args)
+
+	case "migrate-config":
+		runMigrateConfig(args)
+
+	case "bench":
+		runBench(args)
+
+	case "replay":
+		runReplay(args)
+
+	case "assets":
+		runAssets(args)
+
+	case "reload-templates":
+		runReloadTemplates(args)
+
+	default:
+		fmt.Printf("unknown command: %s\n\n", cmd)
+		usage()
+		os.Exit(1)
+	}
+}
+
+// usage prints a short help text describing available commands.
+func usage() {
+	fmt.Print(`servercli commands:
+
+  serve         -config config.json [-env-prefix APP] [-set key=value] [-dev] [-check]
+
+  init-config   -out config.json
+
+  new           [-module path] <dir>
+
+  generate      handler|middleware [-out dir] [-pkg name] <name>
+
+  config show   -config config.json [-env-prefix APP] [-set key=value]
+
+  static-gen    -config config.json -out dist [-data dir] [-static dir]
+
+  openapi       -config config.json [-out openapi.json]
+
+  routes        -config config.json [-json]
+
+  token         -config config.json [-claim key=value] [-ttl 1h]
+
+  deploy        -config config.json [-systemd] [-docker] [-user name] [-out dir]
+
+  cert          [-hosts localhost,127.0.0.1] [-key ecdsa|rsa] [-valid-for 8760h]
+
+  migrate-config -in old.json [-out new.json]
+
+  bench         -url URL [-c 10] [-n 1000 | -d 30s] [-H 'Name: value']
+
+  replay        -in requests.jsonl [-target URL] [-path /prefix] [-dry]
+
+  assets        -config config.json
+
+  reload-templates -config config.json [-url https://host:port] [-insecure]
+`)
+}
+
+// fatal terminates the program on unrecoverable errors.
+//
+// In a CLI context this is acceptable and keeps the control flow simple.
+func fatal(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func runInitConfig(args []string) {
+	// ------------------------------------------------------------------
+	// CLI flag
+	// ------------------------------------------------------------------
+	fs := flag.NewFlagSet("init-config", flag.ExitOnError)
+	cfgPath := fs.String("out", "config.json", "output config file")
+	fs.Parse(args)
+
+	fmt.Println("Initializing default configuration...")
+
+	// Obtain a mutable reference to the internal config
+	cfg := CFG.Get()
+
+	// ------------------------------------------------------------------
+	// Build default configuration
+	// ------------------------------------------------------------------
+	*cfg = defaultConfig("example/templates")
+
+	// ------------------------------------------------------------------
+	// Write file
+	// ------------------------------------------------------------------
+	// Persist configuration to disk
+	if err := CFG.SaveAs(*cfgPath); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("Configuration written to %s\n", *cfgPath)
+}
+
+// defaultConfig returns the default service configuration using the
+// given template folder.
+func defaultConfig(templateFolder string) example.ExampleConfig {
+	return example.ExampleConfig{
+		Version: migrations.Latest(),
+		Server: server.ServerConfig{
+			Host:         "localhost",
+			Port:         8080,
+			ReadTimeout:  10,
+			WriteTimeout: 10,
+			Health:       health.Config{Enabled: true, Timeout: 2},
+		},
+		TemplateFolder: templates.DefaultTemplateSetConfig(templateFolder),
+		ErrorTemplate:  "error.html",
+		Log:            logging.DefaultConfig(),
+		Cors:           middleware.DefaultCORSConfig(),
+		Rates:          middleware.DefaultRateLimitConfig(),
+		Challenge:      challenge.DefaultConfig(),
+		Concurrency:    middleware.DefaultConcurrencyConfig(),
+		Headers:        middleware.DefaultHeaderPolicyConfig(),
+		Compress:       middleware.DefaultCompressConfig(),
+		SecureHeaders:  middleware.DefaultSecureHeadersConfig(),
+		Quotas:         quotas.DefaultConfig(),
+		JWT:            jwt.DefaultConfig(),
+		Modules:        example.DefaultModulesConfig(),
+		CSRF:           csrf.DefaultConfig(),
+		ACL:            acl.DefaultConfig(),
+		Redirects:      redirects.DefaultConfig(),
+		TimeWindows:    timewindow.DefaultConfig(),
+		Recorder:       recorder.DefaultConfig(),
+		Storage:        storage.DefaultConfig(),
+		Assets:         assets.DefaultConfig(),
+		Audit:          audit.DefaultConfig(),
+		Mirror:         mirror.DefaultConfig(),
+		Warm:           render.DefaultWarmConfig(),
+	}
+}
+
+// applyDevMode adjusts the configuration for local development:
+// human-friendly console logs and a relaxed CORS policy.
+func applyDevMode(cfg *example.ExampleConfig) {
+	cfg.Log.Enabled = true
+	cfg.Log.UTC = false
+	cfg.Log.ShortFile = true
+	cfg.Log.TimestampFormat = "15:04:05.000"
+
+	cfg.Cors = middleware.DefaultCORSConfig()
+	cfg.Cors.AllowedMethods = append(cfg.Cors.AllowedMethods, "PATCH", "HEAD")
+	cfg.Cors.AllowedHeaders = []string{"*"}
+
+	// Session cookies over plain HTTP
+	if raw, ok := cfg.Modules.Settings["auth"]; ok {
+		ac := auth.DefaultConfig()
+		if json.Unmarshal(raw, &ac) == nil {
+			ac.CookieSecure = false
+			cfg.Modules.Settings["auth"], _ = json.Marshal(ac)
+		}
+	}
+	cfg.CSRF.CookieSecure = false
+}
+
+// keyPurposes are the key rings of ExampleConfig.Keys.
+var keyPurposes = []string{"jwt", "storage"}
+
+// applyKeys loads the key rings of cfg.Keys into the JSON-less Keys
+// fields of the JWT and storage configs. Rings already in rings are
+// updated in place, so consumers holding them rotate on reload; a new
+// purpose takes effect after a restart.
+func applyKeys(cfg *example.ExampleConfig, rings map[string]*keys.Ring) (map[string]*keys.Ring, error) {
+	if rings == nil {
+		rings = map[string]*keys.Ring{}
+	}
+	for name, kc := range cfg.Keys {
+		if !slices.Contains(keyPurposes, name) {
+			return nil, fmt.Errorf("keys: unknown purpose %q (want one of %v)", name, keyPurposes)
+		}
+		if r, ok := rings[name]; ok {
+			if err := r.Update(kc); err != nil {
+				return nil, fmt.Errorf("keys %s: %w", name, err)
+			}
+			continue
+		}
+		r, err := keys.Load(kc)
+		if err != nil {
+			return nil, fmt.Errorf("keys %s: %w", name, err)
+		}
+		rings[name] = r
+	}
+	cfg.JWT.Keys = rings["jwt"]
+	cfg.Storage.Local.Keys = rings["storage"]
+	return rings, nil
+}
+
+// registerRoutes registers all example routes on srv and returns the
+// assembled modules (a lifecycle.Runner for their background work).
+// rec, bus and info may be nil (e.g. for the openapi command).
+func registerRoutes(srv *server.Server, tmpl *templates.TemplateSet, cfg *example.ExampleConfig, cors *middleware.Reloadable[middleware.CORSConfig], rates *middleware.Reloadable[middleware.RateLimitConfig], headers *middleware.Reloadable[middleware.HeaderPolicyConfig], moved *redirects.Redirector, rec *recorder.Recorder, bus *events.Bus, info *debuginfo.Info) (*module.Set, error) {
+	// Path-based access rules from the config
+	access, err := acl.New(cfg.ACL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Business hours and maintenance windows from the config
+	windows, err := timewindow.New(cfg.TimeWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daily/monthly API quotas per caller (opt-in)
+	quota, err := quotas.New(cfg.Quotas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit log of state-changing calls (opt-in)
+	calls := audit.New(cfg.Audit)
+
+	// Shadow traffic for testing a new version (opt-in)
+	shadow, err := mirror.New(cfg.Mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	// Per-IP rate limit of the API (counters reported on /metrics)
+	limiter := middleware.NewRateLimiter(rates)
+	if cfg.Challenge.Enabled {
+		// Clients over the limit may earn a fresh budget by proof-of-work
+		limiter.SetChallenge(challenge.NewProofOfWork(cfg.Challenge))
+	}
+
+	// Concurrent API requests per client (opt-in)
+	inflight := middleware.ConcurrencyLimit(cfg.Concurrency)
+
+	// Response compression; streams (SSE, WebSockets) pass through
+	compress := middleware.Compress(cfg.Compress)
+
+	// Security headers on every page and API response
+	secure := middleware.SecureHeaders(cfg.SecureHeaders)
+
+	// Shared middleware stacks offered to modules. Named middleware is
+	// listed per route by srv.Routes() (see the routes command).
+	named := middleware.Named
+	stacks := module.Middleware{
+		// HTML pages with forms
+		Page: middleware.Chain(
+			named("recovery", middleware.Recovery),
+			named("cleanup", middleware.Cleanup),
+			named("request-id", middleware.RequestID),
+			named("logging", middleware.Logging),
+			named("compress", compress, cfg.Compress),
+			named("secure-headers", secure, cfg.SecureHeaders),
+			named("headers", middleware.HeadersFrom(headers), headers),
+			named("redirects", moved.Middleware(), cfg.Redirects),
+			named("time-windows", windows.Middleware(), cfg.TimeWindows),
+			named("recorder", rec.Middleware(), cfg.Recorder),
+			named("audit", calls.Middleware(), cfg.Audit),
+			named("acl", access.Middleware(), cfg.ACL),
+			named("csrf", csrf.Protect(cfg.CSRF), cfg.CSRF),
+		),
+		// JSON APIs
+		API: middleware.Chain(
+			named("cors", middleware.CORSFrom(cors), cors),
+			named("rate-limit", limiter.Middleware(), rates),
+			named("concurrency", inflight, cfg.Concurrency),
+			named("recovery", middleware.Recovery),
+			named("cleanup", middleware.Cleanup),
+			named("request-id", middleware.RequestID),
+			named("logging", middleware.Logging),
+			named("compress", compress, cfg.Compress),
+			named("secure-headers", secure, cfg.SecureHeaders),
+			named("headers", middleware.HeadersFrom(headers), headers),
+			named("time-windows", windows.Middleware(), cfg.TimeWindows),
+			named("recorder", rec.Middleware(), cfg.Recorder),
+			named("mirror", shadow.Middleware(), cfg.Mirror),
+			named("bearer", middleware.BearerContextMap(jwt.MapParser(cfg.JWT))),
+			named("audit", calls.Middleware(), cfg.Audit),
+			named("acl", access.Middleware(), cfg.ACL),
+			named("quotas", quota.Middleware(), cfg.Quotas),
+		),
+		// API writes require a JWT
+		Auth: named("require-bearer", middleware.RequireBearer()),
+	}
+
+	// Stacks listed in the config ("stacks") replace the defaults above.
+	// Entries without a config block share the service's instances.
+	if len(cfg.Stacks) > 0 {
+		builder := middleware.NewBuilder()
+		builder.SetDefault("cors", middleware.CORSFrom(cors))
+		builder.SetDefault("rate-limit", limiter.Middleware())
+		builder.SetDefault("concurrency", inflight)
+		builder.SetDefault("headers", middleware.HeadersFrom(headers))
+		builder.SetDefault("compress", compress)
+		builder.SetDefault("secure-headers", secure)
+		builder.SetDefault("bearer", middleware.BearerContextMap(jwt.MapParser(cfg.JWT)))
+		builder.SetDefault("time-windows", windows.Middleware())
+		builder.SetDefault("recorder", rec.Middleware())
+		builder.SetDefault("mirror", shadow.Middleware())
+		builder.SetDefault("audit", calls.Middleware())
+		builder.SetDefault("acl", access.Middleware())
+		builder.SetDefault("redirects", moved.Middleware())
+		builder.SetDefault("quotas", quota.Middleware())
+		builder.SetDefault("csrf", csrf.Protect(cfg.CSRF))
+
+		for name, entries := range cfg.Stacks {
+			m, err := builder.Build(entries)
+			if err != nil {
+				return nil, fmt.Errorf("stack %s: %w", name, err)
+			}
+			switch name {
+			case "page":
+				stacks.Page = m
+			case "api":
+				stacks.API = m
+			case "auth":
+				stacks.Auth = m
+			default:
+				return nil, fmt.Errorf("unknown stack %q (page, api, auth)", name)
+			}
+		}
+	}
+
+	// Notes resource, login/registration pages, ... (see example/modules.go)
+	set, err := example.Modules(tmpl, bus).Assemble(cfg.Modules)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Register(srv, stacks); err != nil {
+		return nil, err
+	}
+
+	// Quota usage of the calling API client
+	if cfg.Quotas.Enabled {
+		srv.Handle("GET /api/quota", stacks.API(quota.Handler()), server.RouteDoc{
+			Summary: "Quota usage of the caller", Tags: []string{"quotas"}, Response: quotas.Usage{},
+		})
+	}
+
+	// Build info and runtime stats for operators (JWT required)
+	if cfg.DebugInfo {
+		if info == nil {
+			info = debuginfo.New()
+		}
+		srv.Handle("GET /debug/info", middleware.Chain(stacks.API, stacks.Auth)(info.Handler()), server.RouteDoc{
+			Summary: "Build info and runtime stats", Tags: []string{"debug"}, Response: debuginfo.Report{},
+		})
+	}
+
+	// Template updates without a restart (JWT required, see reload-templates)
+	if cfg.TemplateReload {
+		reload := tmpl.ReloadHandler(func() error {
+			errTpl, err := tmpl.Get(cfg.ErrorTemplate)
+			if err != nil {
+				return err
+			}
+			server.SetErrorTemplate(errTpl, cfg.ErrorTemplate)
+			return nil
+		})
+		srv.Handle("POST /admin/templates/reload", middleware.Chain(stacks.API, stacks.Auth)(reload), server.RouteDoc{
+			Summary: "Reload templates from disk", Tags: []string{"admin"}, Response: templates.ReloadResult{},
+		})
+	}
+
+	// Internal counters in the Prometheus/OpenMetrics format (JWT required)
+	if cfg.Metrics {
+		reg := metrics.New()
+		reg.Register(metrics.Server(srv), metrics.RateLimit("api", limiter), metrics.Events(bus))
+		for _, m := range set.Modules() {
+			if c, ok := m.(metrics.Collector); ok {
+				reg.Register(c)
+			}
+		}
+		srv.Handle("GET /metrics", middleware.Chain(stacks.API, stacks.Auth)(reg.Handler()), server.RouteDoc{
+			Summary: "Internal counters (Prometheus text format)", Tags: []string{"debug"},
+		})
+	}
+
+	// Presigned downloads of the local upload store
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if local, ok := store.(*storage.Local); ok {
+		base := strings.TrimSuffix(cfg.Storage.Local.BaseURL, "/")
+		srv.Handle("GET "+base+"/", http.StripPrefix(base, local.Handler()))
+		srv.Health().AddReadiness("storage", health.DiskSpace(cfg.Storage.Local.Dir, 100<<20))
+	}
+
+	// Home redirects to the notes list; requests without a route are a
+	// moved URL or a themed 404/405
+	srv.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/notes", http.StatusFound)
+	})
+	srv.WrapFallback(moved.Middleware())
+
+	return set, nil
+}
+
+// auditAuthEvents logs authentication events asynchronously.
+func auditAuthEvents(bus *events.Bus) {
+	for _, t := range []events.Topic[auth.Event]{auth.EventLogin, auth.EventLoginFailed, auth.EventLogout, auth.EventRegistered, auth.EventTOTPEnabled} {
+		events.SubscribeAsync(bus, t, 256, func(_ context.Context, ev auth.Event) {
+			log.Printf("audit: %s user=%q remote=%s rid=%s", t.Name(), ev.Username, logging.ClientIP(ev.RemoteAddr), ev.RequestID)
+		})
+	}
+}
+
+func runServer(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	dev := fs.Bool("dev", false, "developer mode: template hot reload, pretty logs, stack traces, relaxed CORS")
+	check := fs.Bool("check", false, "load config, logging, templates and routes, then exit without listening")
+	fs.Parse(args)
+
+	// ------------------------------------------------------------
+	// Load config (defaults, file, env, flags)
+	// ------------------------------------------------------------
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+
+	cfg := CFG.Get()
+	if *dev {
+		applyDevMode(cfg)
+	}
+
+	// Signing keys (JWT, presigned URLs) from config, env or files
+	rings, err := applyKeys(cfg, nil)
+	if err != nil {
+		fatal(err)
+	}
+
+	// ------------------------------------------------------------
+	// Init logging (global)
+	// ------------------------------------------------------------
+	if err := logging.Init(cfg.Log); err != nil {
+		log.Fatalf("failed to init logging: %v", err)
+	}
+	defer logging.Close()
+
+	// ------------------------------------------------------------
+	// Asset bundles (referenced by templates)
+	// ------------------------------------------------------------
+	bundles, err := assets.Build(cfg.Assets)
+	if err != nil {
+		log.Fatalf("failed to build assets: %v", err)
+	}
+
+	// ------------------------------------------------------------
+	// Templates + error handling
+	// ------------------------------------------------------------
+	tmpl, err := templates.LoadTemplates(cfg.TemplateFolder.Folder, csrf.Funcs(), bundles.Funcs())
+	if err != nil {
+		log.Fatalf("failed to load templates: %v", err)
+	}
+
+	server.SetErrorTemplate(
+		templates.Must(tmpl.Get(cfg.ErrorTemplate)),
+		cfg.ErrorTemplate,
+	)
+
+	if *dev {
+		tmpl.SetAutoReload(true)
+		server.SetDebug(true)
+		log.Println("Developer mode enabled: do not use in production")
+	}
+
+	// ------------------------------------------------------------
+	// Server
+	// ------------------------------------------------------------
+	srv, err := server.NewServer(&cfg.Server, nil)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
+
+	// Lifecycle transitions for operators (readiness follows them)
+	srv.OnStateChange(func(from, to server.State) {
+		log.Printf("Server state: %s -> %s", from, to)
+	})
+
+	// /readyz fails while the error page template is missing
+	srv.Health().AddReadiness("templates", func(context.Context) error {
+		_, err := tmpl.Get(cfg.ErrorTemplate)
+		return err
+	})
+
+	// ------------------------------------------------------------
+	// Routing
+	// ------------------------------------------------------------
+	// Reloadable middleware settings (swapped in place on SIGHUP)
+	cors := middleware.NewReloadable(cfg.Cors)
+	rates := middleware.NewReloadable(cfg.Rates)
+	headers := middleware.NewReloadable(cfg.Headers)
+
+	// Moved URLs (config rules and redirects file, re-read on SIGHUP)
+	moved, err := redirects.New(cfg.Redirects)
+	if err != nil {
+		log.Fatalf("failed to load redirects: %v", err)
+	}
+
+	// Domain events (audit log of authentication events)
+	bus := events.New()
+	auditAuthEvents(bus)
+
+	// Sampled request recording for debugging (see the replay command)
+	rec, err := recorder.New(cfg.Recorder)
+	if err != nil {
+		log.Fatalf("failed to create recorder: %v", err)
+	}
+	// Closed once the last request has finished
+	srv.OnShutdown(func(context.Context) error { return rec.Close() })
+
+	// Build info, config checksum and template count for /debug/info
+	info := debuginfo.New()
+	info.SetConfig(cfg)
+	info.SetTemplates(func() int { return len(tmpl.Names()) })
+
+	// Operator endpoints on the admin listener (server.admin.port), next
+	// to pprof and expvar: build info and the effective config (secrets
+	// masked), which follows reloads
+	var effective atomic.Pointer[config.Config[example.ExampleConfig]]
+	effective.Store(&CFG)
+	srv.HandleAdmin("GET /debug/info", info.Handler())
+	srv.HandleAdmin("GET /debug/config", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, err := effective.Load().Redacted()
+		if err != nil {
+			server.RespondError(w, r, err)
+			return
+		}
+		render.JSON(w, r, http.StatusOK, m)
+	}))
+
+	modules, err := registerRoutes(srv, tmpl, cfg, cors, rates, headers, moved, rec, bus, info)
+	if err != nil {
+		log.Fatalf("failed to register routes: %v", err)
+	}
+
+	// Startup report (logged before the server accepts traffic)
+	srv.AddSummary("templates", strconv.Itoa(len(tmpl.Names())))
+	srv.AddSummary("config", cf.source())
+	srv.AddSummary("config checksum", info.Report().ConfigChecksum)
+	srv.AddSummary("redirects", strconv.Itoa(moved.Len()))
+
+	// Cached pages rendered before /readyz passes, refreshed periodically
+	warmer := render.NewWarmer(cfg.Warm, srv.Mux())
+	if warmer.Enabled() {
+		srv.OnWarmup(func(ctx context.Context) error {
+			if _, err := warmer.Warm(ctx); err != nil {
+				log.Printf("page warmup: %v", err)
+			}
+			return nil
+		})
+	}
+
+	if cfg.OpenAPI {
+		openapi.Mount(srv.Mux(), apiDocument(srv))
+	}
+
+	if len(cfg.Assets.Bundles) > 0 {
+		prefix := "/" + strings.Trim(cfg.Assets.URLPrefix, "/")
+		srv.Handle("GET "+prefix+"/", http.StripPrefix(prefix, bundles.Handler()))
+	}
+
+	// Re-read the config file and apply reloadable settings on SIGHUP
+	srv.OnReload(func() error {
+		next := config.New("", example.ExampleConfig{})
+		if err := loadConfig(cf, next); err != nil {
+			return fmt.Errorf("config reload: %w", err)
+		}
+
+		ncfg := next.Get()
+		if *dev {
+			applyDevMode(ncfg)
+		}
+
+		if err := logging.Init(ncfg.Log); err != nil {
+			return fmt.Errorf("logging reload: %w", err)
+		}
+		if _, err := applyKeys(ncfg, rings); err != nil {
+			return fmt.Errorf("keys reload: %w", err)
+		}
+		cors.Store(ncfg.Cors)
+		rates.Store(ncfg.Rates)
+		headers.Store(ncfg.Headers)
+		if err := moved.Load(ncfg.Redirects); err != nil {
+			return err
+		}
+
+		if err := bundles.Rebuild(); err != nil {
+			return fmt.Errorf("assets reload: %w", err)
+		}
+		if err := tmpl.Reload(); err != nil {
+			return fmt.Errorf("template reload: %w", err)
+		}
+		errTpl, err := tmpl.Get(ncfg.ErrorTemplate)
+		if err != nil {
+			return fmt.Errorf("template reload: %w", err)
+		}
+		server.SetErrorTemplate(errTpl, ncfg.ErrorTemplate)
+		effective.Store(next)
+		return info.SetConfig(ncfg)
+	})
+
+	// Smoke check: everything is initialized, do not bind a port
+	if *check {
+		if _, err := srv.RunStartupChecks(context.Background()); err != nil {
+			log.Fatalf("Check failed: %v", err)
+		}
+		logging.Summary("Check OK", srv.Summary())
+		return
+	}
+
+	// ------------------------------------------------------------
+	// Run until SIGINT/SIGTERM; SIGHUP reloads in place, SIGUSR2
+	// hands the sockets to a new binary and then shuts down
+	// ------------------------------------------------------------
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	m := lifecycle.NewManager()
+	m.Add("events", bus)
+	m.Add("modules", modules)
+	m.Add("http", lifecycle.Server(srv, 30*time.Second))
+	if warmer.Enabled() && warmer.Interval() > 0 {
+		m.Add("warm", lifecycle.Every(warmer.Interval(), func(ctx context.Context) error {
+			_, err := warmer.Warm(ctx)
+			return err
+		}))
+	}
+	m.Add("reload", lifecycle.OnSignal(func() {
+		log.Println("Received SIGHUP, reloading...")
+		if err := srv.Reload(); err != nil {
+			log.Printf("reload failed: %v", err)
+			return
+		}
+		log.Println("Reload complete")
+	}, syscall.SIGHUP))
+
+	m.Add("upgrade", lifecycle.OnSignal(func() {
+		log.Println("Received SIGUSR2, upgrading...")
+		if err := srv.Upgrade(); err != nil {
+			log.Printf("upgrade failed: %v", err)
+			return
+		}
+		stop()
+	}, syscall.SIGUSR2))
+
+	if err := m.Run(ctx); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/redirects"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// TestRouteFallback serves the route table of the serve command and
+// checks the responses to requests without a matching route.
+func TestRouteFallback(t *testing.T) {
+	cfg := defaultConfig("")
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = 0
+	cfg.Redirects.Rules = []redirects.Rule{{From: "/old-notes", To: "/notes"}}
+
+	moved, err := redirects.New(cfg.Redirects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := server.NewServer(&cfg.Server, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registerRoutes(srv, nil, &cfg,
+		middleware.NewReloadable(cfg.Cors),
+		middleware.NewReloadable(cfg.Rates),
+		middleware.NewReloadable(cfg.Headers),
+		moved,
+		nil,
+		nil,
+		nil,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := srv.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(t.Context())
+	base := "http://" + srv.Addr().String()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	tests := []struct {
+		method, path string
+		status       int
+		header       string // expected header "Name: value" (value as prefix)
+	}{
+		{"GET", "/", http.StatusFound, "Location: /notes"},
+		{"GET", "/old-notes", http.StatusMovedPermanently, "Location: /notes"},
+		{"POST", "/old-notes", http.StatusMovedPermanently, "Location: /notes"},
+		{"GET", "/missing", http.StatusNotFound, ""},
+		{"POST", "/notes", http.StatusMethodNotAllowed, "Allow: GET"},
+		{"DELETE", "/notes/1", http.StatusMethodNotAllowed, "Allow: GET"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, base+tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, res.StatusCode, tt.status)
+			continue
+		}
+		if name, value, ok := strings.Cut(tt.header, ": "); ok && !strings.HasPrefix(res.Header.Get(name), value) {
+			t.Errorf("%s %s: %s %q, want %q", tt.method, tt.path, name, res.Header.Get(name), value)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+/*
+Config migrations and the "migrate-config" command.
+
+Summary
+-------
+- migrations lists all breaking changes of the example config format.
+- "migrate-config" upgrades a config file to the latest version and
+  reports the applied steps and the resulting changes.
+*/
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bennof/gobfwebservice/config"
+	"github.com/bennof/gobfwebservice/jwt"
+)
+
+// migrations is the registry of config format migrations.
+// Add a step here whenever the config format changes incompatibly.
+var migrations = config.NewMigrations(
+	config.Migration{
+		From:        0,
+		Description: "add jwt section and openapi switch",
+		Apply: func(doc map[string]any) error {
+			if _, ok := doc["jwt"]; !ok {
+				d := jwt.DefaultConfig()
+				doc["jwt"] = map[string]any{
+					"algorithm": d.Algorithm,
+					"secret":    d.Secret,
+					"issuer":    d.Issuer,
+					"ttl":       d.TTL,
+				}
+			}
+			if _, ok := doc["openapi"]; !ok {
+				doc["openapi"] = false
+			}
+			return nil
+		},
+	},
+	config.Migration{
+		From:        1,
+		Description: "move auth settings into the modules section",
+		Apply: func(doc map[string]any) error {
+			if _, ok := doc["modules"]; ok {
+				return nil
+			}
+
+			settings := map[string]any{}
+			if a, ok := doc["auth"]; ok {
+				settings["auth"] = a
+				delete(doc, "auth")
+			}
+			doc["modules"] = map[string]any{
+				"enabled":  []any{"notes", "auth"},
+				"settings": settings,
+			}
+			return nil
+		},
+	},
+)
+
+func runMigrateConfig(args []string) {
+	fs := flag.NewFlagSet("migrate-config", flag.ExitOnError)
+	in := fs.String("in", "config.json", "input config file")
+	out := fs.String("out", "", "output config file (default: overwrite input)")
+	fs.Parse(args)
+
+	if *out == "" {
+		*out = *in
+	}
+
+	steps, changes, err := migrations.MigrateFile(*in, *out)
+	for _, s := range steps {
+		fmt.Printf("applied %s\n", s)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	if len(steps) == 0 {
+		fmt.Printf("%s is up to date (version %d)\n", *in, migrations.Latest())
+		return
+	}
+
+	fmt.Println("changes:")
+	for _, c := range changes {
+		fmt.Printf("  %s\n", c)
+	}
+	fmt.Printf("Migrated config written to %s\n", *out)
+}
@@ -0,0 +1,99 @@
+package main
+
+/*
+Template reload trigger for the "reload-templates" command.
+
+Summary
+-------
+- Asks a running server to reload its templates from disk
+  (POST /admin/templates/reload, enabled with "template_reload").
+- Authenticates with a short-lived token signed with the configured JWT
+  settings, like the "token" command.
+- The server URL defaults to the configured host and port (https if a
+  certificate or ACME is configured).
+- Prints the result including parse errors; exits with status 1 if the
+  reload failed, so deploy scripts can stop.
+*/
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/jwt"
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+func runReloadTemplates(args []string) {
+	fs := flag.NewFlagSet("reload-templates", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	target := fs.String("url", "", "server base URL (default: from the config)")
+	insecure := fs.Bool("insecure", false, "skip TLS certificate verification (self-signed certificates)")
+	fs.Parse(args)
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+
+	cfg := CFG.Get()
+	if _, err := applyKeys(cfg, nil); err != nil {
+		fatal(err)
+	}
+
+	base := *target
+	if base == "" {
+		scheme := "http"
+		if cfg.Server.CertFile != "" || cfg.Server.ACME.Enabled {
+			scheme = "https"
+		}
+		host := cfg.Server.Host
+		if host == "" || host == "0.0.0.0" {
+			host = "localhost"
+		}
+		base = fmt.Sprintf("%s://%s:%d", scheme, host, cfg.Server.Port)
+	}
+
+	jc := cfg.JWT
+	jc.TTL = 60
+	token, err := jwt.Sign(jc, map[string]any{"sub": "servercli"})
+	if err != nil {
+		fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(base, "/")+"/admin/templates/reload", nil)
+	if err != nil {
+		fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if *insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var res templates.ReloadResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		fmt.Printf("reload failed: %s (is template_reload enabled?)\n", resp.Status)
+		os.Exit(1)
+	}
+
+	if !res.OK {
+		fmt.Printf("reload failed, %d templates still served:\n", res.Templates)
+		for _, e := range res.Errors {
+			fmt.Println("  " + e)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("reloaded %d templates\n", res.Templates)
+}
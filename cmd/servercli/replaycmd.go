@@ -0,0 +1,125 @@
+package main
+
+/*
+Request replay for the "replay" command.
+
+Summary
+-------
+- Reads a recording written by the recorder middleware
+  (requests-YYYYMMDD.jsonl) and re-sends the requests against a target.
+- Requests can be filtered by method and path prefix and paced with a
+  fixed delay; -dry only lists them.
+- Prints the recorded and the replayed status side by side and
+  highlights differences.
+*/
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/recorder"
+)
+
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	in := fs.String("in", "", "recording file (requests-YYYYMMDD.jsonl)")
+	target := fs.String("target", "http://localhost:8080", "target base URL")
+	method := fs.String("method", "", "only replay requests with this method")
+	prefix := fs.String("path", "", "only replay requests with this path prefix")
+	rid := fs.String("request-id", "", "only replay the request with this request ID")
+	delay := fs.Duration("delay", 0, "pause between requests")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-request timeout")
+	dry := fs.Bool("dry", false, "list matching requests without sending them")
+	var headers stringList
+	fs.Var(&headers, "H", "extra header as 'Name: value', e.g. a fresh Authorization (repeatable)")
+	fs.Parse(args)
+
+	if *in == "" {
+		fmt.Println("usage: replay -in requests.jsonl [-target URL] [-method M] [-path /prefix] [-request-id ID] [-H 'Name: value'] [-delay 100ms] [-dry]")
+		os.Exit(1)
+	}
+
+	base, err := url.Parse(*target)
+	if err != nil {
+		fatal(err)
+	}
+
+	records, err := recorder.ReadFile(*in)
+	if err != nil {
+		fatal(err)
+	}
+
+	client := &http.Client{
+		Timeout: *timeout,
+		// Show redirects as recorded instead of following them
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	sent, diff := 0, 0
+	for _, rec := range records {
+		if *method != "" && !strings.EqualFold(rec.Method, *method) {
+			continue
+		}
+		if *prefix != "" && !strings.HasPrefix(rec.URL, *prefix) {
+			continue
+		}
+		if *rid != "" && rec.RequestID != *rid {
+			continue
+		}
+
+		if *dry {
+			fmt.Printf("%s %s %s (recorded %d)\n", rec.Time.Format(time.RFC3339), rec.Method, rec.URL, rec.Status)
+			continue
+		}
+
+		req, err := rec.Request(base)
+		if err != nil {
+			fatal(err)
+		}
+		for _, h := range headers {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok {
+				fatal(fmt.Errorf("invalid header %q", h))
+			}
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		if rec.Truncated {
+			fmt.Printf("  warning: body of %s %s was truncated when recorded\n", rec.Method, rec.URL)
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Printf("%-6s %s error: %v\n", rec.Method, rec.URL, err)
+			diff++
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		mark := ""
+		if resp.StatusCode != rec.Status {
+			mark = "  <- differs"
+			diff++
+		}
+		fmt.Printf("%-6s %s recorded=%d replayed=%d %s%s\n",
+			rec.Method, rec.URL, rec.Status, resp.StatusCode, time.Since(start).Round(time.Millisecond), mark)
+		sent++
+
+		if *delay > 0 {
+			time.Sleep(*delay)
+		}
+	}
+
+	if !*dry {
+		fmt.Printf("Replayed %d requests, %d with a different outcome\n", sent, diff)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+/*
+Route listing for the "routes" command.
+
+Summary
+-------
+- Registers the service routes on a server instance (without binding a port).
+- Prints one line per route with method, path and the middleware
+  wrapping it (outermost first), or with -json the full route registry
+  including each middleware's configuration.
+*/
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// routeView is the JSON form of a route.
+type routeView struct {
+	Method     string                  `json:"method,omitempty"`
+	Path       string                  `json:"path"`
+	Summary    string                  `json:"summary,omitempty"`
+	Middleware []server.MiddlewareInfo `json:"middleware,omitempty"`
+}
+
+func runRoutes(args []string) {
+	fs := flag.NewFlagSet("routes", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	asJSON := fs.Bool("json", false, "print routes with middleware configuration as JSON")
+	fs.Parse(args)
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+	cfg := CFG.Get()
+
+	srv, err := server.NewServer(&cfg.Server, nil)
+	if err != nil {
+		fatal(err)
+	}
+	if _, err := registerRoutes(srv, nil, cfg,
+		middleware.NewReloadable(cfg.Cors),
+		middleware.NewReloadable(cfg.Rates),
+		middleware.NewReloadable(cfg.Headers),
+		nil,
+		nil,
+		nil,
+		nil,
+	); err != nil {
+		fatal(err)
+	}
+
+	routes := srv.Routes()
+	if *asJSON {
+		views := make([]routeView, len(routes))
+		for i, r := range routes {
+			views[i] = routeView{Method: r.Method, Path: r.Path, Summary: r.Doc.Summary, Middleware: r.Middleware}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		fatal(enc.Encode(views))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tMIDDLEWARE")
+	for _, r := range routes {
+		method := r.Method
+		if method == "" {
+			method = "*"
+		}
+		names := make([]string, len(r.Middleware))
+		for i, m := range r.Middleware {
+			names[i] = m.Name
+		}
+		mw := strings.Join(names, " > ")
+		if mw == "" {
+			mw = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", method, r.Path, mw)
+	}
+	tw.Flush()
+}
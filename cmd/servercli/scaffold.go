@@ -0,0 +1,139 @@
+package main
+
+/*
+Project scaffolding for the "new" command.
+
+Summary
+-------
+- Generates a ready-to-run service project in a new directory.
+- Writes main.go wired with server, middleware, templates and config.
+- Writes a default config.json, layout/index/error templates and a Makefile.
+- Scaffold sources are embedded; *.tmpl files are rendered with [[ ]] delimiters
+  so they can contain html/template syntax unchanged.
+*/
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bennof/gobfwebservice/config"
+)
+
+//go:embed all:scaffold
+var scaffoldFS embed.FS
+
+// scaffoldData is passed to all *.tmpl scaffold files.
+type scaffoldData struct {
+	Name   string // project (binary) name
+	Module string // Go module path
+}
+
+func runNew(args []string) {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	module := fs.String("module", "", "Go module path (default: directory name)")
+	force := fs.Bool("force", false, "write into an existing, non-empty directory")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Println("usage: new [-module path] [-force] <dir>")
+		os.Exit(1)
+	}
+
+	dir := fs.Arg(0)
+	name := filepath.Base(filepath.Clean(dir))
+
+	data := scaffoldData{
+		Name:   name,
+		Module: *module,
+	}
+	if data.Module == "" {
+		data.Module = name
+	}
+
+	if err := checkTargetDir(dir, *force); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("Creating project %s in %s...\n", data.Module, dir)
+
+	if err := writeScaffold(dir, data); err != nil {
+		fatal(err)
+	}
+
+	// Default configuration for the generated project
+	cfg := config.New("", defaultConfig("templates"))
+	if err := cfg.SaveAs(filepath.Join(dir, "config.json")); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf(`Done. Next steps:
+
+  cd %s
+  go mod tidy
+  make run
+`, dir)
+}
+
+// checkTargetDir ensures dir is absent or empty unless force is set.
+func checkTargetDir(dir string, force bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 && !force {
+		return fmt.Errorf("directory %s is not empty (use -force)", dir)
+	}
+	return nil
+}
+
+// writeScaffold copies the embedded scaffold tree into dir, rendering
+// *.tmpl files with data and stripping their suffix.
+func writeScaffold(dir string, data scaffoldData) error {
+	return fs.WalkDir(scaffoldFS, "scaffold", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, "scaffold"), "/")
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		b, err := scaffoldFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		if path.Ext(p) == ".tmpl" {
+			target = strings.TrimSuffix(target, ".tmpl")
+
+			tpl, err := template.New(rel).Delims("[[", "]]").Parse(string(b))
+			if err != nil {
+				return fmt.Errorf("failed to parse scaffold %s: %w", rel, err)
+			}
+
+			var buf bytes.Buffer
+			if err := tpl.Execute(&buf, data); err != nil {
+				return fmt.Errorf("failed to render scaffold %s: %w", rel, err)
+			}
+			b = buf.Bytes()
+		}
+
+		fmt.Printf("  create %s\n", target)
+		return os.WriteFile(target, b, 0644)
+	})
+}
@@ -0,0 +1,173 @@
+package main
+
+/*
+Static site generation for the "static-gen" command.
+
+Summary
+-------
+- Loads the configured template set and pre-renders every view into an
+  output directory (the error template is skipped).
+- Optionally reads per-page data from <data>/<view>.json
+  (e.g. data/about.json for about.html).
+- Copies a static asset directory verbatim into the output directory.
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/assets"
+	"github.com/bennof/gobfwebservice/csrf"
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+func runStaticGen(args []string) {
+	fs := flag.NewFlagSet("static-gen", flag.ExitOnError)
+	cf := addConfigFlags(fs)
+	out := fs.String("out", "dist", "output directory")
+	dataDir := fs.String("data", "", "directory with optional <view>.json data files")
+	static := fs.String("static", "", "static asset directory to copy into the output")
+	fs.Parse(args)
+
+	if err := loadConfig(cf, &CFG); err != nil {
+		fatal(err)
+	}
+	cfg := CFG.Get()
+
+	bundles, err := assets.Build(cfg.Assets)
+	if err != nil {
+		fatal(err)
+	}
+
+	tmpl, err := templates.LoadTemplates(cfg.TemplateFolder.Folder, csrf.Funcs(), bundles.Funcs())
+	if err != nil {
+		fatal(err)
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		fatal(err)
+	}
+
+	// ------------------------------------------------------------
+	// Render views
+	// ------------------------------------------------------------
+	pages := 0
+	for _, name := range tmpl.Names() {
+		if name == cfg.ErrorTemplate {
+			continue
+		}
+
+		data, err := loadPageData(*dataDir, name)
+		if err != nil {
+			fatal(err)
+		}
+
+		buf, err := tmpl.RenderToBytes(name, data)
+		if err != nil {
+			fatal(fmt.Errorf("failed to render %s: %w", name, err))
+		}
+
+		target := filepath.Join(*out, name)
+		if err := os.WriteFile(target, buf.Bytes(), 0644); err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("  render %s\n", target)
+		pages++
+	}
+
+	// ------------------------------------------------------------
+	// Copy static assets
+	// ------------------------------------------------------------
+	copied := 0
+	if *static != "" {
+		n, err := copyTree(*static, *out)
+		if err != nil {
+			fatal(err)
+		}
+		copied = n
+	}
+
+	fmt.Printf("Generated %d pages and copied %d assets to %s\n", pages, copied, *out)
+}
+
+// loadPageData reads <dir>/<view>.json if it exists.
+// It returns nil data if dir is empty or the file is absent.
+func loadPageData(dir, view string) (any, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(dir, strings.TrimSuffix(view, filepath.Ext(view))+".json")
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("invalid page data %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// copyTree copies all regular files below src into dst, preserving the
+// relative directory layout. It returns the number of copied files.
+func copyTree(src, dst string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if err := copyFile(path, target); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// copyFile copies a single file from src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,186 @@
+package config
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Versioned configuration migrations.
+
+Summary
+-------
+- A Migrations registry holds ordered steps, each upgrading a config
+  document from version N to N+1.
+- Migrations operate on the generic JSON representation (map[string]any),
+  so old files can be upgraded even if they no longer match the Go structs.
+- The version is stored in the document under VersionKey.
+- Diff reports the changes between two documents by key path.
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// VersionKey is the top-level JSON key holding the config version.
+// Documents without it are treated as version 0.
+const VersionKey = "config_version"
+
+// Migration upgrades a config document from version From to From+1.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]any) error
+}
+
+// Migrations is an ordered registry of config migrations.
+type Migrations struct {
+	steps map[int]Migration
+}
+
+// NewMigrations creates a registry with the given migrations.
+func NewMigrations(ms ...Migration) *Migrations {
+	r := &Migrations{steps: map[int]Migration{}}
+	for _, m := range ms {
+		r.Register(m)
+	}
+	return r
+}
+
+// Register adds a migration. A later registration for the same
+// source version replaces the earlier one.
+func (r *Migrations) Register(m Migration) {
+	r.steps[m.From] = m
+}
+
+// Latest returns the version produced by applying all migrations.
+func (r *Migrations) Latest() int {
+	latest := 0
+	for from := range r.steps {
+		if from+1 > latest {
+			latest = from + 1
+		}
+	}
+	return latest
+}
+
+// Migrate upgrades doc in place to the latest version and returns the
+// descriptions of the applied steps.
+func (r *Migrations) Migrate(doc map[string]any) ([]string, error) {
+	var applied []string
+
+	for v := Version(doc); v < r.Latest(); v++ {
+		m, ok := r.steps[v]
+		if !ok {
+			return applied, fmt.Errorf("config: no migration from version %d", v)
+		}
+		if err := m.Apply(doc); err != nil {
+			return applied, fmt.Errorf("config: migration %d->%d: %w", v, v+1, err)
+		}
+		doc[VersionKey] = v + 1
+		applied = append(applied, fmt.Sprintf("%d->%d: %s", v, v+1, m.Description))
+	}
+
+	return applied, nil
+}
+
+// MigrateFile reads a JSON config file, migrates it and writes the
+// result to out (which may equal in). It returns the applied steps and
+// the resulting changes (see Diff).
+func (r *Migrations) MigrateFile(in, out string) (steps, changes []string, err error) {
+	b, err := os.ReadFile(in)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var doc, before map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+	_ = json.Unmarshal(b, &before)
+
+	steps, err = r.Migrate(doc)
+	if err != nil {
+		return steps, nil, err
+	}
+
+	b, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return steps, nil, err
+	}
+	if err := os.WriteFile(out, b, 0644); err != nil {
+		return steps, nil, err
+	}
+
+	// Compare via a JSON round trip so number types match
+	var after map[string]any
+	_ = json.Unmarshal(b, &after)
+
+	return steps, Diff(before, after), nil
+}
+
+// Version returns the config version stored in doc (0 if absent).
+func Version(doc map[string]any) int {
+	if v, ok := doc[VersionKey].(float64); ok {
+		return int(v)
+	}
+	if v, ok := doc[VersionKey].(int); ok {
+		return v
+	}
+	return 0
+}
+
+// Diff lists the differences between two config documents by key path:
+// "+ path = value" (added), "- path" (removed), "~ path: old -> new" (changed).
+func Diff(before, after map[string]any) []string {
+	b := flatten(before, "")
+	a := flatten(after, "")
+
+	keys := map[string]bool{}
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range a {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var out []string
+	for _, k := range sorted {
+		ov, inB := b[k]
+		nv, inA := a[k]
+		switch {
+		case !inB:
+			out = append(out, fmt.Sprintf("+ %s = %s", k, nv))
+		case !inA:
+			out = append(out, fmt.Sprintf("- %s", k))
+		case ov != nv:
+			out = append(out, fmt.Sprintf("~ %s: %s -> %s", k, ov, nv))
+		}
+	}
+	return out
+}
+
+// flatten maps key paths of all leaf values to their JSON encoding.
+func flatten(m map[string]any, prefix string) map[string]string {
+	out := map[string]string{}
+	for k, v := range m {
+		path := strings.TrimPrefix(prefix+"."+k, ".")
+		if sub, ok := v.(map[string]any); ok {
+			for sk, sv := range flatten(sub, path) {
+				out[sk] = sv
+			}
+			continue
+		}
+		b, _ := json.Marshal(v)
+		out[path] = string(b)
+	}
+	return out
+}
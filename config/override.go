@@ -0,0 +1,222 @@
+package config
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Overrides and redaction for configuration values.
+
+Summary
+-------
+- Set applies a single "section.key=value" style override.
+- ApplyEnv applies overrides from environment variables derived from the
+  JSON key paths (e.g. APP_SERVER_PORT for server.port).
+- Redacted returns a JSON-ready view with secret values masked.
+- Everything works on the JSON representation of the config, so no
+  reflection beyond encoding/json is required.
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SecretKeys lists lowercase substrings of JSON keys whose values are
+// treated as secrets and masked by Redacted.
+var SecretKeys = []string{"password", "secret", "token", "api_key", "private_key", "signing_key"}
+
+// RedactedValue replaces secret values in Redacted output.
+const RedactedValue = "******"
+
+// Set overrides a single configuration value addressed by a dotted JSON
+// key path (e.g. "server.port"). The value is parsed as JSON unless the
+// current value is a string, in which case it is used verbatim.
+func (c *Config[T]) Set(path, value string) error {
+	m, err := c.toMap()
+	if err != nil {
+		return err
+	}
+
+	if err := setPath(m, strings.Split(path, "."), value); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	return c.fromMap(m)
+}
+
+// ApplyEnv overrides configuration values from environment variables.
+// The variable name for a key path is the prefix followed by the upper-cased
+// path joined by underscores, e.g. prefix "APP" and path server.port give
+// APP_SERVER_PORT. Only keys present in the config are considered.
+//
+// It returns the key paths that were overridden.
+func (c *Config[T]) ApplyEnv(prefix string) ([]string, error) {
+	m, err := c.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, path := range leafPaths(m, nil) {
+		name := envName(prefix, path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setPath(m, path, value); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", name, err)
+		}
+		applied = append(applied, strings.Join(path, "."))
+	}
+
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	return applied, c.fromMap(m)
+}
+
+// Redacted returns the configuration as a generic JSON map with all
+// secret values (see SecretKeys) replaced by RedactedValue.
+func (c *Config[T]) Redacted() (map[string]any, error) {
+	m, err := c.toMap()
+	if err != nil {
+		return nil, err
+	}
+	redact(m)
+	return m, nil
+}
+
+/* --------------------------------------------------------------------------
+   Helpers
+   -------------------------------------------------------------------------- */
+
+// toMap converts the config into its generic JSON representation.
+func (c *Config[T]) toMap() (map[string]any, error) {
+	b, err := json.Marshal(c.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("config: not a JSON object: %w", err)
+	}
+	return m, nil
+}
+
+// fromMap replaces the config with the given generic JSON representation.
+func (c *Config[T]) fromMap(m map[string]any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	var cfg T
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return err
+	}
+
+	c.cfg = cfg
+	return nil
+}
+
+// setPath assigns value to the key path in m.
+func setPath(m map[string]any, path []string, value string) error {
+	for i, key := range path {
+		cur, ok := m[key]
+		if !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+
+		if i < len(path)-1 {
+			next, ok := cur.(map[string]any)
+			if !ok {
+				return fmt.Errorf("key %q is not an object", key)
+			}
+			m = next
+			continue
+		}
+
+		// Strings are taken verbatim, everything else is parsed as JSON
+		if _, isString := cur.(string); isString {
+			m[key] = value
+			return nil
+		}
+
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return fmt.Errorf("invalid value %q: %w", value, err)
+		}
+		m[key] = v
+	}
+	return nil
+}
+
+// leafPaths returns all key paths to non-object values in sorted order.
+func leafPaths(m map[string]any, prefix []string) [][]string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var paths [][]string
+	for _, k := range keys {
+		path := append(append([]string(nil), prefix...), k)
+		if sub, ok := m[k].(map[string]any); ok {
+			paths = append(paths, leafPaths(sub, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// envName builds the environment variable name for a key path.
+func envName(prefix string, path []string) string {
+	name := strings.ToUpper(strings.Join(path, "_"))
+	if prefix == "" {
+		return name
+	}
+	return strings.ToUpper(prefix) + "_" + name
+}
+
+// redact masks secret values in m recursively, including objects in
+// arrays (e.g. key rings) and string arrays under a secret key.
+func redact(m map[string]any) {
+	for k, v := range m {
+		switch val := v.(type) {
+		case map[string]any:
+			redact(val)
+		case []any:
+			for i, e := range val {
+				switch ev := e.(type) {
+				case map[string]any:
+					redact(ev)
+				case string:
+					if ev != "" && isSecretKey(k) {
+						val[i] = RedactedValue
+					}
+				}
+			}
+		case string:
+			if val != "" && isSecretKey(k) {
+				m[k] = RedactedValue
+			}
+		}
+	}
+}
+
+// isSecretKey reports whether a JSON key names a secret value.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range SecretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
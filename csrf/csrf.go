@@ -0,0 +1,226 @@
+package csrf
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package csrf protects HTML forms against cross-site request forgery.
+
+Summary
+-------
+- Protect is the middleware: it issues a per-session token (stored in an
+  HttpOnly cookie) and verifies it on unsafe methods (POST, PUT, PATCH,
+  DELETE) from the form field or request header.
+- Tokens handed to templates are masked with a fresh one-time pad per
+  render (BREACH mitigation); any masked form verifies.
+- {{csrfField}} and {{csrfToken}} template functions via Funcs (load
+  time placeholders) and FuncMap (per request).
+
+Typical usage:
+
+	tmpl, _ := templates.LoadTemplates("templates", csrf.Funcs())
+	protect := csrf.Protect(cfg)
+	srv.Handle("POST /login", protect(loginHandler))
+
+	// in the handler
+	buf, _ := tmpl.RenderToBytesFuncs("login.html", csrf.FuncMap(r), data)
+
+	// in the template
+	<form method="post">{{csrfField}} ... </form>
+
+Requests without a valid token are rejected with 403 Forbidden.
+JSON APIs authenticated by Bearer tokens do not need this middleware.
+*/
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+const tokenLen = 32
+
+// Reasons a submitted token is rejected.
+var (
+	errNoCookie     = errors.New("csrf: missing token cookie")
+	errNoToken      = errors.New("csrf: missing token")
+	errInvalidToken = errors.New("csrf: invalid token")
+)
+
+// Config defines the CSRF protection behavior.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	CookieName   string `json:"cookie_name"`   // Cookie holding the session token
+	FieldName    string `json:"field_name"`    // Form field checked on unsafe methods
+	HeaderName   string `json:"header_name"`   // Header checked on unsafe methods (AJAX)
+	CookieSecure bool   `json:"cookie_secure"` // Send the cookie over HTTPS only
+	MaxAge       int    `json:"max_age"`       // Cookie lifetime in seconds (0 = browser session)
+}
+
+// DefaultConfig returns a default CSRF configuration.
+func DefaultConfig() Config {
+	return Config{
+		CookieName:   "csrf",
+		FieldName:    "csrf_token",
+		HeaderName:   "X-CSRF-Token",
+		CookieSecure: true,
+	}
+}
+
+// stateKey stores the request state.
+var stateKey = ctxutil.NewKey[*state]("csrf")
+
+// state is the per-request token state.
+type state struct {
+	token []byte // unmasked session token
+	field string // form field name
+}
+
+// Protect returns the CSRF middleware.
+func Protect(cfg ...Config) middleware.Middleware {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Cookie")
+
+			cookie := readCookie(r, c.CookieName)
+			token := cookie
+			if token == nil {
+				token = make([]byte, tokenLen)
+				if _, err := rand.Read(token); err != nil {
+					server.InternalServerError(w, r)
+					return
+				}
+				http.SetCookie(w, &http.Cookie{
+					Name:     c.CookieName,
+					Value:    base64.RawURLEncoding.EncodeToString(token),
+					Path:     "/",
+					MaxAge:   c.MaxAge,
+					HttpOnly: true,
+					Secure:   c.CookieSecure,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+
+			if !safeMethod(r.Method) {
+				sent := r.Header.Get(c.HeaderName)
+				if sent == "" {
+					sent = r.PostFormValue(c.FieldName)
+				}
+				if err := verify(cookie, sent); err != nil {
+					server.Forbidden(w, r)
+					return
+				}
+			}
+
+			ctx := stateKey.Set(r.Context(), &state{token: token, field: c.FieldName})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Token returns a masked token for the current request, or "" if the
+// request did not pass through Protect.
+func Token(r *http.Request) string {
+	st, ok := stateKey.Get(r.Context())
+	if !ok {
+		return ""
+	}
+	return mask(st.token)
+}
+
+// Field returns a hidden form input carrying a masked token.
+func Field(r *http.Request) template.HTML {
+	st, ok := stateKey.Get(r.Context())
+	if !ok {
+		return ""
+	}
+	return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(st.field) +
+		`" value="` + mask(st.token) + `">`)
+}
+
+// Funcs returns placeholder template functions to register at load time
+// (templates.LoadTemplates). They render empty values.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"csrfField": func() template.HTML { return "" },
+		"csrfToken": func() string { return "" },
+	}
+}
+
+// FuncMap returns the template functions bound to r, for use with
+// templates.TemplateSet.RenderToBytesFuncs.
+func FuncMap(r *http.Request) template.FuncMap {
+	return template.FuncMap{
+		"csrfField": func() template.HTML { return Field(r) },
+		"csrfToken": func() string { return Token(r) },
+	}
+}
+
+// verify checks a submitted masked token against the cookie token.
+// Requests without a cookie (cookie == nil) never verify.
+func verify(cookie []byte, sent string) error {
+	if cookie == nil {
+		return errNoCookie
+	}
+	if sent == "" {
+		return errNoToken
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(sent)
+	if err != nil || len(b) != 2*tokenLen {
+		return errInvalidToken
+	}
+
+	unmasked := make([]byte, tokenLen)
+	for i := range unmasked {
+		unmasked[i] = b[i] ^ b[tokenLen+i]
+	}
+	if subtle.ConstantTimeCompare(unmasked, cookie) != 1 {
+		return errInvalidToken
+	}
+	return nil
+}
+
+// mask returns base64(pad || pad XOR token) with a fresh random pad.
+func mask(token []byte) string {
+	b := make([]byte, 2*tokenLen)
+	_, _ = rand.Read(b[:tokenLen])
+	for i := range tokenLen {
+		b[tokenLen+i] = b[i] ^ token[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// readCookie returns the decoded token cookie or nil.
+func readCookie(r *http.Request, name string) []byte {
+	ck, err := r.Cookie(name)
+	if err != nil {
+		return nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(ck.Value)
+	if err != nil || len(b) != tokenLen {
+		return nil
+	}
+	return b
+}
+
+// safeMethod reports whether the method does not need verification.
+func safeMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package ctxutil
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package ctxutil provides typed, collision-safe context keys.
+
+Summary
+-------
+- Key[T] is a context key bound to a value type. Values stored under a
+  key can only be read back as T, so no type assertions are needed.
+- NewKey creates a unique key: two keys never collide, even if they
+  have the same name and type (identity is a private pointer).
+- TypeKey returns the key for "the" value of type T: all callers of
+  TypeKey[T] share it. Useful for generic code (e.g. typed claims).
+- Set/Get are generic helpers; Key also offers methods.
+
+Typical usage:
+
+	var userKey = ctxutil.NewKey[*User]("user")
+
+	ctx = ctxutil.Set(ctx, userKey, u)
+	u, ok := ctxutil.Get(ctx, userKey)
+*/
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Key is a typed context key. The zero value is equivalent to TypeKey[T]().
+type Key[T any] struct {
+	name *string // unique identity; nil for type keys
+}
+
+// typeKey is the context key of TypeKey[T].
+type typeKey[T any] struct{}
+
+// NewKey creates a new unique key. The name is used for debugging only.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: &name}
+}
+
+// TypeKey returns the shared key for values of type T.
+func TypeKey[T any]() Key[T] {
+	return Key[T]{}
+}
+
+// Set returns a copy of ctx carrying v under k.
+func (k Key[T]) Set(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k.id(), v)
+}
+
+// Get returns the value stored under k and whether it was present.
+func (k Key[T]) Get(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k.id()).(T)
+	return v, ok
+}
+
+// Value returns the value stored under k or the zero value of T.
+func (k Key[T]) Value(ctx context.Context) T {
+	v, _ := k.Get(ctx)
+	return v
+}
+
+// MustGet returns the value stored under k and panics if it is missing.
+// Use it only where a middleware guarantees the value.
+func (k Key[T]) MustGet(ctx context.Context) T {
+	v, ok := k.Get(ctx)
+	if !ok {
+		panic(fmt.Sprintf("ctxutil: no value for key %s", k))
+	}
+	return v
+}
+
+// String returns the key name (or the type name for type keys).
+func (k Key[T]) String() string {
+	if k.name == nil {
+		return reflect.TypeFor[T]().String()
+	}
+	return *k.name
+}
+
+// id returns the value used as context key.
+func (k Key[T]) id() any {
+	if k.name == nil {
+		return typeKey[T]{}
+	}
+	return k.name
+}
+
+// Set returns a copy of ctx carrying v under k.
+func Set[T any](ctx context.Context, k Key[T], v T) context.Context {
+	return k.Set(ctx, v)
+}
+
+// Get returns the value stored under k and whether it was present.
+func Get[T any](ctx context.Context, k Key[T]) (T, bool) {
+	return k.Get(ctx)
+}
@@ -0,0 +1,169 @@
+package debuginfo
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package debuginfo serves build information and runtime statistics as JSON.
+
+Summary
+-------
+- Handler reports the module path and version, Go version and VCS
+  revision (from debug.ReadBuildInfo), process uptime, goroutines, heap
+  and GC statistics, a SHA-256 checksum of the loaded configuration and
+  the number of loaded templates.
+- Meant for quick operational inspection ("which build runs, with which
+  config?") without a metrics stack. It reveals internals: mount it only
+  behind authentication.
+- SetConfig is called at startup and after every config reload, so the
+  checksum identifies the configuration actually in effect. Secrets do
+  not leave the process; only the hash is reported.
+
+Typical usage:
+
+	info := debuginfo.New()
+	info.SetConfig(cfg)
+	info.SetTemplates(func() int { return len(tmpl.Names()) })
+	srv.Handle("GET /debug/info", requireAdmin(info.Handler()))
+*/
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/bennof/gobfwebservice/render"
+)
+
+// started approximates the process start.
+var started = time.Now()
+
+// Build describes the running binary.
+type Build struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"` // VCS commit
+	Time      string `json:"time,omitempty"`     // VCS commit time
+	Modified  bool   `json:"modified,omitempty"` // built from a dirty tree
+}
+
+// Runtime holds Go runtime statistics.
+type Runtime struct {
+	Goroutines  int    `json:"goroutines"`
+	GOMAXPROCS  int    `json:"gomaxprocs"`
+	NumCPU      int    `json:"num_cpu"`
+	HeapAlloc   uint64 `json:"heap_alloc"` // bytes
+	HeapInuse   uint64 `json:"heap_inuse"` // bytes
+	HeapObjects uint64 `json:"heap_objects"`
+	Sys         uint64 `json:"sys"` // bytes obtained from the OS
+	NumGC       uint32 `json:"num_gc"`
+	PauseTotal  string `json:"gc_pause_total"`
+}
+
+// Report is the document served by Handler.
+type Report struct {
+	Build          Build     `json:"build"`
+	Started        time.Time `json:"started"`
+	Uptime         string    `json:"uptime"`
+	Runtime        Runtime   `json:"runtime"`
+	ConfigChecksum string    `json:"config_checksum,omitempty"`
+	Templates      int       `json:"templates"`
+}
+
+// Info collects the report sources.
+type Info struct {
+	checksum  atomic.Pointer[string]
+	templates atomic.Pointer[func() int]
+}
+
+// New creates an empty Info.
+func New() *Info {
+	return &Info{}
+}
+
+// SetConfig records the checksum of the JSON encoding of cfg.
+func (i *Info) SetConfig(cfg any) error {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	s := hex.EncodeToString(sum[:])
+	i.checksum.Store(&s)
+	return nil
+}
+
+// SetTemplates sets the function counting loaded templates.
+func (i *Info) SetTemplates(fn func() int) {
+	i.templates.Store(&fn)
+}
+
+// Report returns the current report.
+func (i *Info) Report() Report {
+	rep := Report{
+		Build:   readBuild(),
+		Started: started.UTC(),
+		Uptime:  time.Since(started).Round(time.Second).String(),
+		Runtime: readRuntime(),
+	}
+	if s := i.checksum.Load(); s != nil {
+		rep.ConfigChecksum = *s
+	}
+	if fn := i.templates.Load(); fn != nil {
+		rep.Templates = (*fn)()
+	}
+	return rep
+}
+
+// Handler returns a handler serving the report as JSON.
+func (i *Info) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		render.JSON(w, r, http.StatusOK, i.Report())
+	})
+}
+
+// readBuild reads the embedded build information.
+func readBuild() Build {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Build{GoVersion: runtime.Version()}
+	}
+
+	b := Build{Path: bi.Main.Path, Version: bi.Main.Version, GoVersion: bi.GoVersion}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.Time = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
+
+// readRuntime collects runtime statistics. ReadMemStats stops the world
+// briefly; the endpoint is not meant to be polled at high frequency.
+func readRuntime() Runtime {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return Runtime{
+		Goroutines:  runtime.NumGoroutine(),
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+		NumCPU:      runtime.NumCPU(),
+		HeapAlloc:   ms.HeapAlloc,
+		HeapInuse:   ms.HeapInuse,
+		HeapObjects: ms.HeapObjects,
+		Sys:         ms.Sys,
+		NumGC:       ms.NumGC,
+		PauseTotal:  time.Duration(ms.PauseTotalNs).String(),
+	}
+}
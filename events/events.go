@@ -0,0 +1,238 @@
+package events
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package events provides a lightweight in-process publish/subscribe bus.
+
+Summary
+-------
+- Typed topics: Topic[T] binds a name to an event type, so publishers
+  and subscribers agree on the payload at compile time.
+- Sync subscribers run in the publisher's goroutine, in subscription order.
+- Async subscribers get a bounded queue and their own goroutine; when
+  the queue is full the event is dropped (and counted) instead of
+  blocking the publisher.
+- Subscriber panics are recovered and logged.
+- Bus implements lifecycle.Runner: async queues are drained on shutdown.
+
+Typical usage:
+
+	var UserRegistered = events.NewTopic[User]("auth.registered")
+
+	bus := events.New()
+	events.SubscribeAsync(bus, UserRegistered, 100, func(ctx context.Context, u User) {
+		sendWelcomeMail(u)
+	})
+
+	events.Publish(ctx, bus, UserRegistered, u)
+*/
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Topic is a named event channel carrying events of type T.
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic creates a topic. Topics with the same name share subscribers,
+// so names should be unique per event type (e.g. "auth.login").
+func NewTopic[T any](name string) Topic[T] {
+	return Topic[T]{name: name}
+}
+
+// Name returns the topic name.
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Stats is a snapshot of bus counters.
+type Stats struct {
+	Published uint64 `json:"published"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"` // events dropped because an async queue was full
+	Panics    uint64 `json:"panics"`
+}
+
+// Bus dispatches events to subscribers.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[string][]*subscriber
+	nextID uint64
+	closed bool
+	wg     sync.WaitGroup
+
+	published, delivered, dropped, panics atomic.Uint64
+}
+
+// subscriber is a single subscription.
+type subscriber struct {
+	id    uint64
+	topic string
+	fn    func(ctx context.Context, ev any)
+	queue chan envelope // nil for sync subscribers
+}
+
+// envelope is a queued event.
+type envelope struct {
+	ctx context.Context
+	ev  any
+}
+
+// New creates an empty bus.
+func New() *Bus {
+	return &Bus{subs: map[string][]*subscriber{}}
+}
+
+// Subscribe registers a synchronous subscriber. It returns a function
+// that removes the subscription.
+func Subscribe[T any](b *Bus, t Topic[T], fn func(ctx context.Context, ev T)) (unsubscribe func()) {
+	return b.add(t.name, wrap(fn), 0)
+}
+
+// SubscribeAsync registers an asynchronous subscriber with a queue of
+// the given size (minimum 1). It returns a function that removes the
+// subscription; already queued events are still delivered.
+func SubscribeAsync[T any](b *Bus, t Topic[T], queue int, fn func(ctx context.Context, ev T)) (unsubscribe func()) {
+	return b.add(t.name, wrap(fn), max(queue, 1))
+}
+
+// Publish sends ev to all subscribers of t. Sync subscribers have run
+// when Publish returns. Publishing on a closed bus does nothing.
+func Publish[T any](ctx context.Context, b *Bus, t Topic[T], ev T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+	b.published.Add(1)
+
+	for _, s := range b.subs[t.name] {
+		if s.queue == nil {
+			b.call(s, ctx, ev)
+			continue
+		}
+
+		// Async subscribers outlive the request: keep values, drop cancellation
+		select {
+		case s.queue <- envelope{ctx: context.WithoutCancel(ctx), ev: ev}:
+		default:
+			b.dropped.Add(1)
+			log.Printf("events: queue full, dropped %s event", t.name)
+		}
+	}
+}
+
+// Stats returns a snapshot of the bus counters.
+func (b *Bus) Stats() Stats {
+	return Stats{
+		Published: b.published.Load(),
+		Delivered: b.delivered.Load(),
+		Dropped:   b.dropped.Load(),
+		Panics:    b.panics.Load(),
+	}
+}
+
+// Close stops accepting events and waits until all async queues are
+// drained.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for _, s := range subs {
+			if s.queue != nil {
+				close(s.queue)
+			}
+		}
+	}
+	b.subs = map[string][]*subscriber{}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// Run blocks until ctx is cancelled and then closes the bus.
+// It implements lifecycle.Runner; add the bus before its publishers so
+// it is stopped after them.
+func (b *Bus) Run(ctx context.Context) error {
+	<-ctx.Done()
+	b.Close()
+	return nil
+}
+
+// add registers a subscriber (queue > 0 makes it async).
+func (b *Bus) add(topic string, fn func(context.Context, any), queue int) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	s := &subscriber{id: b.nextID, topic: topic, fn: fn}
+
+	if queue > 0 {
+		s.queue = make(chan envelope, queue)
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for e := range s.queue {
+				b.call(s, e.ctx, e.ev)
+			}
+		}()
+	}
+
+	if !b.closed {
+		b.subs[topic] = append(b.subs[topic], s)
+	} else if s.queue != nil {
+		close(s.queue)
+	}
+
+	return func() { b.remove(s) }
+}
+
+// remove unregisters a subscriber and stops its queue.
+func (b *Bus) remove(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[s.topic]
+	for i, x := range subs {
+		if x.id == s.id {
+			b.subs[s.topic] = append(subs[:i:i], subs[i+1:]...)
+			if s.queue != nil {
+				close(s.queue)
+			}
+			return
+		}
+	}
+}
+
+// call runs a subscriber and recovers panics.
+func (b *Bus) call(s *subscriber, ctx context.Context, ev any) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			b.panics.Add(1)
+			log.Printf("events: subscriber for %s panicked: %v\n%s", s.topic, rec, debug.Stack())
+		}
+	}()
+
+	s.fn(ctx, ev)
+	b.delivered.Add(1)
+}
+
+// wrap converts a typed handler into an untyped one.
+func wrap[T any](fn func(context.Context, T)) func(context.Context, any) {
+	return func(ctx context.Context, ev any) {
+		fn(ctx, ev.(T))
+	}
+}
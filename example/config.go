@@ -11,18 +11,58 @@ Summary
 */
 
 import (
+	"github.com/bennof/gobfwebservice/acl"
+	"github.com/bennof/gobfwebservice/assets"
+	"github.com/bennof/gobfwebservice/audit"
+	"github.com/bennof/gobfwebservice/challenge"
+	"github.com/bennof/gobfwebservice/csrf"
+	"github.com/bennof/gobfwebservice/jwt"
+	"github.com/bennof/gobfwebservice/keys"
 	"github.com/bennof/gobfwebservice/logging"
 	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/mirror"
+	"github.com/bennof/gobfwebservice/module"
+	"github.com/bennof/gobfwebservice/quotas"
+	"github.com/bennof/gobfwebservice/recorder"
+	"github.com/bennof/gobfwebservice/redirects"
+	"github.com/bennof/gobfwebservice/render"
 	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/storage"
 	"github.com/bennof/gobfwebservice/templates"
+	"github.com/bennof/gobfwebservice/timewindow"
 )
 
 // ExampleConfig bundles all configuration sections required by the example service.
 type ExampleConfig struct {
-	Server         server.ServerConfig         `json:"server"`
-	TemplateFolder templates.TemplateSetConfig `json:"templates"`
-	ErrorTemplate  string                      `json:"error_template"`
-	Log            logging.Config              `json:"logging"`
-	Cors           middleware.CORSConfig       `json:"cors"`
-	Rates          middleware.RateLimitConfig  `json:"rate_limit"`
+	Version        int                                `json:"config_version"` // Config format version (see migrate-config)
+	Server         server.ServerConfig                `json:"server"`
+	TemplateFolder templates.TemplateSetConfig        `json:"templates"`
+	ErrorTemplate  string                             `json:"error_template"`
+	Log            logging.Config                     `json:"logging"`
+	Cors           middleware.CORSConfig              `json:"cors"`
+	Rates          middleware.RateLimitConfig         `json:"rate_limit"`
+	Challenge      challenge.Config                   `json:"challenge"`      // Proof-of-work for clients over the rate limit instead of 429
+	Concurrency    middleware.ConcurrencyConfig       `json:"concurrency"`    // Concurrent API requests in total and per client
+	Headers        middleware.HeaderPolicyConfig      `json:"headers"`        // Response header policy by path
+	Compress       middleware.CompressConfig          `json:"compress"`       // gzip responses (not SSE, WebSockets or skipped paths)
+	SecureHeaders  middleware.SecureHeadersConfig     `json:"secure_headers"` // HSTS, nosniff, framing, referrer and content security policy
+	Quotas         quotas.Config                      `json:"quotas"`         // Daily/monthly request quotas per API caller
+	JWT            jwt.Config                         `json:"jwt"`
+	Keys           map[string]keys.Config             `json:"keys,omitempty"`  // Rotating signing keys by purpose (jwt, storage); replace the secrets
+	OpenAPI        bool                               `json:"openapi"`         // Serve /openapi.json and /docs
+	DebugInfo      bool                               `json:"debug_info"`      // Serve /debug/info (build info, runtime stats) to JWT holders
+	Metrics        bool                               `json:"metrics"`         // Serve /metrics (caches, rate limiter, events) to JWT holders
+	TemplateReload bool                               `json:"template_reload"` // Serve POST /admin/templates/reload to JWT holders (see reload-templates)
+	Modules        module.Config                      `json:"modules"`         // Enabled features and their settings (see modules.go)
+	CSRF           csrf.Config                        `json:"csrf"`
+	ACL            acl.Config                         `json:"acl"`              // Path-based access rules
+	Redirects      redirects.Config                   `json:"redirects"`        // Moved URLs from the config or a CSV file
+	TimeWindows    timewindow.Config                  `json:"time_windows"`     // Business hours and maintenance windows by path
+	Recorder       recorder.Config                    `json:"recorder"`         // Sampled request recording (see replay)
+	Storage        storage.Config                     `json:"storage"`          // Upload storage (local disk or S3)
+	Assets         assets.Config                      `json:"assets"`           // CSS/JS bundles (see the assets command)
+	Audit          audit.Config                       `json:"audit"`            // Request body capture for state-changing calls
+	Stacks         map[string][]middleware.StackEntry `json:"stacks,omitempty"` // Middleware stacks by name (page, api, auth); replaces the built-in order
+	Mirror         mirror.Config                      `json:"mirror"`           // Sampled API traffic copied to a shadow backend
+	Warm           render.WarmConfig                  `json:"warm"`             // Pages pre-rendered on start and periodically
 }
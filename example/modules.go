@@ -0,0 +1,152 @@
+package example
+
+/*
+Example features packaged as modules.
+
+Summary
+-------
+- NotesModule serves the notes resource (settings: NotesConfig).
+- AuthModule serves login, registration and account pages
+  (settings: auth.Config) and purges expired sessions in the background.
+- Both modules report their caches to the metrics package
+  (metrics.Collector).
+- Modules returns the registry of all example modules.
+*/
+
+import (
+	"context"
+	"time"
+
+	"github.com/bennof/gobfwebservice/auth"
+	"github.com/bennof/gobfwebservice/cache"
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/metrics"
+	"github.com/bennof/gobfwebservice/module"
+	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+// Modules returns a registry with all example modules rendering
+// HTML pages with tmpl and publishing domain events on bus (both may be
+// nil if no routes are registered).
+func Modules(tmpl *templates.TemplateSet, bus *events.Bus) *module.Registry {
+	return module.NewRegistry(
+		NewNotesModule(tmpl),
+		NewAuthModule(tmpl, bus),
+	)
+}
+
+// DefaultModulesConfig enables all example modules with default settings.
+func DefaultModulesConfig() module.Config {
+	cfg, err := Modules(nil, nil).Defaults()
+	if err != nil {
+		panic(err) // default configs are static and always serializable
+	}
+	return cfg
+}
+
+/* ---------- notes ---------- */
+
+// NotesModule serves the notes resource.
+type NotesModule struct {
+	module.Base
+	config NotesConfig
+	tmpl   *templates.TemplateSet
+	notes  *Notes
+}
+
+// NewNotesModule creates the notes module.
+func NewNotesModule(tmpl *templates.TemplateSet) *NotesModule {
+	return &NotesModule{config: DefaultNotesConfig(), tmpl: tmpl}
+}
+
+// Name returns "notes".
+func (m *NotesModule) Name() string { return "notes" }
+
+// DefaultConfig returns the notes configuration.
+func (m *NotesModule) DefaultConfig() any { return &m.config }
+
+// Register registers the notes pages wrapped with mw.Page and the API;
+// writes require mw.Auth.
+func (m *NotesModule) Register(srv *server.Server, mw module.Middleware) error {
+	m.notes = NewNotes(NewNoteStore(), m.tmpl, m.config)
+	m.notes.Register(srv, mw.Page, mw.API, mw.Auth)
+	return nil
+}
+
+// Collect reports the page cache.
+func (m *NotesModule) Collect(emit func(metrics.Sample)) {
+	if m.notes != nil && m.notes.Pages() != nil {
+		metrics.Cache("notes-pages", m.notes.Pages()).Collect(emit)
+	}
+}
+
+/* ---------- auth ---------- */
+
+// AuthModule serves login, logout, registration and account pages.
+type AuthModule struct {
+	config   auth.Config
+	tmpl     *templates.TemplateSet
+	bus      *events.Bus
+	sessions *cache.Memory[auth.Session]
+	stop     context.CancelFunc
+}
+
+// NewAuthModule creates the auth module with in-memory users and
+// sessions. Auth events are published on bus (may be nil).
+func NewAuthModule(tmpl *templates.TemplateSet, bus *events.Bus) *AuthModule {
+	return &AuthModule{config: auth.DefaultConfig(), tmpl: tmpl, bus: bus}
+}
+
+// Name returns "auth".
+func (m *AuthModule) Name() string { return "auth" }
+
+// DefaultConfig returns the auth configuration.
+func (m *AuthModule) DefaultConfig() any { return &m.config }
+
+// Register registers the auth pages wrapped with mw.Page.
+func (m *AuthModule) Register(srv *server.Server, mw module.Middleware) error {
+	m.sessions = cache.NewMemory[auth.Session](cache.Config{MaxEntries: 100_000})
+
+	a := auth.New(auth.NewMemoryStore(), auth.NewSessions(m.sessions, m.config), m.tmpl, m.config)
+	a.SetEvents(m.bus)
+	a.Register(srv, mw.Page)
+	return nil
+}
+
+// Collect reports the session cache.
+func (m *AuthModule) Collect(emit func(metrics.Sample)) {
+	if m.sessions != nil {
+		metrics.Cache("sessions", m.sessions).Collect(emit)
+	}
+}
+
+// Start purges expired sessions once a minute.
+func (m *AuthModule) Start(ctx context.Context) error {
+	if m.sessions == nil {
+		return nil
+	}
+
+	ctx, m.stop = context.WithCancel(ctx)
+	go func() {
+		t := time.NewTicker(time.Minute)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				m.sessions.Purge()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the session purge.
+func (m *AuthModule) Stop(context.Context) error {
+	if m.stop != nil {
+		m.stop()
+	}
+	return nil
+}
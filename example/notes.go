@@ -0,0 +1,403 @@
+package example
+
+/*
+Notes: a small CRUD resource demonstrating all subsystems together.
+
+Summary
+-------
+- In-memory NoteStore (concurrency-safe) holding notes.
+- HTML pages rendered via the templates package (/notes, /notes/{id});
+  htmx requests receive only the "content" block. Rendered pages are
+  cached (render.PageCache) and purged by every write.
+- JSON API with binding and validation (/api/notes, /api/notes/{id}).
+- Write operations require a valid Bearer JWT (middleware.RequireBearer).
+- Errors are rendered through the server error helpers (HTML) or as
+  JSON error objects (API).
+- Routes are registered with documentation for the OpenAPI generator.
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bennof/gobfwebservice/cache"
+	"github.com/bennof/gobfwebservice/htmx"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/render"
+	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+/* ---------- model ---------- */
+
+// Note is a single note.
+type Note struct {
+	ID      int64     `json:"id"`
+	Title   string    `json:"title"`
+	Body    string    `json:"body"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// NoteInput is the request body for creating or updating a note.
+type NoteInput struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Validate checks the input and returns a field -> message map of problems.
+func (in NoteInput) Validate() map[string]string {
+	problems := map[string]string{}
+
+	title := strings.TrimSpace(in.Title)
+	switch {
+	case title == "":
+		problems["title"] = "is required"
+	case len(title) > 200:
+		problems["title"] = "must be at most 200 characters"
+	}
+	if len(in.Body) > 10000 {
+		problems["body"] = "must be at most 10000 characters"
+	}
+
+	return problems
+}
+
+/* ---------- store ---------- */
+
+// ErrNoteNotFound is returned if a note does not exist.
+var ErrNoteNotFound = errors.New("note not found")
+
+// NoteStore is an in-memory, concurrency-safe note store.
+type NoteStore struct {
+	mu     sync.RWMutex
+	notes  map[int64]Note
+	nextID int64
+}
+
+// NewNoteStore creates an empty store.
+func NewNoteStore() *NoteStore {
+	return &NoteStore{notes: map[int64]Note{}, nextID: 1}
+}
+
+// List returns all notes ordered by ID.
+func (s *NoteStore) List() []Note {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Note, 0, len(s.notes))
+	for _, n := range s.notes {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Get returns the note with the given ID.
+func (s *NoteStore) Get(id int64) (Note, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n, ok := s.notes[id]
+	if !ok {
+		return Note{}, ErrNoteNotFound
+	}
+	return n, nil
+}
+
+// Create stores a new note and returns it.
+func (s *NoteStore) Create(in NoteInput, author string) Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	n := Note{
+		ID:      s.nextID,
+		Title:   strings.TrimSpace(in.Title),
+		Body:    in.Body,
+		Author:  author,
+		Created: now,
+		Updated: now,
+	}
+	s.notes[n.ID] = n
+	s.nextID++
+	return n
+}
+
+// Update replaces title and body of an existing note.
+func (s *NoteStore) Update(id int64, in NoteInput) (Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notes[id]
+	if !ok {
+		return Note{}, ErrNoteNotFound
+	}
+	n.Title = strings.TrimSpace(in.Title)
+	n.Body = in.Body
+	n.Updated = time.Now().UTC()
+	s.notes[id] = n
+	return n, nil
+}
+
+// Delete removes a note.
+func (s *NoteStore) Delete(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.notes[id]; !ok {
+		return ErrNoteNotFound
+	}
+	delete(s.notes, id)
+	return nil
+}
+
+/* ---------- handlers ---------- */
+
+// NotesConfig defines the configuration of the notes resource.
+type NotesConfig struct {
+	MaxBodyBytes int64 `json:"max_body_bytes"` // Maximum accepted JSON request body size
+	PageCacheTTL int   `json:"page_cache_ttl"` // Seconds rendered HTML pages are cached; 0 disables the cache
+}
+
+// DefaultNotesConfig returns the default notes configuration.
+func DefaultNotesConfig() NotesConfig {
+	return NotesConfig{
+		MaxBodyBytes: 64 << 10,
+		PageCacheTTL: 300,
+	}
+}
+
+// Notes serves the notes resource as HTML pages and JSON API.
+type Notes struct {
+	config NotesConfig
+	store  *NoteStore
+	tmpl   *templates.TemplateSet
+	pages  *render.PageCache // nil if disabled
+}
+
+// NewNotes creates the notes resource backed by store and rendering
+// HTML pages with tmpl (views notes.html and note.html).
+func NewNotes(store *NoteStore, tmpl *templates.TemplateSet, cfg ...NotesConfig) *Notes {
+	c := DefaultNotesConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	n := &Notes{config: c, store: store, tmpl: tmpl}
+	if c.PageCacheTTL > 0 {
+		n.pages = render.NewPageCache(cache.NewMemory[render.CachedPage](), time.Duration(c.PageCacheTTL)*time.Second)
+	}
+	return n
+}
+
+// Pages returns the page cache (nil if disabled).
+func (n *Notes) Pages() *render.PageCache {
+	return n.pages
+}
+
+// Register registers all notes routes on srv. page wraps the HTML pages
+// (e.g. recovery, request ID, logging, CSRF), outside the page cache;
+// api wraps every API route (e.g. request ID, logging, CORS); auth
+// additionally wraps write routes.
+func (n *Notes) Register(srv *server.Server, page, api, auth middleware.Middleware) {
+	write := middleware.Chain(api, auth)
+
+	// HTML pages
+	if n.pages != nil {
+		page = middleware.Chain(page, middleware.Named("page-cache", n.pages.Middleware(), n.config.PageCacheTTL))
+	}
+	srv.Handle("GET /notes", page(http.HandlerFunc(n.listPage)), server.RouteDoc{
+		Summary: "List notes (HTML)", Tags: []string{"notes", "html"},
+	})
+	srv.Handle("GET /notes/{id}", page(http.HandlerFunc(n.showPage)), server.RouteDoc{
+		Summary: "Show a note (HTML)", Tags: []string{"notes", "html"},
+	})
+
+	// JSON API
+	srv.Handle("GET /api/notes", api(http.HandlerFunc(n.list)), server.RouteDoc{
+		Summary: "List notes", Tags: []string{"notes"}, Response: []Note{},
+	})
+	srv.Handle("POST /api/notes", write(http.HandlerFunc(n.create)), server.RouteDoc{
+		Summary: "Create a note", Tags: []string{"notes"}, Request: NoteInput{}, Response: Note{},
+	})
+	srv.Handle("GET /api/notes/{id}", api(http.HandlerFunc(n.get)), server.RouteDoc{
+		Summary: "Get a note", Tags: []string{"notes"}, Response: Note{},
+	})
+	srv.Handle("PUT /api/notes/{id}", write(http.HandlerFunc(n.update)), server.RouteDoc{
+		Summary: "Update a note", Tags: []string{"notes"}, Request: NoteInput{}, Response: Note{},
+	})
+	srv.Handle("DELETE /api/notes/{id}", write(http.HandlerFunc(n.delete)), server.RouteDoc{
+		Summary: "Delete a note", Tags: []string{"notes"},
+	})
+}
+
+// listPage renders all notes as HTML.
+func (n *Notes) listPage(w http.ResponseWriter, r *http.Request) {
+	n.renderPage(w, r, "notes.html", map[string]any{
+		"Title": "Notes",
+		"Notes": n.store.List(),
+	})
+}
+
+// showPage renders a single note as HTML.
+func (n *Notes) showPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		server.NotFound(w, r)
+		return
+	}
+
+	note, err := n.store.Get(id)
+	if err != nil {
+		server.NotFound(w, r)
+		return
+	}
+
+	n.renderPage(w, r, "note.html", map[string]any{
+		"Title": note.Title,
+		"Note":  note,
+	})
+}
+
+// list returns all notes.
+func (n *Notes) list(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, http.StatusOK, n.store.List())
+}
+
+// get returns a single note.
+func (n *Notes) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := noteID(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := n.store.Get(id)
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	render.JSON(w, r, http.StatusOK, note)
+}
+
+// create stores a new note from the request body.
+func (n *Notes) create(w http.ResponseWriter, r *http.Request) {
+	in, ok := n.bind(w, r)
+	if !ok {
+		return
+	}
+
+	author := ""
+	if claims, ok := middleware.GetBearerClaimsMap(r.Context()); ok {
+		author, _ = claims["sub"].(string)
+	}
+
+	note := n.store.Create(in, author)
+	n.purge(r, note.ID)
+	w.Header().Set("Location", "/api/notes/"+strconv.FormatInt(note.ID, 10))
+	render.JSON(w, r, http.StatusCreated, note)
+}
+
+// update replaces an existing note from the request body.
+func (n *Notes) update(w http.ResponseWriter, r *http.Request) {
+	id, ok := noteID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := n.bind(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := n.store.Update(id, in)
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	n.purge(r, id)
+	render.JSON(w, r, http.StatusOK, note)
+}
+
+// delete removes a note.
+func (n *Notes) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := noteID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := n.store.Delete(id); err != nil {
+		writeJSONError(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	n.purge(r, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* ---------- helpers ---------- */
+
+// bind decodes and validates a NoteInput request body. On failure an
+// error response is written and false is returned.
+func (n *Notes) bind(w http.ResponseWriter, r *http.Request) (NoteInput, bool) {
+	var in NoteInput
+
+	r.Body = http.MaxBytesReader(w, r.Body, n.config.MaxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&in); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid JSON body: "+err.Error(), nil)
+		return in, false
+	}
+
+	if problems := in.Validate(); len(problems) > 0 {
+		writeJSONError(w, r, http.StatusUnprocessableEntity, "validation failed", problems)
+		return in, false
+	}
+
+	return in, true
+}
+
+// purge removes the cached list page and the page of note id.
+func (n *Notes) purge(r *http.Request, id int64) {
+	if n.pages == nil {
+		return
+	}
+	if err := n.pages.InvalidatePaths(r.Context(), "/notes", "/notes/"+strconv.FormatInt(id, 10)); err != nil {
+		log.Printf("notes: purge pages: %v", err)
+	}
+}
+
+// renderPage renders an HTML view (only its "content" block for htmx
+// requests) or falls back to a 500 error page.
+func (n *Notes) renderPage(w http.ResponseWriter, r *http.Request, view string, data any) {
+	if err := htmx.Render(w, r, n.tmpl, view, "content", data); err != nil {
+		log.Printf("render %s: %v", view, err)
+		server.InternalServerError(w, r)
+	}
+}
+
+// noteID parses the {id} path value or writes a 404 JSON error.
+func noteID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, ErrNoteNotFound.Error(), nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJSONError writes a JSON error object including the request ID.
+func writeJSONError(w http.ResponseWriter, r *http.Request, code int, msg string, fields map[string]string) {
+	render.JSON(w, r, code, map[string]any{
+		"error":      msg,
+		"fields":     fields,
+		"request_id": middleware.GetRequestID(r.Context()),
+	})
+}
@@ -0,0 +1,188 @@
+package form
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package form binds HTML form submissions into structs and handles uploads.
+
+Summary
+-------
+- Bind decodes URL-encoded forms (and query parameters) into a struct
+  using `form:"name"` tags.
+- ParseMultipart streams multipart/form-data bodies part by part:
+  small files stay in memory, larger ones spill to temporary files.
+- Upload limits (per file, total, file count) and allowlists for file
+  extensions and sniffed content types are enforced while streaming.
+- Configured via a JSON-serializable Config struct.
+
+Typical usage:
+
+	var in SignupForm
+	if err := form.Bind(r, &in); err != nil {
+		server.BadRequest(w, r)
+		return
+	}
+
+	mp, err := form.ParseMultipart(r, cfg)
+	if err != nil { ... }
+	defer mp.Cleanup()
+	avatar := mp.File("avatar")
+*/
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the binding and upload helpers.
+var (
+	ErrNotStructPointer    = errors.New("form: destination must be a pointer to a struct")
+	ErrTooLarge            = errors.New("form: upload too large")
+	ErrTooManyFiles        = errors.New("form: too many files")
+	ErrExtensionNotAllowed = errors.New("form: file extension not allowed")
+	ErrTypeNotAllowed      = errors.New("form: file type not allowed")
+)
+
+// Bind parses the request form (URL-encoded body and query string) and
+// decodes it into dst, a pointer to a struct.
+func Bind(r *http.Request, dst any) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	return Decode(r.Form, dst)
+}
+
+// Decode decodes form values into dst, a pointer to a struct.
+//
+// Fields are matched by their `form:"name"` tag or, without a tag, by
+// their field name. `form:"-"` skips a field. Supported field types are
+// string, bool, integers, floats, pointers to these and slices of these.
+func Decode(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrNotStructPointer
+	}
+	return decodeStruct(values, v.Elem())
+}
+
+// decodeStruct assigns values to the fields of struct value v.
+func decodeStruct(values url.Values, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+
+		if err := setField(v.Field(i), vals); err != nil {
+			return fmt.Errorf("form: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField assigns one or more raw values to a field.
+func setField(field reflect.Value, vals []string) error {
+	switch field.Kind() {
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Pointer {
+			return nil // e.g. []*File, set by Multipart.Bind
+		}
+		s := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+		for i, raw := range vals {
+			if err := setScalar(s.Index(i), raw); err != nil {
+				return err
+			}
+		}
+		field.Set(s)
+		return nil
+
+	case reflect.Pointer:
+		if field.Type().Elem().Kind() == reflect.Struct {
+			return nil // e.g. *File, set by Multipart.Bind
+		}
+		p := reflect.New(field.Type().Elem())
+		if err := setScalar(p.Elem(), vals[0]); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
+	}
+
+	return setScalar(field, vals[0])
+}
+
+// setScalar parses raw into a scalar field.
+func setScalar(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+
+	case reflect.Bool:
+		// HTML checkboxes submit "on"
+		b := raw == "on"
+		if !b && raw != "" {
+			var err error
+			if b, err = strconv.ParseBool(raw); err != nil {
+				return err
+			}
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package form
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Streaming multipart/form-data parsing with upload limits.
+
+Summary
+-------
+- Reads the body part by part via http.Request.MultipartReader, so large
+  uploads are never buffered completely in memory.
+- Files up to Config.MaxMemory stay in memory; larger files are written
+  to temporary files in Config.TempDir.
+- The content type of each file is sniffed from its first 512 bytes
+  (http.DetectContentType) and checked against the allowlists.
+- Temporary files are removed by Multipart.Cleanup, which runs
+  automatically after the response if the request is served through
+  middleware.Cleanup.
+*/
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Config defines upload limits and allowlists for multipart parsing.
+// All fields are JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	MaxMemory         int64    `json:"max_memory"`         // Files up to this size stay in memory (bytes); 0 writes all files to disk
+	MaxFileSize       int64    `json:"max_file_size"`      // Maximum size per file (bytes); 0 is unlimited
+	MaxTotalSize      int64    `json:"max_total_size"`     // Maximum size of all parts (bytes); 0 is unlimited
+	MaxFiles          int      `json:"max_files"`          // Maximum number of files
+	AllowedExtensions []string `json:"allowed_extensions"` // e.g. [".png", ".jpg"]; empty allows all
+	AllowedTypes      []string `json:"allowed_types"`      // Sniffed MIME types, e.g. ["image/png"]; empty allows all
+	TempDir           string   `json:"temp_dir"`           // Directory for spilled files; empty uses os.TempDir
+}
+
+// DefaultConfig returns conservative upload limits.
+func DefaultConfig() Config {
+	return Config{
+		MaxMemory:         1 << 20,  // 1 MiB
+		MaxFileSize:       10 << 20, // 10 MiB
+		MaxTotalSize:      32 << 20, // 32 MiB
+		MaxFiles:          10,
+		AllowedExtensions: nil,
+		AllowedTypes:      nil,
+		TempDir:           "",
+	}
+}
+
+// File is an uploaded file, held in memory or in a temporary file.
+type File struct {
+	Field       string // Form field name
+	Filename    string // Client-provided file name (base name only)
+	ContentType string // Sniffed content type
+	Size        int64  // Size in bytes
+
+	data []byte // in-memory content
+	path string // temporary file path if spilled to disk
+}
+
+// Open returns a reader for the file content.
+func (f *File) Open() (io.ReadCloser, error) {
+	if f.path != "" {
+		return os.Open(f.path)
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+// Path returns the temporary file path, or "" if the file is in memory.
+func (f *File) Path() string {
+	return f.path
+}
+
+// SaveTo copies the file content to dst.
+func (f *File) SaveTo(dst string) error {
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Remove deletes the temporary file, if any.
+func (f *File) Remove() error {
+	if f.path == "" {
+		return nil
+	}
+	err := os.Remove(f.path)
+	f.path = ""
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Multipart holds the parsed values and files of a multipart form.
+type Multipart struct {
+	Values url.Values
+	Files  map[string][]*File
+}
+
+// File returns the first file uploaded for field, or nil.
+func (m *Multipart) File(field string) *File {
+	if fs := m.Files[field]; len(fs) > 0 {
+		return fs[0]
+	}
+	return nil
+}
+
+// Bind decodes the form values into dst (see Decode) and assigns
+// uploaded files to fields of type *File or []*File.
+func (m *Multipart) Bind(dst any) error {
+	if err := Decode(m.Values, dst); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+	fileType := reflect.TypeOf(&File{})
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get("form")
+		if name == "" {
+			name = f.Name
+		}
+
+		files := m.Files[name]
+		if len(files) == 0 || !f.IsExported() {
+			continue
+		}
+
+		switch f.Type {
+		case fileType:
+			v.Field(i).Set(reflect.ValueOf(files[0]))
+		case reflect.SliceOf(fileType):
+			v.Field(i).Set(reflect.ValueOf(files))
+		}
+	}
+	return nil
+}
+
+// Cleanup removes all temporary files. Call it (deferred) once the
+// uploads have been processed or moved, unless the request is served
+// through middleware.Cleanup (see ParseMultipart).
+func (m *Multipart) Cleanup() {
+	for _, files := range m.Files {
+		for _, f := range files {
+			_ = f.Remove()
+		}
+	}
+}
+
+// ParseMultipart streams a multipart/form-data request body into a
+// Multipart, enforcing the configured limits. On error, already written
+// temporary files are removed; otherwise they are removed after the
+// response if the request has a cleanup scope (middleware.Cleanup).
+func ParseMultipart(r *http.Request, cfg ...Config) (*Multipart, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Multipart{Values: url.Values{}, Files: map[string][]*File{}}
+	var total int64
+	var count int
+
+	fail := func(err error) (*Multipart, error) {
+		m.Cleanup()
+		return nil, err
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(err)
+		}
+
+		// Plain form value
+		if part.FileName() == "" {
+			var b []byte
+			if c.MaxTotalSize > 0 {
+				b, err = readLimited(part, c.MaxTotalSize-total)
+			} else {
+				b, err = io.ReadAll(part)
+			}
+			part.Close()
+			if err != nil {
+				return fail(err)
+			}
+			total += int64(len(b))
+			m.Values.Add(part.FormName(), string(b))
+			continue
+		}
+
+		// File part
+		count++
+		if c.MaxFiles > 0 && count > c.MaxFiles {
+			part.Close()
+			return fail(ErrTooManyFiles)
+		}
+
+		f, err := readFile(part, c, c.MaxTotalSize-total)
+		part.Close()
+		if err != nil {
+			return fail(err)
+		}
+		total += f.Size
+		m.Files[f.Field] = append(m.Files[f.Field], f)
+	}
+
+	middleware.OnFinishRequest(r.Context(), m.Cleanup)
+	return m, nil
+}
+
+// readFile streams a file part into memory or a temporary file.
+func readFile(part *multipart.Part, c Config, remaining int64) (*File, error) {
+	f := &File{
+		Field:    part.FormName(),
+		Filename: filepath.Base(part.FileName()),
+	}
+
+	if !extensionAllowed(f.Filename, c.AllowedExtensions) {
+		return nil, ErrExtensionNotAllowed
+	}
+
+	// Maximum size of this file; -1 is unlimited
+	limit := int64(-1)
+	if c.MaxFileSize > 0 {
+		limit = c.MaxFileSize
+	}
+	if c.MaxTotalSize > 0 && (limit < 0 || remaining < limit) {
+		limit = max(remaining, 0)
+	}
+
+	// Sniff the content type from the first bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	f.ContentType = http.DetectContentType(head)
+	if !typeAllowed(f.ContentType, c.AllowedTypes) {
+		return nil, ErrTypeNotAllowed
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), part)
+
+	// Keep small files in memory
+	var buf []byte
+	if c.MaxMemory > 0 {
+		mem := c.MaxMemory
+		if limit >= 0 {
+			mem = min(mem, limit)
+		}
+		buf, err = readLimited(body, mem)
+		if err == nil {
+			f.data = buf
+			f.Size = int64(len(buf))
+			return f, nil
+		}
+		if !errors.Is(err, ErrTooLarge) || (limit >= 0 && c.MaxMemory >= limit) {
+			return nil, err
+		}
+	}
+
+	// Spill larger files to disk
+	tmp, err := os.CreateTemp(c.TempDir, "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	f.path = tmp.Name()
+
+	src := io.MultiReader(bytes.NewReader(buf), body)
+	if limit >= 0 {
+		src = io.LimitReader(src, limit+1)
+	}
+	written, err := io.Copy(tmp, src)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && limit >= 0 && written > limit {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		_ = f.Remove()
+		return nil, err
+	}
+
+	f.Size = written
+	return f, nil
+}
+
+// readLimited reads r completely if it has at most limit bytes (none
+// if limit <= 0). Otherwise the bytes read so far are returned together
+// with ErrTooLarge.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	limit = max(limit, 0)
+
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return b, ErrTooLarge
+	}
+	return b, nil
+}
+
+// extensionAllowed checks the file extension against the allowlist.
+func extensionAllowed(name string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, a := range allowed {
+		if strings.ToLower(a) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// typeAllowed checks the sniffed content type against the allowlist.
+func typeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	mt, _, _ := strings.Cut(contentType, ";")
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(mt), a) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,4 +2,9 @@ module github.com/bennof/gobfwebservice
 
 go 1.24.3
 
-require github.com/google/uuid v1.6.0 // indirect
+require github.com/google/uuid v1.6.0
+
+require (
+	golang.org/x/crypto v0.48.0
+	golang.org/x/sys v0.41.0 // indirect
+)
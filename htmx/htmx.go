@@ -0,0 +1,217 @@
+package htmx
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package htmx provides helpers for HTMX-driven, server-rendered pages.
+
+Summary
+-------
+- Request detection and accessors for the HX-* request headers
+  (IsRequest, IsBoosted, Target, TriggerName, CurrentURL, ...).
+- Response header helpers (Redirect, Refresh, PushURL, Retarget,
+  Reswap, Trigger, ...).
+- Render sends only a block of a view (e.g. "content") to HTMX requests
+  and the full page to everything else, using the block-rendering API of
+  the templates package.
+
+Typical usage:
+
+	func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	    htmx.Render(w, r, h.tmpl, "notes.html", "content", data)
+	}
+
+	func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	    ...
+	    htmx.Trigger(w, "noteCreated")
+	    htmx.Redirect(w, r, "/notes")
+	}
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/templates"
+)
+
+// Request headers sent by htmx.
+const (
+	HeaderRequest               = "HX-Request"
+	HeaderBoosted               = "HX-Boosted"
+	HeaderCurrentURL            = "HX-Current-URL"
+	HeaderHistoryRestoreRequest = "HX-History-Restore-Request"
+	HeaderPrompt                = "HX-Prompt"
+	HeaderTarget                = "HX-Target"
+	HeaderTriggerName           = "HX-Trigger-Name"
+	HeaderTriggerID             = "HX-Trigger"
+)
+
+// Response headers understood by htmx.
+const (
+	HeaderLocation           = "HX-Location"
+	HeaderPushURL            = "HX-Push-Url"
+	HeaderRedirect           = "HX-Redirect"
+	HeaderRefresh            = "HX-Refresh"
+	HeaderReplaceURL         = "HX-Replace-Url"
+	HeaderReswap             = "HX-Reswap"
+	HeaderRetarget           = "HX-Retarget"
+	HeaderReselect           = "HX-Reselect"
+	HeaderTrigger            = "HX-Trigger"
+	HeaderTriggerAfterSettle = "HX-Trigger-After-Settle"
+	HeaderTriggerAfterSwap   = "HX-Trigger-After-Swap"
+)
+
+/* ---------- request ---------- */
+
+// IsRequest reports whether r was issued by htmx.
+func IsRequest(r *http.Request) bool {
+	return r.Header.Get(HeaderRequest) == "true"
+}
+
+// IsBoosted reports whether r was issued by an hx-boost element.
+// Boosted requests expect a full page.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get(HeaderBoosted) == "true"
+}
+
+// IsHistoryRestore reports whether r restores a page from history after
+// a cache miss. History restores expect a full page.
+func IsHistoryRestore(r *http.Request) bool {
+	return r.Header.Get(HeaderHistoryRestoreRequest) == "true"
+}
+
+// IsPartial reports whether r expects a page fragment: an htmx request
+// that is neither boosted nor a history restore.
+func IsPartial(r *http.Request) bool {
+	return IsRequest(r) && !IsBoosted(r) && !IsHistoryRestore(r)
+}
+
+// CurrentURL returns the browser URL at the time of the request.
+func CurrentURL(r *http.Request) string { return r.Header.Get(HeaderCurrentURL) }
+
+// Prompt returns the user response to an hx-prompt.
+func Prompt(r *http.Request) string { return r.Header.Get(HeaderPrompt) }
+
+// Target returns the id of the target element.
+func Target(r *http.Request) string { return r.Header.Get(HeaderTarget) }
+
+// TriggerName returns the name of the triggering element.
+func TriggerName(r *http.Request) string { return r.Header.Get(HeaderTriggerName) }
+
+// TriggerID returns the id of the triggering element.
+func TriggerID(r *http.Request) string { return r.Header.Get(HeaderTriggerID) }
+
+/* ---------- response ---------- */
+
+// Redirect redirects the client to url. htmx requests get an HX-Redirect
+// header (a full page navigation), other requests a 303 See Other.
+func Redirect(w http.ResponseWriter, r *http.Request, url string) {
+	if IsRequest(r) {
+		w.Header().Set(HeaderRedirect, url)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, url, http.StatusSeeOther)
+}
+
+// Location performs a client-side navigation to url without a full reload.
+func Location(w http.ResponseWriter, url string) {
+	w.Header().Set(HeaderLocation, url)
+}
+
+// Refresh makes the client reload the whole page.
+func Refresh(w http.ResponseWriter) {
+	w.Header().Set(HeaderRefresh, "true")
+}
+
+// PushURL pushes url onto the browser history stack.
+func PushURL(w http.ResponseWriter, url string) {
+	w.Header().Set(HeaderPushURL, url)
+}
+
+// ReplaceURL replaces the current browser URL.
+func ReplaceURL(w http.ResponseWriter, url string) {
+	w.Header().Set(HeaderReplaceURL, url)
+}
+
+// Reswap overrides the swap strategy (e.g. "outerHTML", "beforeend").
+func Reswap(w http.ResponseWriter, swap string) {
+	w.Header().Set(HeaderReswap, swap)
+}
+
+// Retarget overrides the target element with a CSS selector.
+func Retarget(w http.ResponseWriter, selector string) {
+	w.Header().Set(HeaderRetarget, selector)
+}
+
+// Reselect selects the part of the response to swap with a CSS selector.
+func Reselect(w http.ResponseWriter, selector string) {
+	w.Header().Set(HeaderReselect, selector)
+}
+
+// Trigger triggers client-side events once the response is received.
+// Multiple calls accumulate events.
+func Trigger(w http.ResponseWriter, events ...string) {
+	addEvents(w, HeaderTrigger, events)
+}
+
+// TriggerAfterSwap triggers client-side events after the swap step.
+func TriggerAfterSwap(w http.ResponseWriter, events ...string) {
+	addEvents(w, HeaderTriggerAfterSwap, events)
+}
+
+// TriggerAfterSettle triggers client-side events after the settle step.
+func TriggerAfterSettle(w http.ResponseWriter, events ...string) {
+	addEvents(w, HeaderTriggerAfterSettle, events)
+}
+
+// TriggerDetail triggers client-side events carrying details, encoded
+// as the JSON form of HX-Trigger. It replaces previously set triggers.
+func TriggerDetail(w http.ResponseWriter, events map[string]any) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(HeaderTrigger, string(b))
+	return nil
+}
+
+// addEvents appends event names to a comma-separated trigger header.
+func addEvents(w http.ResponseWriter, header string, events []string) {
+	if len(events) == 0 {
+		return
+	}
+	list := strings.Join(events, ", ")
+	if prev := w.Header().Get(header); prev != "" {
+		list = prev + ", " + list
+	}
+	w.Header().Set(header, list)
+}
+
+/* ---------- rendering ---------- */
+
+// Render renders block of view for partial htmx requests and the full
+// view otherwise. Vary: HX-Request is set so caches keep both variants.
+func Render(w http.ResponseWriter, r *http.Request, ts *templates.TemplateSet, view, block string, data any) error {
+	var (
+		buf *bytes.Buffer
+		err error
+	)
+	if IsPartial(r) {
+		buf, err = ts.RenderBlockToBytes(view, block, data)
+	} else {
+		buf, err = ts.RenderToBytes(view, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Add("Vary", HeaderRequest)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
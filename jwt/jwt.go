@@ -0,0 +1,250 @@
+package jwt
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package jwt provides minimal HMAC-signed JSON Web Tokens (HS256/HS384/HS512).
+
+Summary
+-------
+- Signs map-based claims with a shared secret.
+- Parses and verifies tokens (signature, algorithm, exp and nbf).
+- MapParser adapts Parse to middleware.BearerContextMap.
+- With Config.Keys set, tokens are signed with the ring's active key and
+  carry its ID in the "kid" header; Parse verifies with the key of that
+  ID, so keys can be rotated without invalidating issued tokens.
+- Uses only the standard library; asymmetric algorithms are out of scope.
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/keys"
+)
+
+// Config defines the JWT settings of a service.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	Algorithm string `json:"algorithm"` // HS256, HS384 or HS512
+	Secret    string `json:"secret"`    // Shared signing secret
+	Issuer    string `json:"issuer"`    // Default "iss" claim for issued tokens
+	TTL       int    `json:"ttl"`       // Default token lifetime in seconds
+
+	// Keys replaces Secret with a rotating key ring (see package keys).
+	Keys *keys.Ring `json:"-"`
+}
+
+// DefaultConfig returns a default JWT configuration.
+// The secret is empty and must be configured before tokens can be signed.
+func DefaultConfig() Config {
+	return Config{
+		Algorithm: "HS256",
+		Secret:    "",
+		Issuer:    "",
+		TTL:       3600,
+	}
+}
+
+var (
+	// ErrMalformed is returned for tokens that are not three base64url parts.
+	ErrMalformed = errors.New("jwt: malformed token")
+
+	// ErrSignature is returned if the signature does not match.
+	ErrSignature = errors.New("jwt: invalid signature")
+
+	// ErrAlgorithm is returned for unsupported or unexpected algorithms.
+	ErrAlgorithm = errors.New("jwt: unsupported algorithm")
+
+	// ErrExpired is returned if the token is expired or not yet valid.
+	ErrExpired = errors.New("jwt: token expired or not yet valid")
+
+	// ErrNoSecret is returned if no signing secret is configured.
+	ErrNoSecret = errors.New("jwt: no secret configured")
+)
+
+// Sign encodes and signs claims using the configured algorithm and secret.
+// If the config has an issuer or TTL, missing iss/iat/exp claims are added.
+func Sign(cfg Config, claims map[string]any) (string, error) {
+	kid, secret, err := signingKey(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	newHash, err := hasher(cfg.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	c := make(map[string]any, len(claims)+3)
+	now := time.Now().Unix()
+	if cfg.Issuer != "" {
+		c["iss"] = cfg.Issuer
+	}
+	c["iat"] = now
+	if cfg.TTL > 0 {
+		c["exp"] = now + int64(cfg.TTL)
+	}
+	for k, v := range claims {
+		c[k] = v
+	}
+
+	h := map[string]string{"alg": strings.ToUpper(cfg.Algorithm), "typ": "JWT"}
+	if kid != "" {
+		h["kid"] = kid
+	}
+	header, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	signing := encode(header) + "." + encode(payload)
+	return signing + "." + encode(sign(newHash, secret, signing)), nil
+}
+
+// Parse verifies token and returns its claims.
+// The token algorithm must match the configured algorithm.
+func Parse(cfg Config, token string) (map[string]any, error) {
+	if cfg.Secret == "" && cfg.Keys == nil {
+		return nil, ErrNoSecret
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJSON(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(header.Alg, cfg.Algorithm) {
+		return nil, ErrAlgorithm
+	}
+
+	newHash, err := hasher(cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	valid := false
+	for _, secret := range verifyingKeys(cfg, header.Kid) {
+		if hmac.Equal(sig, sign(newHash, secret, parts[0]+"."+parts[1])) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrSignature
+	}
+
+	var claims map[string]any
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	now := float64(time.Now().Unix())
+	if exp, ok := claims["exp"].(float64); ok && now >= exp {
+		return nil, ErrExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return nil, ErrExpired
+	}
+
+	return claims, nil
+}
+
+// MapParser returns a parser for middleware.BearerContextMap that verifies
+// tokens with cfg.
+func MapParser(cfg Config) func(token string) (map[string]any, error) {
+	return func(token string) (map[string]any, error) {
+		return Parse(cfg, token)
+	}
+}
+
+/* ---------- helpers ---------- */
+
+// hasher returns the hash constructor for an HMAC algorithm name.
+func hasher(alg string) (func() hash.Hash, error) {
+	switch strings.ToUpper(alg) {
+	case "HS256", "":
+		return sha256.New, nil
+	case "HS384":
+		return sha512.New384, nil
+	case "HS512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAlgorithm, alg)
+}
+
+// signingKey returns the key ID (empty for Secret) and secret to sign with.
+func signingKey(cfg Config) (string, []byte, error) {
+	if cfg.Keys != nil {
+		k, err := cfg.Keys.Signing()
+		if err != nil {
+			return "", nil, fmt.Errorf("jwt: %w", err)
+		}
+		return k.ID, k.Secret, nil
+	}
+	if cfg.Secret == "" {
+		return "", nil, ErrNoSecret
+	}
+	return "", []byte(cfg.Secret), nil
+}
+
+// verifyingKeys returns the secrets a token with the given key ID may be
+// signed with. Tokens without kid are checked against every key.
+func verifyingKeys(cfg Config, kid string) [][]byte {
+	if cfg.Keys == nil {
+		return [][]byte{[]byte(cfg.Secret)}
+	}
+	var out [][]byte
+	for _, k := range cfg.Keys.Verifying(kid) {
+		out = append(out, k.Secret)
+	}
+	return out
+}
+
+// sign computes the HMAC of data.
+func sign(newHash func() hash.Hash, secret []byte, data string) []byte {
+	mac := hmac.New(newHash, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encode returns the unpadded base64url encoding of b.
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeJSON decodes a base64url JSON segment into v.
+func decodeJSON(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package keys
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package keys loads and rotates symmetric signing keys.
+
+Summary
+-------
+- A Ring holds the keys of one purpose (JWTs, presigned URLs, ...).
+  Every key has an ID; the first active key signs, every key that has
+  not expired verifies, so rotation works with an overlap period:
+
+	1. add the new key at the top with "not_before" in the future and
+	   roll the config out everywhere (it verifies, but does not sign yet)
+	2. once active it signs; set "expires" on the old key to the end of
+	   the overlap (e.g. the longest token lifetime)
+	3. remove the old key after it expired
+
+- Key material comes from the config ("secret"), an environment variable
+  ("env") or a file ("file", e.g. a mounted Kubernetes/Docker secret).
+  Values prefixed with "base64:" are decoded. Keys shorter than MinLength
+  bytes are rejected.
+- Consumers: jwt.Config.Keys (the "kid" header selects the key) and
+  storage.LocalConfig.Keys (presigned URLs carry the key ID). CSRF tokens
+  and session cookies are random values stored server-side and need no
+  key.
+- Update swaps the keys of a Ring in place, e.g. on config reload;
+  consumers holding the Ring see the new keys immediately.
+- Config values named "secret" are masked by config.Redacted.
+
+Example config:
+
+	"keys": {
+	  "jwt": [
+	    { "id": "2026-10", "env": "JWT_KEY_2026_10", "not_before": "2026-10-01T00:00:00Z" },
+	    { "id": "2026-07", "file": "/run/secrets/jwt_2026_07", "expires": "2026-10-02T00:00:00Z" }
+	  ],
+	  "storage": [ { "id": "s1", "secret": "base64:3q2+7w..." } ]
+	}
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MinLength is the minimum key length in bytes.
+const MinLength = 16
+
+// ErrNoKey is returned if a ring has no active signing key.
+var ErrNoKey = errors.New("keys: no active key")
+
+// KeyConfig defines one key. Exactly one of Secret, Env and File is set.
+type KeyConfig struct {
+	ID        string    `json:"id"`
+	Secret    string    `json:"secret,omitempty"`    // key material (masked in redacted config output)
+	Env       string    `json:"env,omitempty"`       // environment variable holding the key
+	File      string    `json:"file,omitempty"`      // file holding the key (trailing newlines are ignored)
+	NotBefore time.Time `json:"not_before,omitzero"` // signs from this time on (verifies before)
+	Expires   time.Time `json:"expires,omitzero"`    // neither signs nor verifies from this time on
+}
+
+// Config lists the keys of a ring, newest first.
+type Config []KeyConfig
+
+// Key is a loaded key.
+type Key struct {
+	ID        string
+	Secret    []byte
+	NotBefore time.Time
+	Expires   time.Time
+}
+
+// active reports whether k may sign at t.
+func (k Key) active(t time.Time) bool {
+	return !t.Before(k.NotBefore) && k.valid(t)
+}
+
+// valid reports whether k may verify at t.
+func (k Key) valid(t time.Time) bool {
+	return k.Expires.IsZero() || t.Before(k.Expires)
+}
+
+// Ring is a rotating set of keys. It is safe for concurrent use.
+type Ring struct {
+	keys atomic.Pointer[[]Key]
+	now  func() time.Time
+}
+
+// Load reads the keys of cfg.
+func Load(cfg Config) (*Ring, error) {
+	r := &Ring{now: time.Now}
+	if err := r.Update(cfg); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Static returns a ring with a single key without ID, e.g. for a legacy
+// "secret" config field.
+func Static(secret string) *Ring {
+	r := &Ring{now: time.Now}
+	r.keys.Store(&[]Key{{Secret: []byte(secret)}})
+	return r
+}
+
+// LoadAll loads a ring per purpose.
+func LoadAll(cfg map[string]Config) (map[string]*Ring, error) {
+	rings := make(map[string]*Ring, len(cfg))
+	for name, c := range cfg {
+		r, err := Load(c)
+		if err != nil {
+			return nil, fmt.Errorf("keys %s: %w", name, err)
+		}
+		rings[name] = r
+	}
+	return rings, nil
+}
+
+// Update replaces the keys of r with those of cfg. On error r is unchanged.
+func (r *Ring) Update(cfg Config) error {
+	ks := make([]Key, 0, len(cfg))
+	seen := map[string]bool{}
+	for i, kc := range cfg {
+		if seen[kc.ID] {
+			return fmt.Errorf("keys: duplicate key id %q", kc.ID)
+		}
+		seen[kc.ID] = true
+
+		secret, err := readSecret(kc)
+		if err != nil {
+			return fmt.Errorf("keys: key %d (%q): %w", i, kc.ID, err)
+		}
+		ks = append(ks, Key{ID: kc.ID, Secret: secret, NotBefore: kc.NotBefore, Expires: kc.Expires})
+	}
+	r.keys.Store(&ks)
+	return nil
+}
+
+// Signing returns the key to sign with: the first active key.
+func (r *Ring) Signing() (Key, error) {
+	now := r.now()
+	for _, k := range r.list() {
+		if k.active(now) {
+			return k, nil
+		}
+	}
+	return Key{}, ErrNoKey
+}
+
+// Verifying returns the unexpired keys with the given ID, or all
+// unexpired keys if id is empty.
+func (r *Ring) Verifying(id string) []Key {
+	now := r.now()
+	var out []Key
+	for _, k := range r.list() {
+		if k.valid(now) && (id == "" || k.ID == id) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Sign returns the HMAC-SHA256 of data with the signing key and its ID.
+func (r *Ring) Sign(data []byte) (id string, sig []byte, err error) {
+	k, err := r.Signing()
+	if err != nil {
+		return "", nil, err
+	}
+	return k.ID, MAC(k.Secret, data), nil
+}
+
+// Verify reports whether sig is the HMAC-SHA256 of data under an
+// unexpired key with the given ID (any key if id is empty).
+func (r *Ring) Verify(id string, data, sig []byte) bool {
+	for _, k := range r.Verifying(id) {
+		if hmac.Equal(sig, MAC(k.Secret, data)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IDs returns the IDs of all keys (for diagnostics; no key material).
+func (r *Ring) IDs() []string {
+	ks := r.list()
+	ids := make([]string, len(ks))
+	for i, k := range ks {
+		ids[i] = k.ID
+	}
+	return ids
+}
+
+// list returns the current keys.
+func (r *Ring) list() []Key {
+	if p := r.keys.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// MAC returns the HMAC-SHA256 of data under secret.
+func MAC(secret, data []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write(data)
+	return m.Sum(nil)
+}
+
+// readSecret resolves the key material of kc.
+func readSecret(kc KeyConfig) ([]byte, error) {
+	var (
+		value   string
+		sources int
+	)
+	if kc.Secret != "" {
+		value = kc.Secret
+		sources++
+	}
+	if kc.Env != "" {
+		v, ok := os.LookupEnv(kc.Env)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s not set", kc.Env)
+		}
+		value = v
+		sources++
+	}
+	if kc.File != "" {
+		b, err := os.ReadFile(kc.File)
+		if err != nil {
+			return nil, err
+		}
+		value = strings.TrimRight(string(b), "\r\n")
+		sources++
+	}
+	if sources != 1 {
+		return nil, errors.New("exactly one of secret, env and file must be set")
+	}
+
+	secret := []byte(value)
+	if enc, ok := strings.CutPrefix(value, "base64:"); ok {
+		b, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return nil, fmt.Errorf("base64: %w", err)
+		}
+		secret = b
+	}
+	if len(secret) < MinLength {
+		return nil, fmt.Errorf("key shorter than %d bytes", MinLength)
+	}
+	return secret, nil
+}
@@ -0,0 +1,205 @@
+package lifecycle
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package lifecycle coordinates the long-running parts of a service.
+
+Summary
+-------
+- Runner is anything that runs until its context is cancelled: HTTP
+  servers, file watchers, job workers, schedulers.
+- Manager starts runners in registration order, propagates the first
+  fatal error and shuts everything down in reverse order, each runner
+  waiting for the ones started after it.
+- Adapters for server.Server, periodic jobs and OS signals (runners.go).
+
+Typical usage:
+
+	m := lifecycle.NewManager(lifecycle.Config{ShutdownTimeout: 30})
+	m.Add("jobs", workers)
+	m.Add("http", lifecycle.Server(srv, 30*time.Second))
+	m.Add("reload", lifecycle.OnSignal(reloadFn, syscall.SIGHUP))
+
+	if err := m.RunUntilSignal(); err != nil {
+		log.Fatal(err)
+	}
+
+On shutdown "reload" stops first, then "http" drains, then "jobs".
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Runner is a long-running component. Run blocks until ctx is cancelled
+// (return nil) or the component fails (return an error, which stops the
+// whole Manager). Returning nil before ctx is cancelled means the runner
+// finished its work; the others keep running.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// RunnerFunc adapts a function to Runner.
+type RunnerFunc func(ctx context.Context) error
+
+// Run calls f(ctx).
+func (f RunnerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Config defines the manager behavior.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	ShutdownTimeout int `json:"shutdown_timeout"` // Seconds to wait for all runners to stop
+}
+
+// DefaultConfig returns the default manager configuration.
+func DefaultConfig() Config {
+	return Config{
+		ShutdownTimeout: 30,
+	}
+}
+
+// Manager runs a group of runners.
+type Manager struct {
+	config Config
+
+	mu      sync.Mutex
+	runners []entry
+	running bool
+}
+
+// entry is a named runner.
+type entry struct {
+	name   string
+	runner Runner
+}
+
+// NewManager creates a manager using the provided configuration.
+// If no configuration is supplied, DefaultConfig() is used.
+func NewManager(cfg ...Config) *Manager {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	return &Manager{config: c}
+}
+
+// Add registers a runner. Runners start in registration order and stop
+// in reverse order. Add panics if the manager is already running.
+func (m *Manager) Add(name string, r Runner) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		panic("lifecycle: Add called on a running manager")
+	}
+	m.runners = append(m.runners, entry{name: name, runner: r})
+}
+
+// RunUntilSignal runs all runners until SIGINT or SIGTERM is received.
+func (m *Manager) RunUntilSignal() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return m.Run(ctx)
+}
+
+// Run starts all runners and blocks until ctx is cancelled or a runner
+// fails. It then stops the runners in reverse order and returns the
+// first fatal error joined with any shutdown errors.
+func (m *Manager) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return errors.New("lifecycle: manager already running")
+	}
+	m.running = true
+	runners := m.runners
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.running = false
+		m.mu.Unlock()
+	}()
+
+	type result struct {
+		index int
+		err   error
+	}
+
+	results := make(chan result, len(runners))
+	cancels := make([]context.CancelFunc, len(runners))
+	done := make([]chan struct{}, len(runners))
+
+	// Start in order. Runner contexts are not derived from ctx, so
+	// cancelling ctx triggers the ordered shutdown below instead of
+	// stopping everything at once.
+	for i, e := range runners {
+		rctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		cancels[i] = cancel
+		done[i] = make(chan struct{})
+
+		go func() {
+			defer close(done[i])
+			err := e.runner.Run(rctx)
+			results <- result{index: i, err: err}
+		}()
+	}
+
+	// Wait for cancellation or the first failure
+	var fatal error
+	for pending := len(runners); pending > 0 && fatal == nil; {
+		select {
+		case <-ctx.Done():
+			log.Println("lifecycle: shutting down")
+			pending = 0
+
+		case res := <-results:
+			pending--
+			name := runners[res.index].name
+			if res.err != nil {
+				fatal = fmt.Errorf("lifecycle: %s: %w", name, res.err)
+				log.Printf("%v; shutting down", fatal)
+			} else {
+				log.Printf("lifecycle: %s finished", name)
+			}
+		}
+	}
+
+	// Stop in reverse order within the shutdown timeout
+	deadline := time.After(time.Duration(m.config.ShutdownTimeout) * time.Second)
+	errs := []error{fatal}
+
+	for i := len(runners) - 1; i >= 0; i-- {
+		cancels[i]()
+		select {
+		case <-done[i]:
+		case <-deadline:
+			for j := i - 1; j >= 0; j-- {
+				cancels[j]()
+			}
+			errs = append(errs, fmt.Errorf("lifecycle: %s did not stop within %ds", runners[i].name, m.config.ShutdownTimeout))
+			return errors.Join(errs...)
+		}
+	}
+
+	// Collect errors reported during shutdown
+	close(results)
+	for res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("lifecycle: %s: %w", runners[res.index].name, res.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
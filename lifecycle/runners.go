@@ -0,0 +1,88 @@
+package lifecycle
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Runner adapters for common components.
+*/
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Server runs srv until the runner context is cancelled, then shuts it
+// down gracefully, waiting at most timeout for open connections.
+func Server(srv *server.Server, timeout time.Duration) Runner {
+	return RunnerFunc(func(ctx context.Context) error {
+		errc, err := srv.StartAsync()
+		if err != nil {
+			// The start failed (e.g. address in use)
+			return err
+		}
+		log.Printf("Server listening on %s", srv.Addr())
+
+		select {
+		case err := <-errc:
+			return err
+
+		case <-ctx.Done():
+			sctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if err := srv.Shutdown(sctx); err != nil {
+				return err
+			}
+			if err := <-errc; err != nil {
+				return err
+			}
+			log.Println("Server stopped gracefully")
+			return nil
+		}
+	})
+}
+
+// Every runs fn every interval until the runner context is cancelled.
+// Errors are logged and do not stop the manager.
+func Every(interval time.Duration, fn func(ctx context.Context) error) Runner {
+	return RunnerFunc(func(ctx context.Context) error {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-t.C:
+				if err := fn(ctx); err != nil {
+					log.Printf("lifecycle: periodic job: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// OnSignal calls fn whenever one of sigs is received (e.g. SIGHUP for
+// reloading) until the runner context is cancelled.
+func OnSignal(fn func(), sigs ...os.Signal) Runner {
+	return RunnerFunc(func(ctx context.Context) error {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, sigs...)
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ch:
+				fn()
+			}
+		}
+	})
+}
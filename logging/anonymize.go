@@ -0,0 +1,107 @@
+package logging
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Client IP anonymization for logs.
+
+Summary
+-------
+- ClientIP formats a remote address for log output according to
+  Config.AnonymizeIP, so personal data never reaches the access or
+  audit log:
+  - "" or "none": the IP is logged unchanged
+  - "truncate":   the host part is zeroed (IPv4 /24, IPv6 /64)
+  - "hash":       a keyed hash (HMAC-SHA256, 12 hex characters); the
+                  same client gets the same pseudonym, so requests can
+                  still be correlated without storing the IP
+- The hash key is Config.IPHashSecret. If empty, a random key is
+  generated per process: pseudonyms then change on every restart and
+  cannot be linked across log files.
+- Ports are always dropped.
+- Anonymization only affects log output; rate limiting and access
+  rules still see the real address.
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// IP anonymization modes accepted by Config.AnonymizeIP.
+const (
+	AnonymizeNone     = "none"
+	AnonymizeTruncate = "truncate"
+	AnonymizeHash     = "hash"
+)
+
+// anonymizer is the active IP anonymization setting.
+type anonymizer struct {
+	mode string
+	key  []byte
+}
+
+// ipAnonymizer holds the setting applied by Init.
+var ipAnonymizer atomic.Pointer[anonymizer]
+
+// setAnonymizer applies the IP anonymization settings of cfg.
+func setAnonymizer(cfg Config) {
+	a := &anonymizer{mode: strings.ToLower(cfg.AnonymizeIP), key: []byte(cfg.IPHashSecret)}
+	if a.mode == AnonymizeHash && len(a.key) == 0 {
+		a.key = make([]byte, 32)
+		rand.Read(a.key)
+	}
+	ipAnonymizer.Store(a)
+}
+
+// ClientIP returns addr ("ip" or "ip:port") anonymized according to the
+// configuration passed to Init.
+func ClientIP(addr string) string {
+	a := ipAnonymizer.Load()
+	if a == nil {
+		return AnonymizeIP(addr, AnonymizeNone, nil)
+	}
+	return AnonymizeIP(addr, a.mode, a.key)
+}
+
+// AnonymizeIP returns addr ("ip" or "ip:port") without port, anonymized
+// with mode (see package documentation) and key (hash mode only).
+// Values that are not IP addresses are truncated to "-" unless mode is
+// none.
+func AnonymizeIP(addr, mode string, key []byte) string {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+
+	switch strings.ToLower(mode) {
+	case AnonymizeTruncate:
+		ip, err := netip.ParseAddr(host)
+		if err != nil {
+			return "-"
+		}
+		ip = ip.Unmap()
+		bits := 64
+		if ip.Is4() {
+			bits = 24
+		}
+		p, _ := ip.Prefix(bits)
+		return p.Addr().String()
+
+	case AnonymizeHash:
+		if _, err := netip.ParseAddr(host); err != nil {
+			return "-"
+		}
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(host))
+		return hex.EncodeToString(m.Sum(nil)[:6])
+	}
+	return host
+}
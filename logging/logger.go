@@ -12,13 +12,23 @@ Summary
 - Supports logging to stdout and optionally to a file at the same time.
 - Can be fully configured via a JSON-serializable Config struct.
 - Designed to integrate cleanly with a central application config.
+- Supports custom timestamp formats (RFC 3339, epoch millis, Go layouts).
+- Optionally buffers file output; Flush and Close release it on shutdown.
+- Optionally anonymizes client IPs in log output (see anonymize.go).
+- Prefixes std log output written during a request with its request ID,
+  also for third-party libraries (see tag.go).
 - Keeps dependencies minimal and relies only on the standard library.
 */
 
 import (
+	"bufio"
 	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Config defines the runtime configuration for the global logger.
@@ -30,8 +40,26 @@ type Config struct {
 	Flags     int    `json:"flags"`      // Explicit log flags (overrides computed flags if set)
 	UTC       bool   `json:"utc"`        // Use UTC timestamps
 	ShortFile bool   `json:"short_file"` // Include short file name and line number
+	Buffer    int    `json:"buffer"`     // File write buffer size in bytes; 0 writes through
+
+	// TimestampFormat replaces the standard date/time flags with a custom
+	// timestamp prefix: "rfc3339", "rfc3339nano", "epoch_ms", or any Go
+	// time layout (e.g. "2006-01-02 15:04:05.000"). Empty keeps the std flags.
+	TimestampFormat string `json:"timestamp_format"`
+
+	AnonymizeIP  string `json:"anonymize_ip"`   // Client IPs in logs: "none", "truncate" or "hash" (see ClientIP)
+	IPHashSecret string `json:"ip_hash_secret"` // Key for "hash"; empty uses a random key per process
+
+	RequestIDs bool `json:"request_ids"` // Prefix std log output written while handling a request with its ID (see Tag)
 }
 
+// Predefined timestamp formats accepted by Config.TimestampFormat.
+const (
+	TimestampRFC3339     = "rfc3339"
+	TimestampRFC3339Nano = "rfc3339nano"
+	TimestampEpochMillis = "epoch_ms"
+)
+
 // DefaultConfig returns a sane default logger configuration.
 // These defaults are suitable for most production services.
 func DefaultConfig() Config {
@@ -42,9 +70,46 @@ func DefaultConfig() Config {
 		Flags:     0,
 		UTC:       true,
 		ShortFile: false,
+		Buffer:    0,
+
+		TimestampFormat: "",
+
+		AnonymizeIP:  AnonymizeNone,
+		IPHashSecret: "",
+
+		RequestIDs: true,
 	}
 }
 
+var (
+	// mu guards the currently opened log file and its buffer.
+	mu sync.Mutex
+
+	// file is the log file opened by Init, if any.
+	file *os.File
+
+	// buffer wraps file when buffered output is configured.
+	buffer *bufio.Writer
+)
+
+// fileWriter serializes writes to the optional file buffer so that Flush
+// and Close can safely run concurrently with log output.
+type fileWriter struct{}
+
+// Write appends p to the current log file (buffered or direct).
+func (fileWriter) Write(p []byte) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	switch {
+	case buffer != nil:
+		return buffer.Write(p)
+	case file != nil:
+		return file.Write(p)
+	}
+	return len(p), nil
+}
+
 // Init configures the global default logger according to the provided config.
 // After calling Init, all existing log.Print*, log.Fatal*, and log.Panic*
 // calls will use the configured output and flags.
@@ -55,6 +120,14 @@ func Init(c ...Config) error {
 		cfg = c[0]
 	}
 
+	setAnonymizer(cfg)
+	tagging.Store(cfg.Enabled && cfg.RequestIDs)
+
+	// Release a file opened by a previous Init call
+	if err := Close(); err != nil {
+		return err
+	}
+
 	// Disable logging entirely if requested
 	if !cfg.Enabled {
 		log.SetOutput(io.Discard)
@@ -75,8 +148,26 @@ func Init(c ...Config) error {
 			return err
 		}
 
+		mu.Lock()
+		file = f
+		if cfg.Buffer > 0 {
+			buffer = bufio.NewWriterSize(f, cfg.Buffer)
+		}
+		mu.Unlock()
+
 		// Write logs to both stdout and file
-		out = io.MultiWriter(os.Stdout, f)
+		out = io.MultiWriter(os.Stdout, fileWriter{})
+	}
+
+	// Prefix each record with a custom timestamp if configured
+	if cfg.TimestampFormat != "" {
+		out = &timestampWriter{out: out, format: cfg.TimestampFormat, utc: cfg.UTC}
+	}
+
+	// Tag records written while handling a request (see tag.go); the
+	// tag goes after the std header, i.e. after the custom timestamp
+	if cfg.RequestIDs {
+		out = &tagWriter{out: out, flags: resolveFlags(cfg)}
 	}
 
 	log.SetOutput(out)
@@ -85,6 +176,49 @@ func Init(c ...Config) error {
 	return nil
 }
 
+// Flush writes any buffered log records to the log file and syncs it to disk.
+// It is a no-op if no log file is configured.
+func Flush() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return flush()
+}
+
+// Close flushes and closes the log file opened by Init.
+// Subsequent log output goes to stdout only. It is safe to call Close
+// multiple times or without a configured log file.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return nil
+	}
+
+	err := flush()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+
+	file = nil
+	buffer = nil
+	return err
+}
+
+// flush drains the buffer and syncs the file. The caller must hold mu.
+func flush() error {
+	if file == nil {
+		return nil
+	}
+	if buffer != nil {
+		if err := buffer.Flush(); err != nil {
+			return err
+		}
+	}
+	return file.Sync()
+}
+
 // resolveFlags computes log flags from the configuration.
 // If cfg.Flags is non-zero, it overrides all computed flags.
 func resolveFlags(cfg Config) int {
@@ -102,5 +236,51 @@ func resolveFlags(cfg Config) int {
 		flags = cfg.Flags
 	}
 
+	// A custom timestamp format replaces the std date/time prefix
+	if cfg.TimestampFormat != "" {
+		flags &^= log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC
+	}
+
 	return flags
 }
+
+// timestampWriter prepends a formatted timestamp to every log record.
+// The standard logger issues exactly one Write per record, so the prefix
+// is added once per line.
+type timestampWriter struct {
+	out    io.Writer
+	format string
+	utc    bool
+}
+
+// Write prefixes p with the current timestamp and forwards it.
+func (t *timestampWriter) Write(p []byte) (int, error) {
+	now := time.Now()
+	if t.utc {
+		now = now.UTC()
+	}
+
+	line := make([]byte, 0, len(p)+40)
+	line = append(line, formatTimestamp(now, t.format)...)
+	line = append(line, ' ')
+	line = append(line, p...)
+
+	if _, err := t.out.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// formatTimestamp renders ts according to a predefined format name
+// or a Go time layout.
+func formatTimestamp(ts time.Time, format string) string {
+	switch strings.ToLower(format) {
+	case TimestampRFC3339:
+		return ts.Format(time.RFC3339)
+	case TimestampRFC3339Nano:
+		return ts.Format(time.RFC3339Nano)
+	case TimestampEpochMillis:
+		return strconv.FormatInt(ts.UnixMilli(), 10)
+	}
+	return ts.Format(format)
+}
@@ -0,0 +1,38 @@
+package logging
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Key/value summaries, e.g. the startup report of a server.
+
+Summary
+-------
+- Summary logs a title followed by one record per field with aligned
+  values. Each line is a separate record, so it carries the configured
+  timestamp and prefix and stays greppable ("tls:", "middleware:").
+*/
+
+import (
+	"log"
+	"strings"
+)
+
+// Field is one line of a Summary.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Summary logs title and fields through the global logger.
+func Summary(title string, fields []Field) {
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.Key))
+	}
+
+	log.Print(title)
+	for _, f := range fields {
+		log.Printf("  %s:%s %s", f.Key, strings.Repeat(" ", width-len(f.Key)), f.Value)
+	}
+}
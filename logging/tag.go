@@ -0,0 +1,137 @@
+package logging
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Request tags for output of the standard logger.
+
+Summary
+-------
+- Libraries logging with log.Printf know nothing about the request they
+  run in. Tag associates a tag (the request ID, see middleware.RequestID)
+  with the calling goroutine; while it is set, every record written by
+  that goroutine through the standard logger gets "[tag] " in front of
+  its message.
+- The tag is bound to the goroutine handling the request: output of
+  goroutines started by the handler is not tagged.
+- Records already containing the tag (e.g. the access log line with its
+  rid= field) are left unchanged.
+- Enabled with Config.RequestIDs. The goroutine is looked up only while
+  at least one tag is set.
+
+Example output:
+
+	2026/10/16 08:41:07 [5f0c9a2e-...] sql: slow query (1.2s)
+*/
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// tagging is set by Init if Config.RequestIDs is enabled.
+	tagging atomic.Bool
+
+	// tags maps goroutine IDs to tags; active counts its entries.
+	tags   sync.Map
+	active atomic.Int64
+)
+
+// Tag tags the standard log output of the calling goroutine until the
+// returned function is called. It does nothing unless Config.RequestIDs
+// is enabled.
+func Tag(tag string) (untag func()) {
+	if !tagging.Load() || tag == "" {
+		return func() {}
+	}
+
+	id := goid()
+	tags.Store(id, tag)
+	active.Add(1)
+	return func() {
+		tags.Delete(id)
+		active.Add(-1)
+	}
+}
+
+// tagWriter inserts the tag of the writing goroutine after the header
+// of every record written by the standard logger.
+type tagWriter struct {
+	out   io.Writer
+	flags int
+}
+
+// Write forwards p with the tag of the current goroutine, if any.
+func (t *tagWriter) Write(p []byte) (int, error) {
+	if active.Load() == 0 {
+		return t.out.Write(p)
+	}
+	v, ok := tags.Load(goid())
+	if !ok {
+		return t.out.Write(p)
+	}
+	tag := v.(string)
+	if bytes.Contains(p, []byte(tag)) {
+		return t.out.Write(p)
+	}
+
+	n := headerLen(p, t.flags)
+	line := make([]byte, 0, len(p)+len(tag)+3)
+	line = append(line, p[:n]...)
+	line = append(line, '[')
+	line = append(line, tag...)
+	line = append(line, "] "...)
+	line = append(line, p[n:]...)
+
+	if _, err := t.out.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// headerLen returns the length of the header the standard logger wrote
+// in front of the message for the given flags.
+func headerLen(p []byte, flags int) int {
+	n := len(log.Prefix())
+	if flags&log.Lmsgprefix != 0 {
+		n = 0
+	}
+	if flags&log.Ldate != 0 {
+		n += len("2006/01/02 ")
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		n += len("15:04:05 ")
+		if flags&log.Lmicroseconds != 0 {
+			n += len(".000000")
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 && n <= len(p) {
+		if i := bytes.Index(p[n:], []byte(": ")); i >= 0 {
+			n += i + 2
+		}
+	}
+	if flags&log.Lmsgprefix != 0 {
+		n += len(log.Prefix())
+	}
+	return min(n, len(p))
+}
+
+// goid returns the ID of the calling goroutine, parsed from the first
+// line of its stack trace ("goroutine 123 [running]:").
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,99 @@
+package metrics
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+import (
+	"strconv"
+
+	"github.com/bennof/gobfwebservice/cache"
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+	"github.com/bennof/gobfwebservice/workerpool"
+)
+
+// CacheSource is anything reporting cache statistics: every cache.Cache,
+// cache.Loader, render.PageCache and templates.RenderCache.
+type CacheSource interface {
+	Stats() cache.Stats
+}
+
+// Cache reports the statistics of a cache labelled cache=name.
+func Cache(name string, src CacheSource) Collector {
+	return CollectorFunc(func(emit func(Sample)) {
+		l := map[string]string{"cache": name}
+		s := src.Stats()
+
+		emit(Sample{Name: "cache_hits", Type: Counter, Help: "Cache lookups that found an entry.", Labels: l, Value: float64(s.Hits)})
+		emit(Sample{Name: "cache_misses", Type: Counter, Help: "Cache lookups that found no entry.", Labels: l, Value: float64(s.Misses)})
+		emit(Sample{Name: "cache_evictions", Type: Counter, Help: "Entries removed to respect the size limit.", Labels: l, Value: float64(s.Evictions)})
+		emit(Sample{Name: "cache_expirations", Type: Counter, Help: "Entries removed because their TTL passed.", Labels: l, Value: float64(s.Expirations)})
+		emit(Sample{Name: "cache_loads", Type: Counter, Help: "Loader calls.", Labels: l, Value: float64(s.Loads)})
+		emit(Sample{Name: "cache_load_errors", Type: Counter, Help: "Failed loader calls.", Labels: l, Value: float64(s.LoadErrors)})
+		if s.Entries >= 0 {
+			emit(Sample{Name: "cache_entries", Type: Gauge, Help: "Current number of entries.", Labels: l, Value: float64(s.Entries)})
+		}
+	})
+}
+
+// RateLimit reports the counters of a rate limiter labelled limiter=name.
+func RateLimit(name string, rl *middleware.RateLimiter) Collector {
+	return CollectorFunc(func(emit func(Sample)) {
+		l := map[string]string{"limiter": name}
+		s := rl.Stats()
+
+		emit(Sample{Name: "ratelimit_clients", Type: Gauge, Help: "Clients tracked in the current window.", Labels: l, Value: float64(s.Clients)})
+		emit(Sample{Name: "ratelimit_bot_clients", Type: Gauge, Help: "Bots tracked in the current window.", Labels: l, Value: float64(s.BotClients)})
+		emit(Sample{Name: "ratelimit_allowed", Type: Counter, Help: "Requests passed on.", Labels: l, Value: float64(s.Allowed)})
+		emit(Sample{Name: "ratelimit_rejected", Type: Counter, Help: "Requests rejected over a client's limit.", Labels: l, Value: float64(s.Rejected)})
+		emit(Sample{Name: "ratelimit_full", Type: Counter, Help: "Requests of new clients rejected because the client table was full.", Labels: l, Value: float64(s.Full)})
+		emit(Sample{Name: "ratelimit_challenged", Type: Counter, Help: "Requests over a client's limit answered with a challenge.", Labels: l, Value: float64(s.Challenged)})
+		emit(Sample{Name: "ratelimit_solved", Type: Counter, Help: "Solved challenges that granted a fresh budget.", Labels: l, Value: float64(s.Solved)})
+	})
+}
+
+// Server reports the connection and request counters of srv.
+func Server(srv *server.Server) Collector {
+	return CollectorFunc(func(emit func(Sample)) {
+		s := srv.Stats()
+
+		emit(Sample{Name: "http_connections", Type: Gauge, Help: "Open connections.", Value: float64(s.Connections)})
+		emit(Sample{Name: "http_connections_rejected", Type: Counter, Help: "Connections closed at the per-IP limit.", Value: float64(s.ConnsRejected)})
+		emit(Sample{Name: "http_requests_in_flight", Type: Gauge, Help: "Requests being handled.", Value: float64(s.InFlight)})
+		emit(Sample{Name: "http_requests", Type: Counter, Help: "Requests handled.", Value: float64(s.Requests)})
+		classes := []uint64{s.Status1xx, s.Status2xx, s.Status3xx, s.Status4xx, s.Status5xx}
+		for i, n := range classes {
+			l := map[string]string{"class": strconv.Itoa(i+1) + "xx"}
+			emit(Sample{Name: "http_responses", Type: Counter, Help: "Responses by status class.", Labels: l, Value: float64(n)})
+		}
+	})
+}
+
+// WorkerPool reports the queue and job counters of a pool labelled pool=name.
+func WorkerPool(name string, p *workerpool.Pool) Collector {
+	return CollectorFunc(func(emit func(Sample)) {
+		l := map[string]string{"pool": name}
+		s := p.Stats()
+
+		emit(Sample{Name: "jobs_queued", Type: Gauge, Help: "Jobs waiting in the queue.", Labels: l, Value: float64(s.Queued)})
+		emit(Sample{Name: "jobs_active", Type: Gauge, Help: "Jobs running.", Labels: l, Value: float64(s.Active)})
+		emit(Sample{Name: "jobs_submitted", Type: Counter, Help: "Jobs accepted.", Labels: l, Value: float64(s.Submitted)})
+		emit(Sample{Name: "jobs_completed", Type: Counter, Help: "Jobs finished, successfully or not.", Labels: l, Value: float64(s.Completed)})
+		emit(Sample{Name: "jobs_failed", Type: Counter, Help: "Jobs that returned an error or panicked.", Labels: l, Value: float64(s.Failed)})
+		emit(Sample{Name: "jobs_panics", Type: Counter, Help: "Jobs that panicked.", Labels: l, Value: float64(s.Panics)})
+		emit(Sample{Name: "jobs_dropped", Type: Counter, Help: "Queued jobs discarded by a forced stop.", Labels: l, Value: float64(s.Dropped)})
+	})
+}
+
+// Events reports the counters of an event bus.
+func Events(bus *events.Bus) Collector {
+	return CollectorFunc(func(emit func(Sample)) {
+		s := bus.Stats()
+
+		emit(Sample{Name: "events_published", Type: Counter, Help: "Events published.", Value: float64(s.Published)})
+		emit(Sample{Name: "events_delivered", Type: Counter, Help: "Events delivered to subscribers.", Value: float64(s.Delivered)})
+		emit(Sample{Name: "events_dropped", Type: Counter, Help: "Events dropped because an async queue was full.", Value: float64(s.Dropped)})
+		emit(Sample{Name: "events_panics", Type: Counter, Help: "Subscribers that panicked.", Value: float64(s.Panics)})
+	})
+}
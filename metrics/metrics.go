@@ -0,0 +1,196 @@
+package metrics
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package metrics exposes internal counters in the OpenMetrics /
+Prometheus text format.
+
+Summary
+-------
+- The components of this module keep their own counters (Stats
+  snapshots backed by atomics), so there is nothing to instrument: a
+  Registry holds Collectors that read those snapshots at scrape time.
+- Adapters for the bounded resources: caches (hits, misses, evictions,
+  entries), rate limiters (tracked clients, rejections), worker pools
+  (queue depth, failures) and the event bus (dropped events).
+- Handler serves OpenMetrics (application/openmetrics-text) when the
+  scraper asks for it and the Prometheus text format otherwise.
+- Counters are written with the "_total" suffix; the family name in
+  Sample.Name omits it.
+- No dependency on a metrics library; values are read on every scrape,
+  so the cost is zero between scrapes.
+
+Typical usage:
+
+	reg := metrics.New()
+	reg.Register(metrics.Cache("sessions", sessions))
+	reg.Register(metrics.RateLimit("api", limiter))
+	reg.Register(metrics.WorkerPool("mail", pool))
+
+	srv.Handle("GET /metrics", reg.Handler())
+*/
+
+import (
+	"bufio"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Type is the type of a metric family.
+type Type string
+
+// Metric types.
+const (
+	Counter Type = "counter"
+	Gauge   Type = "gauge"
+)
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Name   string            // family name, e.g. "cache_hits" (no "_total")
+	Type   Type              // Counter or Gauge
+	Help   string            // description of the family
+	Labels map[string]string // optional labels
+	Value  float64
+}
+
+// Collector emits samples at scrape time.
+type Collector interface {
+	Collect(emit func(Sample))
+}
+
+// CollectorFunc adapts a function to Collector.
+type CollectorFunc func(emit func(Sample))
+
+// Collect calls f(emit).
+func (f CollectorFunc) Collect(emit func(Sample)) {
+	f(emit)
+}
+
+// Registry holds collectors. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// New creates an empty registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// Register adds collectors.
+func (r *Registry) Register(cs ...Collector) {
+	r.mu.Lock()
+	r.collectors = append(r.collectors, cs...)
+	r.mu.Unlock()
+}
+
+// family groups the samples of one metric name.
+type family struct {
+	name    string
+	typ     Type
+	help    string
+	samples []Sample
+}
+
+// gather collects all samples grouped by family, in order of first
+// appearance.
+func (r *Registry) gather() []family {
+	r.mu.Lock()
+	cs := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var fams []family
+	index := map[string]int{}
+	for _, c := range cs {
+		c.Collect(func(s Sample) {
+			i, ok := index[s.Name]
+			if !ok {
+				i = len(fams)
+				index[s.Name] = i
+				fams = append(fams, family{name: s.Name, typ: s.Type, help: s.Help})
+			}
+			fams[i].samples = append(fams[i].samples, s)
+		})
+	}
+	return fams
+}
+
+// Handler serves the metrics of r.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		open := strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text")
+		if open {
+			w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		}
+		w.Header().Set("Cache-Control", "no-store")
+
+		bw := bufio.NewWriter(w)
+		write(bw, r.gather(), open)
+		bw.Flush()
+	})
+}
+
+// write renders families in the OpenMetrics (open) or Prometheus text format.
+func write(w *bufio.Writer, fams []family, open bool) {
+	for _, f := range fams {
+		name := f.name
+		sampleName := name
+		if f.typ == Counter {
+			sampleName += "_total"
+			if !open {
+				name = sampleName
+			}
+		}
+
+		w.WriteString("# TYPE " + name + " " + string(f.typ) + "\n")
+		if f.help != "" {
+			w.WriteString("# HELP " + name + " " + escape(f.help, false) + "\n")
+		}
+		for _, s := range f.samples {
+			w.WriteString(sampleName)
+			writeLabels(w, s.Labels)
+			w.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+		}
+	}
+	if open {
+		w.WriteString("# EOF\n")
+	}
+}
+
+// writeLabels writes {k="v",...} in sorted key order.
+func writeLabels(w *bufio.Writer, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(k + `="` + escape(labels[k], true) + `"`)
+	}
+	w.WriteByte('}')
+}
+
+// escape escapes backslashes and newlines (and quotes in label values).
+func escape(s string, quote bool) string {
+	r := strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	if quote {
+		r = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	}
+	return r.Replace(s)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+/*
+Per-request cleanup hooks.
+
+Summary
+-------
+- Cleanup opens a cleanup scope for each request; OnFinishRequest
+  registers callbacks in it, e.g. to remove a temporary file of an
+  upload or to roll back a request-scoped transaction that was not
+  committed.
+- Callbacks run after the handler returned, in reverse registration
+  order (like defer), also if the handler panicked. The panic then
+  continues to Recovery, so place Cleanup after Recovery.
+- A panicking callback is logged and does not stop the others.
+- OnFinishRequest reports false if the request has no cleanup scope;
+  the caller has to release the resource itself then.
+
+Typical usage:
+
+	tx, err := db.BeginTx(r.Context(), nil)
+	if err != nil {
+	    return err
+	}
+	middleware.OnFinishRequest(r.Context(), func() { tx.Rollback() }) // no-op after Commit
+*/
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+)
+
+// cleanupScope holds the callbacks of one request.
+type cleanupScope struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// cleanupKey stores the cleanup scope of a request.
+var cleanupKey = ctxutil.NewKey[*cleanupScope]("cleanup")
+
+// Cleanup runs the callbacks registered with OnFinishRequest once the
+// request has been handled.
+func Cleanup(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := &cleanupScope{}
+		defer scope.run()
+
+		next.ServeHTTP(w, r.WithContext(cleanupKey.Set(r.Context(), scope)))
+	})
+}
+
+// OnFinishRequest registers fn to run after the request of ctx has been
+// handled. It reports false, without registering fn, if the request is
+// not served through Cleanup.
+func OnFinishRequest(ctx context.Context, fn func()) bool {
+	scope, ok := cleanupKey.Get(ctx)
+	if !ok {
+		return false
+	}
+	scope.mu.Lock()
+	scope.fns = append(scope.fns, fn)
+	scope.mu.Unlock()
+	return true
+}
+
+// run calls the callbacks in reverse order.
+func (s *cleanupScope) run() {
+	s.mu.Lock()
+	fns := s.fns
+	s.fns = nil
+	s.mu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("panic in request cleanup: %v\n%s", rec, debug.Stack())
+				}
+			}()
+			fns[i]()
+		}()
+	}
+}
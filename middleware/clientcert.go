@@ -0,0 +1,125 @@
+package middleware
+
+/*
+Client certificate (mTLS) middleware.
+
+Summary
+-------
+- ClientCert stores the verified client certificate of the connection
+  (see server.ServerConfig.ClientCAFile and ClientAuth) in the request
+  context; handlers read it with GetClientCert or GetClientSubject.
+- Only certificates verified against the client CAs count: with
+  client_auth "request" or "require" the peer certificate is unverified
+  and ignored.
+- Required rejects requests without a verified certificate (401);
+  Allowed restricts access to certificates whose subject common name or
+  a DNS/URI name is listed (403 otherwise), e.g. the names of the
+  internal services calling the API.
+
+Example config:
+
+	"client_cert": { "required": true, "allowed": ["billing", "spiffe://corp/reports"] }
+
+Typical usage:
+
+	cert, ok := middleware.GetClientCert(r.Context())
+	caller, _ := middleware.GetClientSubject(r.Context()) // "billing"
+*/
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"slices"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+/* ---------- configuration ---------- */
+
+// ClientCertConfig defines the client certificate requirements.
+// It is JSON-serializable and intended to be part of a global application config.
+type ClientCertConfig struct {
+	Required bool     `json:"required"` // Reject requests without a verified client certificate
+	Allowed  []string `json:"allowed"`  // Accepted subject common names, DNS or URI names; empty accepts any verified certificate
+}
+
+// DefaultClientCertConfig returns a configuration that stores the
+// certificate if present and rejects nothing.
+func DefaultClientCertConfig() ClientCertConfig {
+	return ClientCertConfig{
+		Required: false,
+		Allowed:  []string{},
+	}
+}
+
+// clientCertKey stores the verified client certificate.
+var clientCertKey = ctxutil.NewKey[*x509.Certificate]("client-cert")
+
+/* ---------- middleware ---------- */
+
+// ClientCert creates a middleware storing the verified client certificate
+// in the request context, using the provided configuration or
+// DefaultClientCertConfig if none is given.
+func ClientCert(cfg ...ClientCertConfig) Middleware {
+	c := DefaultClientCertConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var cert *x509.Certificate
+			if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+				cert = r.TLS.VerifiedChains[0][0]
+			}
+
+			if cert == nil {
+				if c.Required || len(c.Allowed) > 0 {
+					server.Unauthorized(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(c.Allowed) > 0 && !slices.ContainsFunc(certNames(cert), func(n string) bool {
+				return slices.Contains(c.Allowed, n)
+			}) {
+				server.Forbidden(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(clientCertKey.Set(r.Context(), cert)))
+		})
+	}
+}
+
+// certNames returns the subject common name and the DNS and URI names
+// of cert.
+func certNames(cert *x509.Certificate) []string {
+	names := []string{cert.Subject.CommonName}
+	names = append(names, cert.DNSNames...)
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+/* ---------- getters ---------- */
+
+// GetClientCert returns the verified client certificate from context.
+func GetClientCert(ctx context.Context) (*x509.Certificate, bool) {
+	return clientCertKey.Get(ctx)
+}
+
+// GetClientSubject returns the subject common name of the verified
+// client certificate from context.
+func GetClientSubject(ctx context.Context) (string, bool) {
+	cert, ok := clientCertKey.Get(ctx)
+	if !ok {
+		return "", false
+	}
+	return cert.Subject.CommonName, true
+}
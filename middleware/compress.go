@@ -0,0 +1,341 @@
+package middleware
+
+/*
+Response compression middleware.
+
+Summary
+-------
+- Compresses responses with gzip for clients sending
+  "Accept-Encoding: gzip" if the content type is compressible
+  (ContentTypes) and the body reaches MinSize bytes; smaller bodies are
+  buffered and sent as they are.
+- Skipped automatically, so global use is safe:
+  - Server-Sent Events (Accept or Content-Type text/event-stream)
+  - WebSocket and other protocol upgrades
+  - path prefixes listed in SkipPaths (e.g. downloads of archives)
+  - range requests, responses that already carry a Content-Encoding,
+    partial content and responses without a body
+- Flush passes through: buffered data is compressed and flushed to the
+  client, so streamed responses keep working. Hijack and
+  http.ResponseController work through the wrapper.
+- Strong ETags become weak when the body is compressed, since the bytes
+  sent differ from the entity they identify.
+- gzip writers are pooled per level.
+
+Example config:
+
+	"compress": { "enabled": true, "level": 5, "min_size": 1024, "skip_paths": ["/files/"] }
+*/
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/* ---------- configuration ---------- */
+
+// CompressConfig defines the behavior of the compression middleware.
+// It is JSON-serializable and intended to be part of a global application config.
+type CompressConfig struct {
+	Enabled      bool     `json:"enabled"`
+	Level        int      `json:"level"`         // gzip level 1 (fast) to 9 (small); 0 uses the default
+	MinSize      int      `json:"min_size"`      // Smallest body in bytes worth compressing
+	ContentTypes []string `json:"content_types"` // Compressible content types (prefix match, e.g. "text/")
+	SkipPaths    []string `json:"skip_paths"`    // Path prefixes never compressed
+}
+
+// DefaultCompressConfig returns a configuration compressing text,
+// JSON, JavaScript, XML and SVG responses of at least 1 KiB.
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		Enabled: true,
+		Level:   gzip.DefaultCompression,
+		MinSize: 1024,
+		ContentTypes: []string{
+			"text/",
+			"application/json",
+			"application/problem+json",
+			"application/javascript",
+			"application/xml",
+			"image/svg+xml",
+		},
+		SkipPaths: []string{},
+	}
+}
+
+/* ---------- middleware ---------- */
+
+// Compress creates a gzip compression middleware using the provided
+// configuration, or DefaultCompressConfig if none is given.
+func Compress(cfg ...CompressConfig) Middleware {
+	c := DefaultCompressConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return CompressFrom(NewReloadable(c))
+}
+
+// CompressFrom creates a compression middleware that reads its
+// configuration from src on every request.
+func CompressFrom(src *Reloadable[CompressConfig]) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := src.Load()
+			if !c.Enabled || !compressible(r, &c) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+			cw := &compressWriter{ResponseWriter: w, config: &c}
+			defer cw.finish()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressible reports whether the response to r may be compressed.
+func compressible(r *http.Request, c *CompressConfig) bool {
+	if r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+		return false
+	}
+	if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		return false
+	}
+	// Server-Sent Events and protocol upgrades (WebSockets) stream
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") || r.Header.Get("Upgrade") != "" {
+		return false
+	}
+	for _, p := range c.SkipPaths {
+		if p != "" && strings.HasPrefix(r.URL.Path, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		enc, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(enc), "gzip") && strings.TrimSpace(enc) != "*" {
+			continue
+		}
+		q := strings.ReplaceAll(params, " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+// gzipPools holds gzip writers by level (index level+1, so the default
+// level -1 is at index 0).
+var gzipPools [gzip.BestCompression + 2]sync.Pool
+
+// compressWriter buffers the start of the body until it knows whether
+// to compress, then writes through a gzip.Writer or passes through.
+type compressWriter struct {
+	http.ResponseWriter
+	config *CompressConfig
+
+	code     int          // status set by the handler (0: not yet)
+	buf      []byte       // body buffered before the decision
+	decided  bool         // header sent
+	gz       *gzip.Writer // nil when passing through
+	level    int
+	hijacked bool
+}
+
+// WriteHeader records the status; it is sent once the body decides.
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided || w.hijacked {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code) // informational (e.g. 103 Early Hints)
+		return
+	}
+	if w.code == 0 {
+		w.code = code
+	}
+
+	// Known size or no body to compress: no need to buffer
+	if !w.eligible() {
+		w.decide(false)
+	}
+}
+
+// Write buffers up to MinSize bytes, then compresses or passes through.
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.code == 0 {
+			w.code = http.StatusOK
+		}
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.config.MinSize {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data (compressed if eligible) to the client.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.code == 0 {
+			w.code = http.StatusOK
+		}
+		w.decide(true) // streaming: compress regardless of the size so far
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker if the underlying writer does.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, brw, err
+}
+
+// ReadFrom implements io.ReaderFrom, keeping sendfile for responses that
+// are not compressed.
+func (w *compressWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !w.decided {
+		if w.code == 0 {
+			w.code = http.StatusOK
+		}
+		if err := w.decide(w.sizeHint()); err != nil {
+			return 0, err
+		}
+	}
+	if w.gz != nil {
+		return io.Copy(w.gz, src)
+	}
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+	return io.Copy(writerOnly{w.ResponseWriter}, src)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// sizeHint reports whether the declared Content-Length (if any) reaches
+// MinSize.
+func (w *compressWriter) sizeHint() bool {
+	cl := w.Header().Get("Content-Length")
+	if cl == "" {
+		return true
+	}
+	n, err := strconv.ParseInt(cl, 10, 64)
+	return err != nil || n >= int64(w.config.MinSize)
+}
+
+// eligible reports whether the status and headers allow compression.
+func (w *compressWriter) eligible() bool {
+	h := w.Header()
+	switch {
+	case w.code < 200 || w.code == http.StatusNoContent || w.code == http.StatusPartialContent || w.code == http.StatusNotModified:
+		return false
+	case h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "":
+		return false
+	}
+
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		if len(w.buf) == 0 {
+			return true // decided once the body starts
+		}
+		ct = http.DetectContentType(w.buf)
+		h.Set("Content-Type", ct) // sniffing the gzip stream would fail
+	}
+	if strings.HasPrefix(ct, "text/event-stream") {
+		return false
+	}
+	for _, t := range w.config.ContentTypes {
+		if strings.HasPrefix(ct, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide sends the header, compressing if big is set and the response
+// is eligible, and writes the buffered body.
+func (w *compressWriter) decide(big bool) error {
+	w.decided = true
+	h := w.Header()
+
+	if big && w.eligible() {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		w.level = w.config.Level
+		if w.level < gzip.DefaultCompression || w.level > gzip.BestCompression || w.level == gzip.NoCompression {
+			w.level = gzip.DefaultCompression
+		}
+		if gz, ok := gzipPools[w.level+1].Get().(*gzip.Writer); ok {
+			gz.Reset(w.ResponseWriter)
+			w.gz = gz
+		} else {
+			w.gz, _ = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.code)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish sends what is left after the handler returned.
+func (w *compressWriter) finish() {
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		if w.code == 0 && len(w.buf) == 0 {
+			return // nothing written: net/http sends the default response
+		}
+		if w.code == 0 {
+			w.code = http.StatusOK
+		}
+		w.decide(len(w.buf) >= w.config.MinSize)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		gzipPools[w.level+1].Put(w.gz)
+		w.gz = nil
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+/*
+Concurrent request limiting middleware.
+
+Summary
+-------
+- Caps the number of requests being handled at the same time, in total
+  and per client. Rate limits count requests per window; a client
+  staying below its rate with slow requests (large uploads, expensive
+  reports) can still occupy every handler slot. The per-client limit
+  keeps room for everybody else.
+- Clients are identified by an API key header (KeyHeader) if present,
+  otherwise by their IP.
+- A client at its limit gets 429; when the total limit is reached every
+  further request gets 503. Requests are never queued.
+- Memory is bounded by the number of requests in flight: a client's
+  entry is removed when its last request finishes.
+*/
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+/* ---------- configuration ---------- */
+
+// ConcurrencyConfig defines the limits of the concurrency middleware.
+// It is JSON-serializable and intended to be part of a global application config.
+type ConcurrencyConfig struct {
+	MaxInFlight int    `json:"max_in_flight"` // Concurrent requests in total; 0 means unlimited
+	PerClient   int    `json:"per_client"`    // Concurrent requests per client; 0 means unlimited
+	KeyHeader   string `json:"key_header"`    // Header identifying clients by API key; IP if empty or absent
+}
+
+// DefaultConcurrencyConfig returns a configuration without limits.
+func DefaultConcurrencyConfig() ConcurrencyConfig {
+	return ConcurrencyConfig{
+		MaxInFlight: 0,
+		PerClient:   0,
+		KeyHeader:   "X-API-Key",
+	}
+}
+
+/* ---------- middleware ---------- */
+
+// ConcurrencyLimit creates a middleware limiting concurrent requests in
+// total and per client (see above).
+func ConcurrencyLimit(cfg ...ConcurrencyConfig) Middleware {
+	c := DefaultConcurrencyConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return ConcurrencyLimitFrom(NewReloadable(c))
+}
+
+// ConcurrencyLimitFrom creates a concurrency limiting middleware that
+// reads its configuration from src on every request. Requests already
+// running are not affected by a lowered limit.
+func ConcurrencyLimitFrom(src *Reloadable[ConcurrencyConfig]) Middleware {
+	var (
+		mu      sync.Mutex
+		total   int
+		clients = map[string]int{} // requests in flight per client
+	)
+
+	release := func(client string) {
+		mu.Lock()
+		total--
+		if clients[client]--; clients[client] <= 0 {
+			delete(clients, client)
+		}
+		mu.Unlock()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := src.Load()
+			if c.MaxInFlight <= 0 && c.PerClient <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := concurrencyClient(r, c.KeyHeader)
+
+			mu.Lock()
+			if c.MaxInFlight > 0 && total >= c.MaxInFlight {
+				mu.Unlock()
+				server.ServiceUnavailable(w, r)
+				return
+			}
+			if c.PerClient > 0 && clients[client] >= c.PerClient {
+				mu.Unlock()
+				server.TooManyRequests(w, r)
+				return
+			}
+			total++
+			clients[client]++
+			mu.Unlock()
+
+			defer release(client)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// concurrencyClient returns the client key of r: its API key or its IP.
+func concurrencyClient(r *http.Request, header string) string {
+	if header != "" {
+		if key := r.Header.Get(header); key != "" {
+			return "key:" + key
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -9,6 +9,7 @@ Summary
 - Uses a JSON-serializable configuration struct.
 - Supports sensible defaults via DefaultCORSConfig().
 - Allows optional configuration by using a variadic constructor.
+- Supports runtime policy replacement via CORSFrom and a Reloadable config.
 - Handles CORS preflight (OPTIONS) requests automatically.
 */
 
@@ -54,17 +55,20 @@ func CORS(cfg ...CORSConfig) Middleware {
 		c = cfg[0]
 	}
 
-	// Precompute header values for efficiency
-	origins := strings.Join(c.AllowedOrigins, ", ")
-	methods := strings.Join(c.AllowedMethods, ", ")
-	headers := strings.Join(c.AllowedHeaders, ", ")
+	return CORSFrom(NewReloadable(c))
+}
 
+// CORSFrom creates a CORS middleware that reads its configuration from src
+// on every request, so the policy can be replaced at runtime.
+func CORSFrom(src *Reloadable[CORSConfig]) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := src.Load()
+
 			// Set CORS response headers
-			w.Header().Set("Access-Control-Allow-Origin", origins)
-			w.Header().Set("Access-Control-Allow-Methods", methods)
-			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.Header().Set("Access-Control-Allow-Origin", strings.Join(c.AllowedOrigins, ", "))
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
 
 			if c.AllowCredentials {
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
@@ -0,0 +1,134 @@
+package middleware
+
+/*
+Config-driven response header policy.
+
+Summary
+-------
+- A HeaderRule selects requests by path pattern (and optionally methods)
+  and sets, adds or removes response headers.
+- All matching rules apply in order, so later rules refine earlier ones
+  (e.g. a global rule followed by an override for /internal/*).
+- The policy is applied right before the response header is sent, so
+  it wins over headers set by handlers (Remove also drops those).
+- Patterns follow the acl package:
+
+	/admin          exact path
+	/admin/*        /admin and everything below
+	/files/*.pdf    path.Match glob (one segment per *)
+
+- Supports runtime replacement via HeadersFrom and a Reloadable config.
+
+Example config:
+
+	"headers": {
+	  "rules": [
+	    { "pattern": "/*", "set": { "X-Content-Type-Options": "nosniff" }, "remove": ["Server", "X-Powered-By"] },
+	    { "pattern": "/internal/*", "set": { "X-Robots-Tag": "noindex, nofollow" } }
+	  ]
+	}
+*/
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+// HeaderRule defines header changes for matching requests.
+type HeaderRule struct {
+	Pattern string              `json:"pattern"`           // path pattern (see file doc)
+	Methods []string            `json:"methods,omitempty"` // restrict the rule to methods; empty means all
+	Set     map[string]string   `json:"set,omitempty"`     // headers to set, replacing existing values
+	Add     map[string][]string `json:"add,omitempty"`     // headers to append to existing values
+	Remove  []string            `json:"remove,omitempty"`  // headers to remove
+}
+
+// HeaderPolicyConfig defines the header policy.
+// It is JSON-serializable and intended to be part of a global application config.
+type HeaderPolicyConfig struct {
+	Rules []HeaderRule `json:"rules"`
+}
+
+// DefaultHeaderPolicyConfig returns a policy without rules.
+func DefaultHeaderPolicyConfig() HeaderPolicyConfig {
+	return HeaderPolicyConfig{Rules: []HeaderRule{}}
+}
+
+// Headers creates a header policy middleware using the provided
+// configuration. If no configuration is supplied, no headers are changed.
+func Headers(cfg ...HeaderPolicyConfig) Middleware {
+	c := DefaultHeaderPolicyConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return HeadersFrom(NewReloadable(c))
+}
+
+// HeadersFrom creates a header policy middleware that reads its
+// configuration from src on every request.
+func HeadersFrom(src *Reloadable[HeaderPolicyConfig]) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var rules []HeaderRule
+			for _, ru := range src.Load().Rules {
+				if ru.matches(r) {
+					rules = append(rules, ru)
+				}
+			}
+			if len(rules) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			apply := func(int) {
+				h := w.Header()
+				for _, ru := range rules {
+					ru.apply(h)
+				}
+			}
+
+			rw := WrapResponseWriter(w)
+			rw.BeforeWriteHeader(apply)
+			next.ServeHTTP(rw, r)
+
+			// Handlers writing nothing: net/http sends the header afterwards
+			if !rw.Written() {
+				apply(http.StatusOK)
+			}
+		})
+	}
+}
+
+// matches reports whether the rule applies to r.
+func (ru HeaderRule) matches(r *http.Request) bool {
+	if len(ru.Methods) > 0 && !slices.ContainsFunc(ru.Methods, func(m string) bool {
+		return strings.EqualFold(m, r.Method)
+	}) {
+		return false
+	}
+
+	p := path.Clean(r.URL.Path)
+	if base, ok := strings.CutSuffix(ru.Pattern, "/*"); ok {
+		return p == base || strings.HasPrefix(p, base+"/") || (base == "" && p == "/")
+	}
+	ok, _ := path.Match(ru.Pattern, p)
+	return ok
+}
+
+// apply changes h according to the rule: remove, then set, then add.
+func (ru HeaderRule) apply(h http.Header) {
+	for _, k := range ru.Remove {
+		h.Del(k)
+	}
+	for k, v := range ru.Set {
+		h.Set(k, v)
+	}
+	for k, vs := range ru.Add {
+		for _, v := range vs {
+			h.Add(k, v)
+		}
+	}
+}
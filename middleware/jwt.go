@@ -13,6 +13,7 @@
 //   - Extract a Bearer token from the Authorization header
 //   - Optionally parse token claims
 //   - Store token and/or claims in the request context
+//   - Optionally reject unauthenticated requests (RequireBearer)
 //
 // Design goals:
 //   - No token validation logic (can be handled by nginx auth_request)
@@ -41,20 +42,23 @@ import (
 	"context"
 	"net/http"
 	"strings"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/server"
 )
 
 // -----------------------------------------------------------------------------
-// Context keys (unexported)
+// Context keys (unexported, see ctxutil)
 // -----------------------------------------------------------------------------
 
-// ctxKeyBearerToken stores the raw Bearer token string.
-type ctxKeyBearerToken struct{}
+// bearerTokenKey stores the raw Bearer token string.
+var bearerTokenKey = ctxutil.NewKey[string]("bearer-token")
 
-// ctxKeyBearerClaims stores typed claims (generic).
-type ctxKeyBearerClaims[T any] struct{}
+// bearerClaims wraps typed claims; its TypeKey is private to this package.
+type bearerClaims[T any] struct{ claims *T }
 
-// ctxKeyBearerClaimsMap stores untyped (map-based) claims.
-type ctxKeyBearerClaimsMap struct{}
+// bearerClaimsMapKey stores untyped (map-based) claims.
+var bearerClaimsMapKey = ctxutil.NewKey[map[string]any]("bearer-claims")
 
 // -----------------------------------------------------------------------------
 // Parser types
@@ -83,8 +87,7 @@ func BearerContext() Middleware {
 			h := r.Header.Get("Authorization")
 			if strings.HasPrefix(strings.ToLower(h), "bearer ") {
 				token := strings.TrimSpace(h[len("Bearer "):])
-				ctx := context.WithValue(r.Context(), ctxKeyBearerToken{}, token)
-				r = r.WithContext(ctx)
+				r = r.WithContext(bearerTokenKey.Set(r.Context(), token))
 			}
 			next.ServeHTTP(w, r)
 		})
@@ -109,8 +112,8 @@ func BearerContextTyped[T any](parser BearerParser[T]) Middleware {
 				token := strings.TrimSpace(h[len("Bearer "):])
 
 				if claims, err := parser(token); err == nil {
-					ctx := context.WithValue(r.Context(), ctxKeyBearerClaims[T]{}, claims)
-					ctx = context.WithValue(ctx, ctxKeyBearerToken{}, token)
+					ctx := ctxutil.Set(r.Context(), ctxutil.TypeKey[bearerClaims[T]](), bearerClaims[T]{claims})
+					ctx = bearerTokenKey.Set(ctx, token)
 					r = r.WithContext(ctx)
 				}
 			}
@@ -137,8 +140,8 @@ func BearerContextMap(parser BearerMapParser) Middleware {
 				token := strings.TrimSpace(h[len("Bearer "):])
 
 				if claims, err := parser(token); err == nil {
-					ctx := context.WithValue(r.Context(), ctxKeyBearerClaimsMap{}, claims)
-					ctx = context.WithValue(ctx, ctxKeyBearerToken{}, token)
+					ctx := bearerClaimsMapKey.Set(r.Context(), claims)
+					ctx = bearerTokenKey.Set(ctx, token)
 					r = r.WithContext(ctx)
 				}
 			}
@@ -147,27 +150,46 @@ func BearerContextMap(parser BearerMapParser) Middleware {
 	}
 }
 
+// -----------------------------------------------------------------------------
+// Middleware: enforcement
+// -----------------------------------------------------------------------------
+
+// RequireBearer rejects requests without a Bearer token in the context
+// with 401 Unauthorized. Place it after BearerContextTyped or
+// BearerContextMap, which only store tokens whose claims parsed
+// successfully.
+//
+// Note: BearerContext stores tokens without validation; combined with it,
+// RequireBearer only checks for presence.
+func RequireBearer() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetBearerToken(r.Context()); !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				server.Unauthorized(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Getters (read-only)
 // -----------------------------------------------------------------------------
 
 // GetBearerToken returns the raw Bearer token from context.
 func GetBearerToken(ctx context.Context) (string, bool) {
-	v := ctx.Value(ctxKeyBearerToken{})
-	t, ok := v.(string)
-	return t, ok
+	return bearerTokenKey.Get(ctx)
 }
 
 // GetBearerClaimsTyped returns typed claims from context.
 func GetBearerClaimsTyped[T any](ctx context.Context) (*T, bool) {
-	v := ctx.Value(ctxKeyBearerClaims[T]{})
-	c, ok := v.(*T)
-	return c, ok
+	c, ok := ctxutil.Get(ctx, ctxutil.TypeKey[bearerClaims[T]]())
+	return c.claims, ok
 }
 
 // GetBearerClaimsMap returns map-based claims from context.
 func GetBearerClaimsMap(ctx context.Context) (map[string]any, bool) {
-	v := ctx.Value(ctxKeyBearerClaimsMap{})
-	m, ok := v.(map[string]any)
-	return m, ok
+	return bearerClaimsMapKey.Get(ctx)
 }
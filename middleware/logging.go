@@ -6,7 +6,12 @@ Logging middleware for HTTP request tracing.
 Summary
 -------
 - Logs exactly one entry per HTTP request.
-- Captures method, path, status code, duration, and request ID.
+- Captures method, path, status code, duration, request ID and client IP
+  (anonymized according to the logging configuration, see logging.ClientIP).
+- Logs the gRPC status for gRPC requests (HTTP status is always 200).
+- Marks requests aborted by the client with "client closed" (status 499
+  if no response was sent, see server.ClientGone) and counts them in
+  ClientClosedRequests.
 - Uses Go's global standard logger (log.Printf), so output format and
   destination are controlled by the central logging configuration.
 - Designed to be lightweight and free of business logic.
@@ -15,21 +20,20 @@ Summary
 import (
 	"log"
 	"net/http"
+	"sync/atomic"
 	"time"
+
+	"github.com/bennof/gobfwebservice/logging"
+	"github.com/bennof/gobfwebservice/server"
 )
 
-// statusRecorder wraps an http.ResponseWriter to capture the HTTP status code
-// written by the handler.
-type statusRecorder struct {
-	http.ResponseWriter
-	status int
-}
+// clientClosed counts requests aborted by the client.
+var clientClosed atomic.Uint64
 
-// WriteHeader intercepts the status code before delegating to the underlying
-// ResponseWriter.
-func (r *statusRecorder) WriteHeader(code int) {
-	r.status = code
-	r.ResponseWriter.WriteHeader(code)
+// ClientClosedRequests returns the number of requests logged as aborted
+// by the client since the process started.
+func ClientClosedRequests() uint64 {
+	return clientClosed.Load()
 }
 
 // Logging is an HTTP middleware that logs basic request information.
@@ -41,23 +45,52 @@ func Logging(next http.Handler) http.Handler {
 		start := time.Now()
 
 		// Wrap the ResponseWriter to capture the status code
-		rec := &statusRecorder{
-			ResponseWriter: w,
-			status:         http.StatusOK, // default if WriteHeader is not called
-		}
+		rec := WrapResponseWriter(w)
 
 		// Execute the next handler in the chain
 		next.ServeHTTP(rec, r)
 
 		// Log request details after the handler has completed
 		dur := time.Since(start)
+		status, note := rec.Status(), ""
+		if server.ClientGone(r) {
+			clientClosed.Add(1)
+			note = " client closed"
+			if !rec.Written() {
+				status = server.StatusClientClosedRequest
+			}
+		}
+		if server.IsGRPC(r) {
+			log.Printf(
+				"GRPC %s grpc-status=%s %s rid=%s%s",
+				r.URL.Path,
+				grpcStatus(rec.Header()),
+				dur,
+				GetRequestID(r.Context()),
+				note,
+			)
+			return
+		}
 		log.Printf(
-			"%s %s %d %s rid=%s",
+			"%s %s %d %s rid=%s ip=%s%s",
 			r.Method,
 			r.URL.Path,
-			rec.status,
+			status,
 			dur,
 			GetRequestID(r.Context()),
+			logging.ClientIP(r.RemoteAddr),
+			note,
 		)
 	})
 }
+
+// grpcStatus returns the gRPC status from response headers or trailers.
+func grpcStatus(h http.Header) string {
+	if v := h.Get("Grpc-Status"); v != "" {
+		return v
+	}
+	if v := h.Get(http.TrailerPrefix + "Grpc-Status"); v != "" {
+		return v
+	}
+	return "?"
+}
@@ -1,6 +1,29 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/bennof/gobfwebservice/server"
+)
 
 // Middleware defines a standard HTTP middleware.
 type Middleware func(http.Handler) http.Handler
+
+// Chain composes middleware into a single Middleware.
+// The first middleware is the outermost one:
+//
+//	Chain(Recovery, RequestID, Logging)(h) == Recovery(RequestID(Logging(h)))
+//
+// Descriptions of Named middleware are kept through unnamed ones.
+func Chain(ms ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(ms) - 1; i >= 0; i-- {
+			h := ms[i](next)
+			if server.MiddlewareOf(h) == nil {
+				h = describe(h, server.MiddlewareOf(next))
+			}
+			next = h
+		}
+		return next
+	}
+}
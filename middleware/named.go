@@ -0,0 +1,62 @@
+package middleware
+
+/*
+Self-describing middleware for route introspection.
+
+Summary
+-------
+- Named attaches a name and its configuration to a middleware. Handlers
+  wrapped by it report the middleware via server.MiddlewareOf, so
+  server.Routes lists which middleware protects which route.
+- Chain keeps the descriptions of inner middleware when an unnamed
+  middleware wraps them; unnamed middleware itself is not listed.
+- The configuration should be JSON-serializable; a Reloadable reports
+  its current value.
+
+Typical usage:
+
+	api := middleware.Chain(
+	    middleware.Named("recovery", middleware.Recovery),
+	    middleware.Named("cors", middleware.CORSFrom(cors), cors),
+	)
+*/
+
+import (
+	"net/http"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Named returns m described by name and the optional config.
+func Named(name string, m Middleware, config ...any) Middleware {
+	info := server.MiddlewareInfo{Name: name}
+	if len(config) > 0 {
+		info.Config = config[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return describe(m(next), append([]server.MiddlewareInfo{info}, server.MiddlewareOf(next)...))
+	}
+}
+
+// described is a handler carrying middleware descriptions.
+type described struct {
+	http.Handler
+	info []server.MiddlewareInfo
+}
+
+// MiddlewareInfo returns the middleware wrapping the handler.
+func (d *described) MiddlewareInfo() []server.MiddlewareInfo {
+	return d.info
+}
+
+// describe attaches info to h (replacing a previous description).
+func describe(h http.Handler, info []server.MiddlewareInfo) http.Handler {
+	if len(info) == 0 {
+		return h
+	}
+	if d, ok := h.(*described); ok {
+		h = d.Handler
+	}
+	return &described{Handler: h, info: info}
+}
@@ -10,6 +10,16 @@ Summary
 - Adds a hard cap on the number of tracked clients to prevent
   unbounded memory growth.
 - Uses a global reset timer to clear all counters periodically.
+- Optional bot profile: requests classified as crawlers/bots by their
+  User-Agent (see IsBot) are counted separately against stricter
+  limits, so crawlers neither exhaust nor share the human budget.
+- RateLimiter exposes the number of tracked clients and rejections
+  (Stats), e.g. for the metrics package.
+- Optional challenge (SetChallenge): instead of 429, clients over their
+  limit get a challenge such as a proof-of-work page or a CAPTCHA (see
+  the challenge package). A solved challenge grants a fresh budget,
+  once per solution and window, so humans behind a busy IP get through
+  while bots have to pay for every budget.
 - Designed for low-resource systems and small services where
   predictable memory usage is more important than perfect fairness.
 */
@@ -18,6 +28,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bennof/gobfwebservice/server"
@@ -31,6 +42,17 @@ type RateLimitConfig struct {
 	MaxRequests int           `json:"max_requests"` // Maximum requests per client IP within the window
 	MaxClients  int           `json:"max_clients"`  // Maximum number of distinct clients tracked per window
 	Window      time.Duration `json:"window"`       // Time window for rate limiting
+
+	Bots BotRateLimitConfig `json:"bots"` // Separate profile for crawlers and bots
+}
+
+// BotRateLimitConfig defines the limits applied to requests classified
+// as bots. It shares the window of the enclosing RateLimitConfig.
+type BotRateLimitConfig struct {
+	Enabled     bool     `json:"enabled"`      // Apply the bot profile; otherwise bots count as humans
+	MaxRequests int      `json:"max_requests"` // Maximum requests per bot IP within the window
+	MaxClients  int      `json:"max_clients"`  // Maximum number of distinct bot IPs tracked per window
+	Patterns    []string `json:"patterns"`     // User-Agent substrings; empty uses DefaultBotPatterns
 }
 
 // DefaultRateLimitConfig returns a conservative default configuration.
@@ -40,6 +62,12 @@ func DefaultRateLimitConfig() RateLimitConfig {
 		MaxRequests: 100,
 		MaxClients:  1000,
 		Window:      time.Minute,
+		Bots: BotRateLimitConfig{
+			Enabled:     false,
+			MaxRequests: 20,
+			MaxClients:  200,
+			Patterns:    DefaultBotPatterns,
+		},
 	}
 }
 
@@ -55,50 +83,156 @@ func RateLimit(cfg ...RateLimitConfig) Middleware {
 		c = cfg[0]
 	}
 
-	var (
-		mu    sync.Mutex
-		hits  = map[string]int{} // request counters per client IP
-		reset = time.Now().Add(c.Window)
-	)
+	return RateLimitFrom(NewReloadable(c))
+}
+
+// RateLimitFrom creates a rate limiting middleware that reads its
+// configuration from src on every request. Limits apply immediately;
+// a changed window takes effect at the next counter reset.
+func RateLimitFrom(src *Reloadable[RateLimitConfig]) Middleware {
+	return NewRateLimiter(src).Middleware()
+}
+
+// RateLimitStats is a snapshot of the rate limiter counters.
+type RateLimitStats struct {
+	Clients    int    `json:"clients"`     // clients tracked in the current window
+	BotClients int    `json:"bot_clients"` // bots tracked in the current window (bot profile)
+	Allowed    uint64 `json:"allowed"`     // requests passed on
+	Rejected   uint64 `json:"rejected"`    // requests over a client's limit
+	Full       uint64 `json:"full"`        // requests of new clients rejected because the client table was full
+	Challenged uint64 `json:"challenged"`  // requests over the limit answered with the challenge
+	Solved     uint64 `json:"solved"`      // solved challenges that granted a fresh budget
+}
+
+// Challenge lets clients over the rate limit prove they are not a bot
+// instead of being rejected (see RateLimiter.SetChallenge).
+type Challenge interface {
+	// Solved returns an identifier of the valid solution carried by r
+	// (e.g. in a cookie set by the challenge page), or false.
+	Solved(r *http.Request) (id string, ok bool)
+
+	// ServeHTTP answers a request over the limit with the challenge.
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+// RateLimiter is a rate limiter whose counters can be observed (Stats).
+type RateLimiter struct {
+	src *Reloadable[RateLimitConfig]
+
+	mu    sync.Mutex
+	hits  map[string]int  // request counters per client IP
+	bots  map[string]int  // request counters per bot IP (bot profile)
+	used  map[string]bool // challenge solutions redeemed in this window
+	reset time.Time
+
+	challenge Challenge // nil rejects with 429
 
+	allowed, rejected, full atomic.Uint64
+	challenged, solved      atomic.Uint64
+}
+
+// NewRateLimiter creates a rate limiter reading its configuration from src.
+func NewRateLimiter(src *Reloadable[RateLimitConfig]) *RateLimiter {
+	return &RateLimiter{
+		src:   src,
+		hits:  map[string]int{},
+		bots:  map[string]int{},
+		used:  map[string]bool{},
+		reset: time.Now().Add(src.Load().Window),
+	}
+}
+
+// SetChallenge answers requests over a client's limit with ch instead
+// of 429. Call it before the middleware serves requests.
+func (l *RateLimiter) SetChallenge(ch Challenge) {
+	l.challenge = ch
+}
+
+// Stats returns a snapshot of the counters.
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	clients, bots := len(l.hits), len(l.bots)
+	l.mu.Unlock()
+
+	return RateLimitStats{
+		Clients:    clients,
+		BotClients: bots,
+		Allowed:    l.allowed.Load(),
+		Rejected:   l.rejected.Load(),
+		Full:       l.full.Load(),
+		Challenged: l.challenged.Load(),
+		Solved:     l.solved.Load(),
+	}
+}
+
+// Middleware returns the rate limiting middleware.
+func (l *RateLimiter) Middleware() Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			now := time.Now()
+			c := l.src.Load()
 
-			mu.Lock()
+			l.mu.Lock()
 			// Reset all counters when the time window expires
-			if now.After(reset) {
-				hits = map[string]int{}
-				reset = now.Add(c.Window)
+			if now.After(l.reset) {
+				l.hits = map[string]int{}
+				l.bots = map[string]int{}
+				l.used = map[string]bool{}
+				l.reset = now.Add(c.Window)
 			}
 
 			// Extract client IP (RemoteAddr is usually "IP:PORT")
 			host, _, err := net.SplitHostPort(r.RemoteAddr)
 			if err != nil {
-				mu.Unlock()
+				l.mu.Unlock()
 				server.BadRequest(w, r)
 				return
 			}
 
+			// Select the profile: bots get their own counters and limits
+			counters, maxRequests, maxClients := l.hits, c.MaxRequests, c.MaxClients
+			if c.Bots.Enabled && IsBot(r, c.Bots.Patterns) {
+				counters, maxRequests, maxClients = l.bots, c.Bots.MaxRequests, c.Bots.MaxClients
+			}
+
 			// Reject new clients if the map size limit is reached
-			if _, exists := hits[host]; !exists && len(hits) >= c.MaxClients {
-				mu.Unlock()
+			if _, exists := counters[host]; !exists && len(counters) >= maxClients {
+				l.mu.Unlock()
+				l.full.Add(1)
 				server.TooManyRequests(w, r)
 				return
 			}
 
 			// Increment request counter for this client
-			hits[host]++
-			count := hits[host]
-			mu.Unlock()
+			counters[host]++
+			count := counters[host]
+
+			// A solved challenge starts a fresh budget (once per solution;
+			// bounded like the client table)
+			if count > maxRequests && l.challenge != nil && len(l.used) < maxClients {
+				if id, ok := l.challenge.Solved(r); ok && !l.used[id] {
+					l.used[id] = true
+					counters[host] = 1
+					count = 1
+					l.solved.Add(1)
+				}
+			}
+			l.mu.Unlock()
 
 			// Enforce per-client request limit
-			if count > c.MaxRequests {
+			if count > maxRequests {
+				l.rejected.Add(1)
+				if l.challenge != nil {
+					l.challenged.Add(1)
+					l.challenge.ServeHTTP(w, r)
+					return
+				}
 				server.TooManyRequests(w, r)
 				return
 			}
 
 			// Delegate to the next handler
+			l.allowed.Add(1)
 			next.ServeHTTP(w, r)
 		})
 	}
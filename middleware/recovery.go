@@ -7,7 +7,12 @@ Summary
 -------
 - Protects the HTTP server from panics occurring in handlers or downstream middleware.
 - Converts panics into HTTP 500 Internal Server Error responses.
-- Logs the panic value together with a stack trace.
+- Shows the panic value and stack trace to the client in debug mode
+  (see server.SetDebug): browsers get the developer error page.
+- Logs the panic value together with a stack trace and passes both to
+  the error reporter (see server.SetErrorReporter).
+- Re-panics http.ErrAbortHandler, which net/http uses to abort a
+  response silently (e.g. a proxied stream whose client went away).
 - Prevents a single faulty request from crashing the entire process.
 - Intended to be used early in the middleware chain.
 */
@@ -28,11 +33,16 @@ func Recovery(next http.Handler) http.Handler {
 		// Ensure panics do not propagate and crash the server
 		defer func() {
 			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
 				// Log panic details and stack trace for diagnostics
-				log.Printf("panic: %v\n%s", rec, debug.Stack())
+				stack := debug.Stack()
+				log.Printf("panic: %v\n%s", rec, stack)
 
-				// Return a generic error response to the client
-				server.InternalServerError(w, r)
+				// Return an error response (with diagnostics in debug mode)
+				server.RenderPanic(w, r, rec, stack)
 			}
 		}()
 
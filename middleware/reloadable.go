@@ -0,0 +1,48 @@
+package middleware
+
+/*
+Runtime-replaceable middleware configuration.
+
+Summary
+-------
+- Reloadable holds a configuration value that can be swapped atomically.
+- Middleware built from a Reloadable (CORSFrom, RateLimitFrom) reads the
+  current value on every request, so new settings apply without restarting
+  the server or rebuilding the handler chain.
+- Intended for config reloads (e.g. on SIGHUP).
+*/
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Reloadable holds a configuration of type T that can be replaced at runtime.
+// It is safe for concurrent use.
+type Reloadable[T any] struct {
+	v atomic.Pointer[T]
+}
+
+// NewReloadable creates a Reloadable holding the given configuration.
+func NewReloadable[T any](cfg T) *Reloadable[T] {
+	r := &Reloadable[T]{}
+	r.Store(cfg)
+	return r
+}
+
+// Load returns the current configuration.
+func (r *Reloadable[T]) Load() T {
+	return *r.v.Load()
+}
+
+// Store replaces the configuration. Requests already in flight keep the
+// value they loaded; subsequent requests use the new one.
+func (r *Reloadable[T]) Store(cfg T) {
+	r.v.Store(&cfg)
+}
+
+// MarshalJSON encodes the current configuration (e.g. for route
+// introspection, see Named).
+func (r *Reloadable[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Load())
+}
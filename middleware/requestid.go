@@ -8,8 +8,11 @@ Summary
 - Ensures every incoming HTTP request has a unique request identifier.
 - Accepts an existing request ID from the X-Request-ID header if present.
 - Generates a new request ID otherwise.
-- Injects the request ID into the request context.
+- Injects the request ID into the request context (server.RequestID,
+  also read by the error pages).
 - Returns the request ID to the client via the X-Request-ID response header.
+- Tags standard log output of the handling goroutine with the request ID
+  (logging.Tag), so log lines of third-party libraries can be correlated.
 - Enables log correlation across middleware, handlers, and services.
 */
 
@@ -17,13 +20,11 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/bennof/gobfwebservice/logging"
+	"github.com/bennof/gobfwebservice/server"
 	"github.com/google/uuid"
 )
 
-// ctxKeyRequestID is an unexported context key type used to avoid
-// collisions with other context values.
-type ctxKeyRequestID struct{}
-
 // RequestID is an HTTP middleware that injects a request ID into the
 // request context and response headers.
 func RequestID(next http.Handler) http.Handler {
@@ -36,11 +37,14 @@ func RequestID(next http.Handler) http.Handler {
 		}
 
 		// Store the request ID in the context
-		ctx := context.WithValue(r.Context(), ctxKeyRequestID{}, id)
+		ctx := server.WithRequestID(r.Context(), id)
 
 		// Expose the request ID to the client
 		w.Header().Set("X-Request-ID", id)
 
+		// Prefix std log output of this goroutine with the request ID
+		defer logging.Tag(id)()
+
 		// Continue request handling with the updated context
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -49,8 +53,5 @@ func RequestID(next http.Handler) http.Handler {
 // GetRequestID extracts the request ID from the given context.
 // It returns an empty string if no request ID is present.
 func GetRequestID(ctx context.Context) string {
-	if v, ok := ctx.Value(ctxKeyRequestID{}).(string); ok {
-		return v
-	}
-	return ""
+	return server.RequestID(ctx)
 }
@@ -0,0 +1,147 @@
+package middleware
+
+/*
+Shared response writer wrapper.
+
+Summary
+-------
+- ResponseWriter wraps an http.ResponseWriter to record the status code
+  and the number of body bytes written.
+- It preserves the optional interfaces handlers rely on: http.Flusher
+  (SSE, streaming, gRPC), http.Hijacker (WebSockets) and io.ReaderFrom
+  (sendfile for static files). Each is forwarded to the underlying
+  writer; if that writer lacks it, Flush is a no-op, Hijack returns
+  http.ErrNotSupported and ReadFrom falls back to a plain copy.
+- BeforeWriteHeader registers hooks that may still change the headers
+  right before they are sent (e.g. the header policy).
+- Unwrap exposes the underlying writer to http.ResponseController, so
+  deadlines and future interfaces work through any number of wrappers.
+- Middleware that needs to observe the response should use this type
+  instead of defining its own wrapper.
+
+Typical usage:
+
+	rw := middleware.WrapResponseWriter(w)
+	next.ServeHTTP(rw, r)
+	log.Printf("%d %d bytes", rw.Status(), rw.BytesWritten())
+*/
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter records the status and size of a response.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+	before  []func(code int)
+}
+
+// WrapResponseWriter wraps w. The status defaults to 200 if the handler
+// never calls WriteHeader.
+func WrapResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// Status returns the status code sent to the client (200 if the
+// handler wrote nothing or only a body).
+func (w *ResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// BytesWritten returns the number of body bytes written.
+func (w *ResponseWriter) BytesWritten() int64 {
+	return w.written
+}
+
+// Written reports whether the header has been sent.
+func (w *ResponseWriter) Written() bool {
+	return w.status != 0
+}
+
+// BeforeWriteHeader registers fn to run once, right before the final
+// header is sent, with the status code. Hooks run in registration order
+// and may modify w.Header().
+func (w *ResponseWriter) BeforeWriteHeader(fn func(code int)) {
+	w.before = append(w.before, fn)
+}
+
+// WriteHeader records the first final status code and forwards it.
+func (w *ResponseWriter) WriteHeader(code int) {
+	if w.status == 0 && (code >= 200 || code == http.StatusSwitchingProtocols) {
+		w.setStatus(code)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// setStatus records the status and runs the BeforeWriteHeader hooks.
+func (w *ResponseWriter) setStatus(code int) {
+	w.status = code
+	for _, fn := range w.before {
+		fn(code)
+	}
+	w.before = nil
+}
+
+// Write forwards to the underlying writer and counts the bytes.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.setStatus(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *ResponseWriter) Flush() {
+	if w.status == 0 {
+		w.setStatus(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker if the underlying writer does.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols // headers are not sent
+	}
+	return conn, brw, err
+}
+
+// ReadFrom implements io.ReaderFrom, using the underlying writer's
+// implementation (sendfile) when available.
+func (w *ResponseWriter) ReadFrom(src io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.setStatus(http.StatusOK)
+	}
+
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(writerOnly{w.ResponseWriter}, src)
+	}
+	w.written += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writerOnly hides optional interfaces so io.Copy does not recurse
+// into ReadFrom.
+type writerOnly struct {
+	io.Writer
+}
@@ -0,0 +1,124 @@
+package middleware
+
+/*
+Security headers middleware.
+
+Summary
+-------
+- Sets the common security response headers from a JSON-serializable
+  configuration: Strict-Transport-Security, X-Content-Type-Options,
+  X-Frame-Options, Referrer-Policy, Permissions-Policy and
+  Content-Security-Policy (or its report-only variant).
+- Empty values (and HSTSMaxAge 0) leave a header out.
+- HSTS is only sent on HTTPS requests (TLS or X-Forwarded-Proto https
+  from a TLS-terminating proxy); browsers ignore it on plain HTTP.
+- Headers are set before the handler runs, so a handler (or the header
+  policy, see Headers) can still override them for single responses.
+- Supports runtime replacement via SecureHeadersFrom and a Reloadable config.
+
+Example config:
+
+	"secure_headers": {
+	  "hsts_max_age": 31536000,
+	  "hsts_include_subdomains": true,
+	  "frame_options": "DENY",
+	  "content_security_policy": "default-src 'self'; img-src 'self' data:"
+	}
+*/
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// SecureHeadersConfig defines the security headers.
+// All fields are JSON-serializable and intended to be part of a global app config.
+type SecureHeadersConfig struct {
+	HSTSMaxAge            int    `json:"hsts_max_age"`            // Strict-Transport-Security max-age in seconds; 0 disables HSTS
+	HSTSIncludeSubdomains bool   `json:"hsts_include_subdomains"` // Apply HSTS to all subdomains
+	HSTSPreload           bool   `json:"hsts_preload"`            // Allow inclusion in browser preload lists
+	ContentTypeNosniff    bool   `json:"content_type_nosniff"`    // X-Content-Type-Options: nosniff
+	FrameOptions          string `json:"frame_options"`           // X-Frame-Options, e.g. "DENY" or "SAMEORIGIN"
+	ReferrerPolicy        string `json:"referrer_policy"`         // Referrer-Policy, e.g. "strict-origin-when-cross-origin"
+	PermissionsPolicy     string `json:"permissions_policy"`      // Permissions-Policy, e.g. "camera=(), microphone=()"
+	ContentSecurityPolicy string `json:"content_security_policy"` // Content-Security-Policy; empty omits it
+	CSPReportOnly         bool   `json:"csp_report_only"`         // Send the CSP as Content-Security-Policy-Report-Only
+}
+
+// DefaultSecureHeadersConfig returns headers that are safe for most
+// sites: HSTS for one year, nosniff, no framing and a referrer policy.
+// No Content-Security-Policy is set, since it depends on the pages.
+func DefaultSecureHeadersConfig() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: false,
+		HSTSPreload:           false,
+		ContentTypeNosniff:    true,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     "",
+		ContentSecurityPolicy: "",
+		CSPReportOnly:         false,
+	}
+}
+
+// SecureHeaders creates a security headers middleware using the provided
+// configuration. If no configuration is supplied,
+// DefaultSecureHeadersConfig() is used.
+func SecureHeaders(cfg ...SecureHeadersConfig) Middleware {
+	c := DefaultSecureHeadersConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return SecureHeadersFrom(NewReloadable(c))
+}
+
+// SecureHeadersFrom creates a security headers middleware that reads its
+// configuration from src on every request.
+func SecureHeadersFrom(src *Reloadable[SecureHeadersConfig]) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := src.Load()
+			h := w.Header()
+
+			if c.HSTSMaxAge > 0 && isHTTPS(r) {
+				v := "max-age=" + strconv.Itoa(c.HSTSMaxAge)
+				if c.HSTSIncludeSubdomains {
+					v += "; includeSubDomains"
+				}
+				if c.HSTSPreload {
+					v += "; preload"
+				}
+				h.Set("Strict-Transport-Security", v)
+			}
+			if c.ContentTypeNosniff {
+				h.Set("X-Content-Type-Options", "nosniff")
+			}
+			if c.FrameOptions != "" {
+				h.Set("X-Frame-Options", c.FrameOptions)
+			}
+			if c.ReferrerPolicy != "" {
+				h.Set("Referrer-Policy", c.ReferrerPolicy)
+			}
+			if c.PermissionsPolicy != "" {
+				h.Set("Permissions-Policy", c.PermissionsPolicy)
+			}
+			if c.ContentSecurityPolicy != "" {
+				name := "Content-Security-Policy"
+				if c.CSPReportOnly {
+					name = "Content-Security-Policy-Report-Only"
+				}
+				h.Set(name, c.ContentSecurityPolicy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isHTTPS reports whether r arrived over HTTPS, directly or through a
+// TLS-terminating proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,205 @@
+package middleware
+
+/*
+Middleware stacks assembled from configuration.
+
+Summary
+-------
+- A Builder maps names to factories; Build turns an ordered list of
+  StackEntry values from the JSON config into a Chain, so operators can
+  reorder, add or disable middleware without recompiling.
+- An entry is either a plain name or an object with an optional config
+  block and a disabled flag:
+
+	"api": [
+	  "recovery",
+	  "request-id",
+	  "logging",
+	  { "name": "cors", "config": { "allowed_origins": ["https://example.com"] } },
+	  { "name": "rate-limit", "disabled": true }
+	]
+
+- Factories receive the raw config block (nil if there is none) and
+  decode it strictly (DecodeSettings): unknown fields are errors.
+  Configurable decodes over defaults for typical config structs.
+- NewBuilder knows the middleware of this package (recovery, request-id,
+  logging, cors, rate-limit, concurrency, headers, bearer,
+  require-bearer); Register
+  adds application middleware or replaces built-ins; SetDefault shares
+  an existing instance (e.g. a Reloadable one) for entries without a
+  config block. Underscores in names are accepted for hyphens
+  ("request_id").
+- Every built middleware is Named with its entry's config, so the
+  assembled stack shows up in server.Routes.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Factory builds a middleware from its config block (nil if the entry
+// has none).
+type Factory func(settings json.RawMessage) (Middleware, error)
+
+// StackEntry is one middleware of a configured stack.
+type StackEntry struct {
+	Name     string          `json:"name"`
+	Disabled bool            `json:"disabled,omitempty"`
+	Config   json.RawMessage `json:"config,omitempty"`
+}
+
+// UnmarshalJSON accepts a plain name or an object.
+func (e *StackEntry) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*e = StackEntry{Name: name}
+		return nil
+	}
+	type entry StackEntry
+	return json.Unmarshal(b, (*entry)(e))
+}
+
+// MarshalJSON writes entries without options as plain names.
+func (e StackEntry) MarshalJSON() ([]byte, error) {
+	if !e.Disabled && len(e.Config) == 0 {
+		return json.Marshal(e.Name)
+	}
+	type entry StackEntry
+	return json.Marshal(entry(e))
+}
+
+// Builder assembles middleware stacks by name.
+type Builder struct {
+	factories map[string]Factory
+	order     []string
+}
+
+// NewBuilder creates a builder knowing the middleware of this package.
+// "bearer" stores the raw token only (BearerContext); register a parsing
+// variant to validate tokens.
+func NewBuilder() *Builder {
+	b := &Builder{factories: map[string]Factory{}}
+	b.Register("recovery", Static(Recovery))
+	b.Register("request-id", Static(RequestID))
+	b.Register("cleanup", Static(Cleanup))
+	b.Register("logging", Static(Logging))
+	b.Register("cors", Configurable(DefaultCORSConfig, func(c CORSConfig) Middleware { return CORS(c) }))
+	b.Register("rate-limit", Configurable(DefaultRateLimitConfig, func(c RateLimitConfig) Middleware { return RateLimit(c) }))
+	b.Register("concurrency", Configurable(DefaultConcurrencyConfig, func(c ConcurrencyConfig) Middleware { return ConcurrencyLimit(c) }))
+	b.Register("headers", Configurable(DefaultHeaderPolicyConfig, func(c HeaderPolicyConfig) Middleware { return Headers(c) }))
+	b.Register("compress", Configurable(DefaultCompressConfig, func(c CompressConfig) Middleware { return Compress(c) }))
+	b.Register("secure-headers", Configurable(DefaultSecureHeadersConfig, func(c SecureHeadersConfig) Middleware { return SecureHeaders(c) }))
+	b.Register("client-cert", Configurable(DefaultClientCertConfig, func(c ClientCertConfig) Middleware { return ClientCert(c) }))
+	b.Register("bearer", Static(BearerContext()))
+	b.Register("require-bearer", Static(RequireBearer()))
+	return b
+}
+
+// Register makes a middleware available under name, replacing a
+// previous factory of that name.
+func (b *Builder) Register(name string, f Factory) {
+	name = normalizeName(name)
+	if _, ok := b.factories[name]; !ok {
+		b.order = append(b.order, name)
+	}
+	b.factories[name] = f
+}
+
+// SetDefault makes entries of name without a config block use m (e.g.
+// a reloadable instance shared with other code). Entries with a config
+// block are still built by the registered factory, if any.
+func (b *Builder) SetDefault(name string, m Middleware) {
+	f := b.factories[normalizeName(name)]
+	if f == nil {
+		f = Static(nil)
+	}
+	b.Register(name, func(settings json.RawMessage) (Middleware, error) {
+		if len(settings) == 0 {
+			return m, nil
+		}
+		return f(settings)
+	})
+}
+
+// Names returns the available names in registration order.
+func (b *Builder) Names() []string {
+	return append([]string(nil), b.order...)
+}
+
+// Build assembles the enabled entries (first is outermost). Unknown
+// names, duplicates and invalid config blocks are errors.
+func (b *Builder) Build(entries []StackEntry) (Middleware, error) {
+	ms := make([]Middleware, 0, len(entries))
+	seen := map[string]bool{}
+
+	for _, e := range entries {
+		name := normalizeName(e.Name)
+		f, ok := b.factories[name]
+		if !ok {
+			return nil, fmt.Errorf("middleware: unknown middleware %q", e.Name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("middleware: %q listed twice", e.Name)
+		}
+		seen[name] = true
+		if e.Disabled {
+			continue
+		}
+
+		m, err := f(e.Config)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %s: %w", name, err)
+		}
+		if len(e.Config) > 0 {
+			ms = append(ms, Named(name, m, e.Config))
+		} else {
+			ms = append(ms, Named(name, m))
+		}
+	}
+	return Chain(ms...), nil
+}
+
+// Static returns a factory for a middleware without settings.
+func Static(m Middleware) Factory {
+	return func(settings json.RawMessage) (Middleware, error) {
+		if len(settings) > 0 {
+			return nil, errors.New("does not accept a config")
+		}
+		return m, nil
+	}
+}
+
+// Configurable returns a factory decoding the config block over
+// defaults() and passing the result to build.
+func Configurable[T any](defaults func() T, build func(T) Middleware) Factory {
+	return func(settings json.RawMessage) (Middleware, error) {
+		c := defaults()
+		if err := DecodeSettings(settings, &c); err != nil {
+			return nil, err
+		}
+		return build(c), nil
+	}
+}
+
+// DecodeSettings decodes a config block into dst, rejecting unknown
+// fields. An empty block leaves dst unchanged.
+func DecodeSettings(settings json.RawMessage, dst any) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(settings))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return nil
+}
+
+// normalizeName accepts underscores for hyphens.
+func normalizeName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "_", "-")
+}
@@ -0,0 +1,48 @@
+package middleware
+
+/*
+User-agent classification.
+
+Summary
+-------
+- IsBot reports whether a request comes from a crawler, bot or
+  scripted client, based on case-insensitive substrings of the
+  User-Agent header.
+- Requests without a User-Agent are treated as bots: browsers always
+  send one.
+- The classification is a heuristic for traffic shaping (see the bot
+  profile in RateLimitConfig), not a security boundary: the header is
+  controlled by the client.
+*/
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultBotPatterns are lowercase User-Agent substrings of common
+// crawlers, monitoring services and HTTP libraries.
+var DefaultBotPatterns = []string{
+	"bot", "crawl", "spider", "slurp", "scrape",
+	"facebookexternalhit", "bingpreview", "mediapartners",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client",
+	"java/", "okhttp", "libwww-perl", "httpclient", "headlesschrome",
+}
+
+// IsBot reports whether r is classified as a bot by patterns
+// (DefaultBotPatterns if empty).
+func IsBot(r *http.Request, patterns []string) bool {
+	ua := strings.ToLower(r.UserAgent())
+	if ua == "" {
+		return true
+	}
+	if len(patterns) == 0 {
+		patterns = DefaultBotPatterns
+	}
+	for _, p := range patterns {
+		if p != "" && strings.Contains(ua, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+package mirror
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package mirror copies a sample of live traffic to a shadow backend.
+
+Summary
+-------
+- Middleware sends a configurable percentage of matching requests a
+  second time to Config.URL (e.g. a new version of the service) after
+  the real response was written. Shadow requests run asynchronously;
+  their responses are discarded and never affect the client.
+- Request bodies are buffered for mirroring up to MaxBodyBytes; larger
+  requests are served normally and not mirrored.
+- At most MaxInFlight shadow requests run concurrently; further samples
+  are dropped instead of queueing behind a slow shadow.
+- Shadow requests carry the original method, path, query and headers
+  plus X-Mirrored: true and X-Forwarded-For.
+- By default only GET and HEAD are mirrored: mirroring writes requires a
+  shadow backend without side effects on production data.
+- Stats reports mirrored, skipped, dropped and failed requests and how
+  often the shadow answered with a different status than production
+  (mismatches are also logged).
+
+Example config:
+
+	"mirror": {
+	  "enabled": true,
+	  "url": "http://127.0.0.1:9090",
+	  "percent": 10,
+	  "paths": ["/api/*"],
+	  "methods": ["GET", "HEAD"]
+	}
+*/
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Config defines which requests are mirrored and where to.
+type Config struct {
+	Enabled      bool     `json:"enabled"`
+	URL          string   `json:"url"`            // shadow backend (scheme://host[:port][/base])
+	Percent      float64  `json:"percent"`        // share of matching requests mirrored (0-100)
+	Paths        []string `json:"paths"`          // path patterns: exact, /prefix/* or path.Match glob; empty means all
+	Methods      []string `json:"methods"`        // mirrored methods; empty means all
+	MaxBodyBytes int64    `json:"max_body_bytes"` // larger requests are not mirrored
+	MaxInFlight  int      `json:"max_in_flight"`  // concurrent shadow requests
+	Timeout      int      `json:"timeout"`        // seconds per shadow request
+}
+
+// DefaultConfig returns a disabled configuration mirroring 10% of GET
+// and HEAD requests.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:      false,
+		Percent:      10,
+		Methods:      []string{http.MethodGet, http.MethodHead},
+		MaxBodyBytes: 64 << 10,
+		MaxInFlight:  16,
+		Timeout:      5,
+	}
+}
+
+// Stats is a snapshot of the mirror counters.
+type Stats struct {
+	Mirrored   uint64 `json:"mirrored"`   // shadow requests answered
+	Skipped    uint64 `json:"skipped"`    // sampled but body too large
+	Dropped    uint64 `json:"dropped"`    // sampled but MaxInFlight reached
+	Failed     uint64 `json:"failed"`     // transport errors and timeouts
+	Mismatches uint64 `json:"mismatches"` // shadow status differed from production
+}
+
+// Mirror sends sampled requests to a shadow backend.
+type Mirror struct {
+	config Config
+	target *url.URL
+	client *http.Client
+	slots  chan struct{}
+
+	mirrored, skipped, dropped, failed, mismatches atomic.Uint64
+}
+
+// New creates a mirror. It fails if the configuration is enabled with
+// an invalid URL.
+func New(cfg ...Config) (*Mirror, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.MaxInFlight <= 0 {
+		c.MaxInFlight = 1
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5
+	}
+
+	m := &Mirror{
+		config: c,
+		client: &http.Client{
+			Timeout: time.Duration(c.Timeout) * time.Second,
+			// Report redirects as they are instead of following them
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		slots: make(chan struct{}, c.MaxInFlight),
+	}
+	if c.Enabled {
+		u, err := url.Parse(c.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("mirror: invalid shadow url %q", c.URL)
+		}
+		m.target = u
+	}
+	return m, nil
+}
+
+// Stats returns a snapshot of the mirror counters.
+func (m *Mirror) Stats() Stats {
+	return Stats{
+		Mirrored:   m.mirrored.Load(),
+		Skipped:    m.skipped.Load(),
+		Dropped:    m.dropped.Load(),
+		Failed:     m.failed.Load(),
+		Mismatches: m.mismatches.Load(),
+	}
+}
+
+// Middleware returns a middleware mirroring sampled requests.
+// A disabled configuration returns the handler unchanged.
+func (m *Mirror) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if !m.config.Enabled || m.config.Percent <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.sampled(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, ok := m.bufferBody(r)
+			if !ok {
+				m.skipped.Add(1)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Prepare the copy before the handler may modify r
+			shadow := m.shadowRequest(r, body)
+
+			rw := middleware.WrapResponseWriter(w)
+			next.ServeHTTP(rw, r)
+
+			select {
+			case m.slots <- struct{}{}:
+				go m.send(shadow, rw.Status())
+			default:
+				m.dropped.Add(1)
+			}
+		})
+	}
+}
+
+// sampled reports whether r matches the config and was drawn.
+func (m *Mirror) sampled(r *http.Request) bool {
+	c := m.config
+	if len(c.Methods) > 0 && !slices.ContainsFunc(c.Methods, func(s string) bool {
+		return strings.EqualFold(s, r.Method)
+	}) {
+		return false
+	}
+	if len(c.Paths) > 0 && !slices.ContainsFunc(c.Paths, func(p string) bool {
+		return matchPath(p, path.Clean(r.URL.Path))
+	}) {
+		return false
+	}
+	return rand.Float64()*100 < c.Percent
+}
+
+// bufferBody reads the request body (up to MaxBodyBytes) so it can be
+// sent twice. It reports false if the body is too large; r.Body still
+// yields the complete body in that case.
+func (m *Mirror) bufferBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > m.config.MaxBodyBytes {
+		return nil, false
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, m.config.MaxBodyBytes+1))
+	if err != nil || int64(len(buf)) > m.config.MaxBodyBytes {
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+		return nil, false
+	}
+	r.Body = readCloser{bytes.NewReader(buf), r.Body}
+	return buf, true
+}
+
+// shadowRequest builds the request sent to the shadow backend.
+func (m *Mirror) shadowRequest(r *http.Request, body []byte) *http.Request {
+	u := *m.target
+	u.Path = strings.TrimSuffix(u.Path, "/") + r.URL.Path
+	u.RawPath = ""
+	u.RawQuery = r.URL.RawQuery
+
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	// The context is detached: the shadow request outlives the client's
+	shadow, _ := http.NewRequestWithContext(context.Background(), r.Method, u.String(), rd)
+	shadow.Header = r.Header.Clone()
+	for _, h := range hopHeaders {
+		shadow.Header.Del(h)
+	}
+	shadow.Header.Set("X-Mirrored", "true")
+	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if prior := r.Header.Get("X-Forwarded-For"); prior != "" {
+			ip = prior + ", " + ip
+		}
+		shadow.Header.Set("X-Forwarded-For", ip)
+	}
+	shadow.Host = m.target.Host
+	return shadow
+}
+
+// send performs a shadow request and compares its status.
+func (m *Mirror) send(shadow *http.Request, status int) {
+	defer func() { <-m.slots }()
+
+	resp, err := m.client.Do(shadow)
+	if err != nil {
+		m.failed.Add(1)
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+
+	m.mirrored.Add(1)
+	if resp.StatusCode != status {
+		m.mismatches.Add(1)
+		log.Printf("mirror: %s %s status=%d shadow=%d", shadow.Method, shadow.URL.Path, status, resp.StatusCode)
+	}
+}
+
+// hopHeaders are not forwarded to the shadow backend.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Connection", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// matchPath reports whether p matches pattern (exact, /prefix/* or glob).
+func matchPath(pattern, p string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return p == base || strings.HasPrefix(p, base+"/") || (base == "" && p == "/")
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// readCloser combines a reader with the closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,234 @@
+package module
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package module assembles a service from reusable, drop-in features.
+
+Summary
+-------
+- A Module bundles a feature (auth, admin, metrics, a resource ...):
+  its configuration, routes and optional background work.
+- A Registry knows all available modules; Assemble builds the enabled
+  ones from a JSON-serializable Config, decoding each module's settings
+  over its defaults.
+- The assembled Set registers all routes on a server.Server with shared
+  middleware stacks and runs as a lifecycle.Runner (Start in order,
+  Stop in reverse order).
+
+Typical usage:
+
+	reg := module.NewRegistry(notesModule, authModule)
+
+	set, err := reg.Assemble(cfg.Modules)
+	if err != nil { ... }
+	if err := set.Register(srv, module.Middleware{Page: page, API: api, Auth: auth}); err != nil { ... }
+
+	m.Add("modules", set)
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Module is a drop-in feature.
+type Module interface {
+	// Name returns the unique module name used in Config.
+	Name() string
+
+	// DefaultConfig returns a pointer to the module's configuration,
+	// initialized with defaults. Settings from Config are decoded into it
+	// before Register is called. Modules without settings return nil.
+	DefaultConfig() any
+
+	// Register registers the module's routes.
+	Register(srv *server.Server, mw Middleware) error
+
+	// Start starts background work; it must not block.
+	Start(ctx context.Context) error
+
+	// Stop stops background work started by Start.
+	Stop(ctx context.Context) error
+}
+
+// Base provides no-op Start and Stop for modules without background work.
+type Base struct{}
+
+// Start does nothing.
+func (Base) Start(context.Context) error { return nil }
+
+// Stop does nothing.
+func (Base) Stop(context.Context) error { return nil }
+
+// Middleware holds the shared middleware stacks offered to modules.
+// Nil stacks are replaced by a pass-through.
+type Middleware struct {
+	Page middleware.Middleware // HTML pages (e.g. recovery, request ID, logging, CSRF)
+	API  middleware.Middleware // JSON APIs (e.g. CORS, rate limit, request ID, logging)
+	Auth middleware.Middleware // Additionally applied to authenticated API routes
+}
+
+// Config selects and configures modules.
+// It is JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	Enabled  []string                   `json:"enabled"`  // Module names in start order
+	Settings map[string]json.RawMessage `json:"settings"` // Per-module settings, keyed by name
+}
+
+/* ---------- registry ---------- */
+
+// Registry holds all available modules.
+type Registry struct {
+	modules map[string]Module
+	order   []string
+}
+
+// NewRegistry creates a registry with the given modules.
+func NewRegistry(mods ...Module) *Registry {
+	r := &Registry{modules: map[string]Module{}}
+	for _, m := range mods {
+		r.Add(m)
+	}
+	return r
+}
+
+// Add makes a module available. Add panics on duplicate names.
+func (r *Registry) Add(m Module) {
+	name := m.Name()
+	if _, ok := r.modules[name]; ok {
+		panic("module: duplicate module " + name)
+	}
+	r.modules[name] = m
+	r.order = append(r.order, name)
+}
+
+// Names returns the names of all available modules in registration order.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.order...)
+}
+
+// Defaults returns a Config enabling all modules with default settings
+// (e.g. for generating a config file). Call it before Assemble, which
+// decodes settings into the modules' configurations.
+func (r *Registry) Defaults() (Config, error) {
+	cfg := Config{Enabled: r.Names(), Settings: map[string]json.RawMessage{}}
+	for _, name := range r.order {
+		def := r.modules[name].DefaultConfig()
+		if def == nil {
+			continue
+		}
+		b, err := json.Marshal(def)
+		if err != nil {
+			return Config{}, fmt.Errorf("module %s: %w", name, err)
+		}
+		cfg.Settings[name] = b
+	}
+	return cfg, nil
+}
+
+// Assemble configures the enabled modules and returns them as a Set.
+// Unknown module names and invalid settings are errors; settings for
+// modules that are not enabled are ignored.
+func (r *Registry) Assemble(cfg Config) (*Set, error) {
+	set := &Set{}
+	seen := map[string]bool{}
+
+	for _, name := range cfg.Enabled {
+		m, ok := r.modules[name]
+		if !ok {
+			return nil, fmt.Errorf("module: unknown module %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("module: %q enabled twice", name)
+		}
+		seen[name] = true
+
+		if raw, ok := cfg.Settings[name]; ok {
+			dst := m.DefaultConfig()
+			if dst == nil {
+				return nil, fmt.Errorf("module %s: does not accept settings", name)
+			}
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(dst); err != nil {
+				return nil, fmt.Errorf("module %s: settings: %w", name, err)
+			}
+		}
+
+		set.modules = append(set.modules, m)
+	}
+
+	return set, nil
+}
+
+/* ---------- set ---------- */
+
+// Set is an assembled list of modules.
+type Set struct {
+	modules []Module
+}
+
+// Modules returns the modules in start order.
+func (s *Set) Modules() []Module {
+	return append([]Module(nil), s.modules...)
+}
+
+// Register registers the routes of all modules.
+func (s *Set) Register(srv *server.Server, mw Middleware) error {
+	pass := middleware.Chain()
+	if mw.Page == nil {
+		mw.Page = pass
+	}
+	if mw.API == nil {
+		mw.API = pass
+	}
+	if mw.Auth == nil {
+		mw.Auth = pass
+	}
+
+	for _, m := range s.modules {
+		if err := m.Register(srv, mw); err != nil {
+			return fmt.Errorf("module %s: %w", m.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Run starts all modules in order, blocks until ctx is cancelled and
+// stops them in reverse order. It implements lifecycle.Runner.
+// If a module fails to start, the already started ones are stopped.
+func (s *Set) Run(ctx context.Context) error {
+	started := 0
+	var err error
+
+	for _, m := range s.modules {
+		if err = m.Start(ctx); err != nil {
+			err = fmt.Errorf("module %s: start: %w", m.Name(), err)
+			break
+		}
+		log.Printf("module %s started", m.Name())
+		started++
+	}
+
+	if err == nil {
+		<-ctx.Done()
+	}
+
+	stopCtx := context.WithoutCancel(ctx)
+	errs := []error{err}
+	for i := started - 1; i >= 0; i-- {
+		if serr := s.modules[i].Stop(stopCtx); serr != nil {
+			errs = append(errs, fmt.Errorf("module %s: stop: %w", s.modules[i].Name(), serr))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package openapi
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+HTTP handlers for serving an OpenAPI document.
+
+Summary
+-------
+- Handler serves the document as application/json.
+- SwaggerUI serves an embedded HTML page that renders the document with
+  Swagger UI (assets loaded from a CDN).
+- Mount registers both on a ServeMux (/openapi.json and /docs).
+*/
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Handler serves the document as JSON. The document is marshalled once.
+func Handler(d *Document) http.Handler {
+	b, err := d.JSON()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			log.Printf("openapi: %v", err)
+			server.InternalServerError(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(b)
+	})
+}
+
+// swaggerPage is the Swagger UI page template.
+var swaggerPage = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>{{.Title}}</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui" });
+    </script>
+</body>
+</html>`))
+
+// SwaggerUI serves a Swagger UI page rendering the document at specURL.
+func SwaggerUI(title, specURL string) http.Handler {
+	data := map[string]string{"Title": title, "SpecURL": specURL}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := swaggerPage.Execute(w, data); err != nil {
+			log.Printf("openapi: %v", err)
+		}
+	})
+}
+
+// Mount registers the document at GET /openapi.json and the Swagger UI
+// page at GET /docs on mux.
+func Mount(mux *http.ServeMux, d *Document) {
+	mux.Handle("GET /openapi.json", Handler(d))
+	mux.Handle("GET /docs", SwaggerUI(d.Info.Title, "/openapi.json"))
+}
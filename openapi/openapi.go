@@ -0,0 +1,387 @@
+package openapi
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package openapi generates OpenAPI 3 documents from the server route registry.
+
+Summary
+-------
+- Builds an OpenAPI 3.0 document from server.Route entries and their
+  RouteDoc annotations (summary, tags, request/response types).
+- Derives JSON schemas from Go types via encoding/json conventions
+  (json tags, omitempty); named struct types become reusable components.
+- Path parameters are taken from ServeMux wildcards ({id}, {path...}).
+- The middleware wrapping a route is listed in the x-middleware extension.
+- Serves the document as JSON and an embedded Swagger UI page (handler.go).
+- Validates requests and responses against a document (validate.go).
+
+Typical usage:
+
+	doc := openapi.New("Notes API", "1.0.0")
+	doc.AddRoutes(srv.Routes())
+	openapi.Mount(srv.Mux(), doc)
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Document is an OpenAPI 3.0 document (the subset generated by this package).
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info holds API metadata.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation.
+type Operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Middleware  []string             `json:"x-middleware,omitempty"` // names of the route's middleware, outermost first
+}
+
+// Parameter describes an operation parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation request body.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes an operation response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema for a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is a JSON schema object (OpenAPI 3.0 dialect).
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	// Validation keywords (not generated, honored by Validate)
+	Enum      []any    `json:"enum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+}
+
+// New creates an empty OpenAPI 3 document.
+func New(title, version string) *Document {
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+}
+
+// AddRoutes adds an operation for each route. Routes without a method
+// in their pattern are documented as GET.
+func (d *Document) AddRoutes(routes []server.Route) {
+	for _, r := range routes {
+		d.AddRoute(r)
+	}
+}
+
+// AddRoute adds an operation for a single route.
+func (d *Document) AddRoute(r server.Route) {
+	path, params := convertPath(r.Path)
+
+	method := strings.ToLower(r.Method)
+	if method == "" {
+		method = "get"
+	}
+
+	op := &Operation{
+		Summary:     r.Doc.Summary,
+		Description: r.Doc.Description,
+		Tags:        r.Doc.Tags,
+		Parameters:  params,
+		Responses:   map[string]*Response{},
+	}
+	for _, m := range r.Middleware {
+		op.Middleware = append(op.Middleware, m.Name)
+	}
+
+	if r.Doc.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(d.Schema(r.Doc.Request)),
+		}
+	}
+
+	resp := &Response{Description: http.StatusText(http.StatusOK)}
+	if r.Doc.Response != nil {
+		resp.Content = jsonContent(d.Schema(r.Doc.Response))
+	}
+	op.Responses["200"] = resp
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[method] = op
+}
+
+// AddSchema registers the schema of v as a named component and returns
+// a reference to it.
+func (d *Document) AddSchema(name string, v any) *Schema {
+	s := d.schemaOf(reflect.TypeOf(v), map[reflect.Type]bool{})
+	if s.Ref != "" {
+		s = d.Components.Schemas[refName(s.Ref)]
+	}
+	d.Components.Schemas[name] = s
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Schema returns the schema of v's type. Named struct types are added
+// to the components section and referenced.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaOf(reflect.TypeOf(v), map[reflect.Type]bool{})
+}
+
+// Parse decodes an OpenAPI document from JSON (e.g. a hand-written
+// spec used for validation).
+func Parse(b []byte) (*Document, error) {
+	d := New("", "")
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// LoadFile reads and parses an OpenAPI document from a JSON file.
+func LoadFile(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// JSON returns the document as indented JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+/* ---------- schema generation ---------- */
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf derives a schema from a Go type following encoding/json rules.
+func (d *Document) schemaOf(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct:
+		s = d.structSchema(t, seen)
+	default:
+		s = d.basicSchema(t, seen)
+	}
+
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+// basicSchema handles non-struct kinds.
+func (d *Document) basicSchema(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json writes nil slices as null
+		nullable := t.Kind() == reflect.Slice
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem(), seen), Nullable: nullable}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem(), seen), Nullable: true}
+	}
+
+	// interface{} and unsupported kinds: any value
+	return &Schema{}
+}
+
+// structSchema builds an object schema; named types become components.
+func (d *Document) structSchema(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	name := t.Name()
+	if name != "" {
+		ref := &Schema{Ref: "#/components/schemas/" + name}
+		if seen[t] {
+			return ref
+		}
+		if _, ok := d.Components.Schemas[name]; ok {
+			return ref
+		}
+		seen[t] = true
+	}
+
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	d.addFields(s, t, seen)
+
+	if name == "" {
+		return s
+	}
+	d.Components.Schemas[name] = s
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// addFields adds the JSON-visible fields of struct type t to s,
+// flattening embedded structs like encoding/json does.
+func (d *Document) addFields(s *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name are flattened
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.addFields(s, ft, seen)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = d.schemaOf(f.Type, seen)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+/* ---------- helpers ---------- */
+
+// convertPath turns a ServeMux path into an OpenAPI path and extracts
+// its path parameters ({id} and {rest...} wildcards, {$} anchors).
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.Trim(seg, "{}"), "...")
+		if name == "$" {
+			segs[i] = ""
+			continue
+		}
+
+		segs[i] = "{" + name + "}"
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	return strings.Join(segs, "/"), params
+}
+
+// jsonContent wraps a schema into an application/json content map.
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+// refName extracts the component name from a $ref.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
@@ -0,0 +1,483 @@
+package openapi
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Request and response validation against an OpenAPI document.
+
+Summary
+-------
+- Validate returns a middleware that matches each request to an operation
+  of the document (path template + method) and checks path and query
+  parameters and the JSON request body against their schemas.
+- Mismatching requests are answered with a 400 envelope
+  (render.ErrBadRequest) listing each violation as a FieldError.
+- With ValidationConfig.Responses set (intended for development), JSON
+  responses are buffered and checked against the documented response
+  schema; mismatches are logged and replaced by a 500 envelope.
+- Requests for paths or methods not in the document pass through.
+
+Supported schema keywords: $ref, type, format (informational), nullable,
+items, properties, required, additionalProperties, enum, minLength,
+maxLength, pattern, minimum, maximum, minItems, maxItems.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/render"
+)
+
+// ValidationConfig configures the validation middleware.
+type ValidationConfig struct {
+	Responses    bool  `json:"responses"`      // also validate JSON responses (dev mode)
+	MaxBodyBytes int64 `json:"max_body_bytes"` // request body limit for validation
+}
+
+// DefaultValidationConfig returns a configuration validating requests only.
+func DefaultValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		Responses:    false,
+		MaxBodyBytes: 1 << 20,
+	}
+}
+
+// FieldError describes a single validation failure. Path is a JSON
+// pointer-like location such as "body/title" or "query/limit".
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Validate returns a middleware validating requests (and optionally
+// responses) against d.
+func Validate(d *Document, cfg ...ValidationConfig) middleware.Middleware {
+	c := DefaultValidationConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	v := &validator{doc: d, routes: compileRoutes(d)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, params := v.match(r)
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			errs, err := v.request(r, op, params, c.MaxBodyBytes)
+			if err != nil {
+				render.Fail(w, r, render.ErrBadRequest, err.Error(), nil)
+				return
+			}
+			if len(errs) > 0 {
+				render.Fail(w, r, render.ErrBadRequest, "Request does not match the API specification.", errs)
+				return
+			}
+
+			if !c.Responses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &responseBuffer{header: http.Header{}, code: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if errs := v.response(op, rec); len(errs) > 0 {
+				log.Printf("openapi: response for %s %s does not match the specification: %v", r.Method, r.URL.Path, errs)
+				render.Fail(w, r, render.ErrInternal, "Response does not match the API specification.", errs)
+				return
+			}
+			rec.flush(w)
+		})
+	}
+}
+
+/* ---------- routing ---------- */
+
+// route is a compiled path template.
+type route struct {
+	segments []string // literal segment or "{name}"
+	item     *PathItem
+}
+
+// validator holds a compiled document.
+type validator struct {
+	doc    *Document
+	routes []route
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// compileRoutes splits the document paths into segments. Literal
+// routes are sorted before templated ones with the same length so
+// that /notes/new wins over /notes/{id}.
+func compileRoutes(d *Document) []route {
+	var literal, templated []route
+	for p, item := range d.Paths {
+		rt := route{segments: strings.Split(strings.Trim(p, "/"), "/"), item: item}
+		if strings.Contains(p, "{") {
+			templated = append(templated, rt)
+		} else {
+			literal = append(literal, rt)
+		}
+	}
+	return append(literal, templated...)
+}
+
+// match returns the operation for r and its path parameters.
+func (v *validator) match(r *http.Request) (*Operation, map[string]string) {
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	method := strings.ToLower(r.Method)
+
+	for _, rt := range v.routes {
+		params, ok := matchSegments(rt.segments, segs)
+		if !ok || rt.item == nil {
+			continue
+		}
+		if op := (*rt.item)[method]; op != nil {
+			return op, params
+		}
+	}
+	return nil, nil
+}
+
+// matchSegments matches a path template against request segments.
+// A trailing template segment also matches the rest of the path
+// (ServeMux {name...} wildcards are documented as {name}).
+func matchSegments(tmpl, segs []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, t := range tmpl {
+		if i >= len(segs) {
+			return nil, false
+		}
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			name := t[1 : len(t)-1]
+			if i == len(tmpl)-1 && len(segs) > len(tmpl) {
+				params[name] = strings.Join(segs[i:], "/")
+				return params, true
+			}
+			params[name] = segs[i]
+			continue
+		}
+		if t != segs[i] {
+			return nil, false
+		}
+	}
+	return params, len(tmpl) == len(segs)
+}
+
+/* ---------- request / response ---------- */
+
+// request validates parameters and the JSON body of r. The body is
+// restored for the next handler. A non-nil error means the body could
+// not be read or decoded at all.
+func (v *validator) request(r *http.Request, op *Operation, params map[string]string, limit int64) ([]FieldError, error) {
+	var errs []FieldError
+
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw, present = params[p.Name]
+		case "query":
+			present = query.Has(p.Name)
+			raw = query.Get(p.Name)
+		case "header":
+			raw = r.Header.Get(p.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		loc := p.In + "/" + p.Name
+		if !present {
+			if p.Required {
+				errs = append(errs, FieldError{Path: loc, Message: "is required"})
+			}
+			continue
+		}
+		errs = v.check(errs, loc, p.Schema, coerce(raw, v.resolve(p.Schema)))
+	}
+
+	if op.RequestBody == nil {
+		return errs, nil
+	}
+
+	mt, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return errs, nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultValidationConfig().MaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body")
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("request body too large")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			errs = append(errs, FieldError{Path: "body", Message: "is required"})
+		}
+		return errs, nil
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON body")
+	}
+	return v.check(errs, "body", mt.Schema, doc), nil
+}
+
+// response validates a buffered JSON response against op.
+func (v *validator) response(op *Operation, rec *responseBuffer) []FieldError {
+	resp := op.Responses[strconv.Itoa(rec.code)]
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	if resp == nil {
+		// Undocumented status codes (errors) are not checked.
+		return nil
+	}
+
+	mt, ok := resp.Content["application/json"]
+	if !ok || mt.Schema == nil || !strings.Contains(rec.header.Get("Content-Type"), "json") {
+		return nil
+	}
+
+	doc, err := decodeJSON(rec.body.Bytes())
+	if err != nil {
+		return []FieldError{{Path: "response", Message: "invalid JSON"}}
+	}
+	return v.check(nil, "response", mt.Schema, doc)
+}
+
+// decodeJSON decodes a JSON value keeping numbers as json.Number.
+func decodeJSON(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data")
+	}
+	return out, nil
+}
+
+// coerce converts a raw parameter string into the JSON type of s.
+func coerce(raw string, s *Schema) any {
+	if s == nil {
+		return raw
+	}
+	switch s.Type {
+	case "integer", "number":
+		return json.Number(raw)
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+/* ---------- schema checks ---------- */
+
+// resolve follows $ref to a component schema.
+func (v *validator) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < 32; i++ {
+		s = v.doc.Components.Schemas[refName(s.Ref)]
+	}
+	return s
+}
+
+// check validates value against s and appends failures to errs.
+func (v *validator) check(errs []FieldError, path string, s *Schema, value any) []FieldError {
+	s = v.resolve(s)
+	if s == nil {
+		return errs
+	}
+	fail := func(format string, args ...any) []FieldError {
+		return append(errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return errs
+		}
+		return fail("must not be null")
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		return fail("must be one of %v", s.Enum)
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		n := len([]rune(str))
+		if s.MinLength != nil && n < *s.MinLength {
+			return fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := v.pattern(s.Pattern)
+			if err != nil {
+				log.Printf("openapi: invalid pattern %q: %v", s.Pattern, err)
+			} else if !re.MatchString(str) {
+				return fail("must match %s", s.Pattern)
+			}
+		}
+
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		f, err := num.Float64()
+		if !ok || err != nil {
+			return fail("must be a number")
+		}
+		if _, err := num.Int64(); s.Type == "integer" && err != nil {
+			return fail("must be an integer")
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fail("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fail("must be <= %v", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fail("must be an array")
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return fail("must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range arr {
+			errs = v.check(errs, path+"/"+strconv.Itoa(i), s.Items, item)
+		}
+
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fail("must be an object")
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, FieldError{Path: path + "/" + name, Message: "is required"})
+			}
+		}
+		for name, val := range obj {
+			if ps, ok := s.Properties[name]; ok {
+				errs = v.check(errs, path+"/"+name, ps, val)
+			} else if s.AdditionalProperties != nil {
+				errs = v.check(errs, path+"/"+name, s.AdditionalProperties, val)
+			}
+		}
+	}
+	return errs
+}
+
+// inEnum reports whether value equals one of the enum values.
+func inEnum(enum []any, value any) bool {
+	if n, ok := value.(json.Number); ok {
+		f, _ := n.Float64()
+		value = f
+	}
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// pattern returns a cached compiled regular expression.
+func (v *validator) pattern(p string) (*regexp.Regexp, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if re, ok := v.patterns[p]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	if v.patterns == nil {
+		v.patterns = map[string]*regexp.Regexp{}
+	}
+	v.patterns[p] = re
+	return re, nil
+}
+
+/* ---------- response buffering ---------- */
+
+// responseBuffer captures a response for validation.
+type responseBuffer struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+	wrote  bool
+}
+
+func (b *responseBuffer) Header() http.Header { return b.header }
+
+func (b *responseBuffer) WriteHeader(code int) {
+	if !b.wrote {
+		b.code = code
+		b.wrote = true
+	}
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.wrote = true
+	return b.body.Write(p)
+}
+
+// flush copies the buffered response to w.
+func (b *responseBuffer) flush(w http.ResponseWriter) {
+	h := w.Header()
+	for k, vs := range b.header {
+		h[k] = vs
+	}
+	w.WriteHeader(b.code)
+	_, _ = w.Write(b.body.Bytes())
+}
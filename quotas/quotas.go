@@ -0,0 +1,327 @@
+package quotas
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package quotas meters API usage per user or API key over daily and
+monthly windows.
+
+Summary
+-------
+- Unlike the rate limiter (short bursts per client IP), quotas count
+  requests per caller over calendar windows: the day and the month in
+  Config.Timezone. Counters reset at midnight and on the first of the
+  month.
+- The caller is identified by a verified Bearer claim (default "sub")
+  or, if a key validator is set (SetKeyValidator), by an API key header;
+  requests without identity are not metered. Unverified headers must not
+  be used for metering: anyone could pick a fresh key per request to
+  bypass the quota and grow the counters without bound. API keys are
+  stored as a short SHA-256 fingerprint ("key:<hex>"), never in clear.
+- Overrides assign other limits to individual callers (e.g. paid plans).
+- Every metered response carries X-Quota-Limit, X-Quota-Remaining and
+  X-Quota-Reset (Unix time) for the window closest to its limit.
+- Exceeded quotas are answered with 429 and Retry-After via the server
+  error helpers, unless Config.Enforce is false (soft quotas: only
+  headers and usage reporting).
+- Handler serves the caller's usage as JSON.
+- Counters live in a pluggable Store (in-memory by default, store.go).
+  If the store fails, requests are allowed and the error is logged.
+
+Example config:
+
+	"quotas": {
+	  "enabled": true,
+	  "enforce": true,
+	  "daily": 1000,
+	  "monthly": 20000,
+	  "overrides": { "customer-42": { "daily": 10000, "monthly": 250000 } }
+	}
+*/
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/render"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Limits are request limits per window; 0 means unlimited.
+type Limits struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+// Config defines quotas.
+// It is JSON-serializable and intended to be part of a global application config.
+type Config struct {
+	Enabled   bool              `json:"enabled"`
+	Enforce   bool              `json:"enforce"` // reject requests over quota; false only reports usage
+	Limits                      // default limits per caller
+	Overrides map[string]Limits `json:"overrides,omitempty"` // limits per caller identity
+	Claim     string            `json:"claim"`               // Bearer claim identifying the caller
+	KeyHeader string            `json:"key_header"`          // header carrying an API key; needs SetKeyValidator
+	Timezone  string            `json:"timezone"`            // IANA time zone of the windows
+}
+
+// DefaultConfig returns a disabled configuration with enforced limits of
+// 1000 requests per day and 20000 per month.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:   false,
+		Enforce:   true,
+		Limits:    Limits{Daily: 1000, Monthly: 20000},
+		Overrides: map[string]Limits{},
+		Claim:     "sub",
+		KeyHeader: "",
+		Timezone:  "UTC",
+	}
+}
+
+// IdentityFunc returns the caller identity of r, or false if the
+// request is not metered.
+type IdentityFunc func(r *http.Request) (string, bool)
+
+// Window is the usage of a caller in one window.
+type Window struct {
+	Limit     int64     `json:"limit"` // 0 means unlimited
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// Usage is the usage of a caller.
+type Usage struct {
+	Daily   Window `json:"daily"`
+	Monthly Window `json:"monthly"`
+}
+
+// exceeded reports whether a window is over its limit.
+func (w Window) exceeded() bool {
+	return w.Limit > 0 && w.Used > w.Limit
+}
+
+// Quotas meters requests.
+type Quotas struct {
+	config   Config
+	loc      *time.Location
+	store    Store
+	identity IdentityFunc
+	now      func() time.Time
+}
+
+// New creates quotas with an in-memory store. It fails on unknown time zones.
+func New(cfg ...Config) (*Quotas, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	loc := time.UTC
+	if c.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.Timezone); err != nil {
+			return nil, fmt.Errorf("quotas: %w", err)
+		}
+	}
+
+	return &Quotas{
+		config:   c,
+		loc:      loc,
+		store:    NewMemory(),
+		identity: DefaultIdentity(c.Claim, c.KeyHeader, nil),
+		now:      time.Now,
+	}, nil
+}
+
+// SetStore replaces the counter store. It must be called before the
+// middleware serves requests.
+func (q *Quotas) SetStore(s Store) {
+	q.store = s
+}
+
+// SetIdentity replaces the identity source.
+func (q *Quotas) SetIdentity(fn IdentityFunc) {
+	q.identity = fn
+}
+
+// SetKeyValidator meters callers by the API key in Config.KeyHeader,
+// for keys valid reports as genuine. It replaces the identity source.
+func (q *Quotas) SetKeyValidator(valid func(r *http.Request, key string) bool) {
+	q.identity = DefaultIdentity(q.config.Claim, q.config.KeyHeader, valid)
+}
+
+// DefaultIdentity identifies callers by the Bearer claim (read from
+// middleware.BearerContextMap) and falls back to the API key header.
+// Keys are used only if valid accepts them; with a nil valid the header
+// is ignored, since an unverified key is chosen by the caller.
+func DefaultIdentity(claim, header string, valid func(r *http.Request, key string) bool) IdentityFunc {
+	return func(r *http.Request) (string, bool) {
+		if claims, ok := middleware.GetBearerClaimsMap(r.Context()); ok {
+			if s, _ := claims[claim].(string); s != "" {
+				return s, true
+			}
+		}
+		if header == "" || valid == nil {
+			return "", false
+		}
+		if key := r.Header.Get(header); key != "" && valid(r, key) {
+			sum := sha256.Sum256([]byte(key))
+			return "key:" + hex.EncodeToString(sum[:8]), true
+		}
+		return "", false
+	}
+}
+
+// limits returns the limits of a caller.
+func (q *Quotas) limits(id string) Limits {
+	if l, ok := q.config.Overrides[id]; ok {
+		return l
+	}
+	return q.config.Limits
+}
+
+// windows returns the store keys and resets of the current day and month.
+func (q *Quotas) windows(id string) (dayKey, monthKey string, dayReset, monthReset time.Time) {
+	now := q.now().In(q.loc)
+	y, m, d := now.Date()
+	dayReset = time.Date(y, m, d+1, 0, 0, 0, 0, q.loc)
+	monthReset = time.Date(y, m+1, 1, 0, 0, 0, 0, q.loc)
+	dayKey = fmt.Sprintf("quota:d:%04d%02d%02d:%s", y, m, d, id)
+	monthKey = fmt.Sprintf("quota:m:%04d%02d:%s", y, m, id)
+	return
+}
+
+// Add counts n requests for id (negative n takes them back) and
+// returns the resulting usage.
+func (q *Quotas) Add(ctx context.Context, id string, n int64) (Usage, error) {
+	return q.usage(ctx, id, n)
+}
+
+// Usage returns the current usage of id without counting a request.
+func (q *Quotas) Usage(ctx context.Context, id string) (Usage, error) {
+	return q.usage(ctx, id, 0)
+}
+
+// usage adds n (if not zero) and reads both windows.
+func (q *Quotas) usage(ctx context.Context, id string, n int64) (Usage, error) {
+	l := q.limits(id)
+	dk, mk, dr, mr := q.windows(id)
+
+	count := func(key string, reset time.Time) (int64, error) {
+		if n != 0 {
+			return q.store.Incr(ctx, key, n, reset)
+		}
+		return q.store.Get(ctx, key)
+	}
+
+	du, err := count(dk, dr)
+	if err != nil {
+		return Usage{}, err
+	}
+	mu, err := count(mk, mr)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		Daily:   window(l.Daily, du, dr),
+		Monthly: window(l.Monthly, mu, mr),
+	}, nil
+}
+
+// window builds a Window from limit and usage.
+func window(limit, used int64, reset time.Time) Window {
+	w := Window{Limit: limit, Used: used, Reset: reset}
+	if limit > 0 {
+		w.Remaining = max(limit-used, 0)
+	}
+	return w
+}
+
+// Middleware returns a middleware metering identified callers. Place it
+// after the middleware that establishes the identity (e.g.
+// BearerContextMap). A disabled configuration returns the handler unchanged.
+func (q *Quotas) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if !q.config.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := q.identity(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			u, err := q.Add(r.Context(), id, 1)
+			if err != nil {
+				log.Printf("quotas: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tight := u.tightest()
+			if tight.Limit > 0 {
+				h := w.Header()
+				h.Set("X-Quota-Limit", strconv.FormatInt(tight.Limit, 10))
+				h.Set("X-Quota-Remaining", strconv.FormatInt(tight.Remaining, 10))
+				h.Set("X-Quota-Reset", strconv.FormatInt(tight.Reset.Unix(), 10))
+			}
+
+			if q.config.Enforce && tight.exceeded() {
+				// Rejected requests do not use up the quota
+				if _, err := q.Add(r.Context(), id, -1); err != nil {
+					log.Printf("quotas: %v", err)
+				}
+				secs := int(tight.Reset.Sub(q.now()).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+				server.TooManyRequests(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tightest returns the window closest to (or furthest over) its limit.
+func (u Usage) tightest() Window {
+	switch {
+	case u.Daily.Limit == 0:
+		return u.Monthly
+	case u.Monthly.Limit == 0:
+		return u.Daily
+	case u.Monthly.exceeded() || (!u.Daily.exceeded() && u.Monthly.Remaining < u.Daily.Remaining):
+		return u.Monthly
+	}
+	return u.Daily
+}
+
+// Handler returns a handler serving the caller's usage as JSON.
+// Unidentified callers get 401.
+func (q *Quotas) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := q.identity(r)
+		if !ok {
+			server.Unauthorized(w, r)
+			return
+		}
+
+		u, err := q.Usage(r.Context(), id)
+		if err != nil {
+			server.RespondError(w, r, err)
+			return
+		}
+		render.JSON(w, r, http.StatusOK, u)
+	})
+}
@@ -0,0 +1,92 @@
+package quotas
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Quota counter stores.
+
+Summary
+-------
+- Store is the pluggable counter backend: Incr and Get on string keys
+  that expire at the end of their window.
+- Memory is an in-process store; counters are lost on restart and not
+  shared between instances. Use a shared store (e.g. Redis INCR with
+  PEXPIREAT, or a database table) when running several instances.
+*/
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps request counters.
+type Store interface {
+	// Incr adds n to the counter for key and returns the new value.
+	// A new counter expires at expires.
+	Incr(ctx context.Context, key string, n int64, expires time.Time) (int64, error)
+
+	// Get returns the current counter for key (0 if missing or expired).
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// Memory is an in-memory Store. It is safe for concurrent use.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]*counter
+	sweep   time.Time
+}
+
+// counter is a stored count with its expiry.
+type counter struct {
+	n       int64
+	expires time.Time
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]*counter{}}
+}
+
+// Incr implements Store.
+func (m *Memory) Incr(_ context.Context, key string, n int64, expires time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.prune(now)
+
+	c, ok := m.entries[key]
+	if !ok || !now.Before(c.expires) {
+		c = &counter{expires: expires}
+		m.entries[key] = c
+	}
+	c.n += n
+	return c.n, nil
+}
+
+// Get implements Store.
+func (m *Memory) Get(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.entries[key]
+	if !ok || !time.Now().Before(c.expires) {
+		return 0, nil
+	}
+	return c.n, nil
+}
+
+// prune removes expired counters at most once per minute.
+func (m *Memory) prune(now time.Time) {
+	if now.Before(m.sweep) {
+		return
+	}
+	m.sweep = now.Add(time.Minute)
+	for k, c := range m.entries {
+		if !now.Before(c.expires) {
+			delete(m.entries, k)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package recorder
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package recorder captures sampled requests to disk for later replay.
+
+Summary
+-------
+- Middleware records a sample of incoming requests (method, URL, headers,
+  body) plus the response status and duration.
+- Records are appended as JSON lines to <dir>/requests-YYYYMMDD.jsonl
+  (one file per day).
+- Sensitive headers (Authorization, Cookie, ...) are redacted by default.
+- Bodies are captured up to MaxBodyBytes; the handler still sees the
+  complete body.
+- ReadFile and Record.Request turn recordings back into requests; the
+  "replay" command of servercli re-sends them against a target.
+
+Recording is intended for debugging: keep the sample rate low in
+production and mind that recorded bodies may contain personal data.
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Config defines the configuration of the request recorder.
+type Config struct {
+	Enabled       bool     `json:"enabled"`
+	Dir           string   `json:"dir"`            // output directory
+	SampleRate    float64  `json:"sample_rate"`    // fraction of requests to record (0..1)
+	MaxBodyBytes  int64    `json:"max_body_bytes"` // body bytes to capture per request
+	RedactHeaders []string `json:"redact_headers"` // header values replaced by "REDACTED"
+	SkipPaths     []string `json:"skip_paths"`     // path prefixes never recorded
+}
+
+// DefaultConfig returns a disabled recorder sampling 1% of requests.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:       false,
+		Dir:           "recordings",
+		SampleRate:    0.01,
+		MaxBodyBytes:  64 << 10,
+		RedactHeaders: []string{"Authorization", "Cookie", "Set-Cookie", "X-Csrf-Token"},
+		SkipPaths:     []string{},
+	}
+}
+
+// Redacted replaces the values of redacted headers.
+const Redacted = "REDACTED"
+
+// Record is a single recorded request.
+type Record struct {
+	Time      time.Time     `json:"time"`
+	RequestID string        `json:"request_id,omitempty"`
+	Method    string        `json:"method"`
+	URL       string        `json:"url"` // path and query
+	Host      string        `json:"host"`
+	Header    http.Header   `json:"header"`
+	Body      []byte        `json:"body,omitempty"` // base64 in JSON
+	Truncated bool          `json:"truncated,omitempty"`
+	Status    int           `json:"status"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Request builds a replayable request for r against target
+// (scheme and host, e.g. http://localhost:8080). Redacted headers and
+// hop-by-hop headers are dropped.
+func (r Record) Request(target *url.URL) (*http.Request, error) {
+	u, err := target.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(r.Method, u.String(), bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range r.Header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Connection", "Content-Length", "Keep-Alive", "Transfer-Encoding", "Upgrade":
+			continue
+		}
+		for _, v := range values {
+			if v != Redacted {
+				req.Header.Add(name, v)
+			}
+		}
+	}
+	return req, nil
+}
+
+// Recorder writes sampled requests to disk.
+type Recorder struct {
+	config Config
+
+	mu   sync.Mutex
+	day  string
+	file *os.File
+	w    *bufio.Writer
+}
+
+// New creates a recorder writing to cfg.Dir.
+func New(cfg ...Config) (*Recorder, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.Enabled {
+		if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Recorder{config: c}, nil
+}
+
+// Middleware returns a middleware recording sampled requests.
+// A disabled (or nil) recorder returns the handler unchanged.
+func (rec *Recorder) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if rec == nil || !rec.config.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rec.sample(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			entry := Record{
+				Time:   start.UTC(),
+				Method: r.Method,
+				URL:    r.URL.RequestURI(),
+				Host:   r.Host,
+				Header: rec.redact(r.Header),
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				body, err := io.ReadAll(io.LimitReader(r.Body, rec.config.MaxBodyBytes+1))
+				if err == nil {
+					if int64(len(body)) > rec.config.MaxBodyBytes {
+						entry.Truncated = true
+						entry.Body = body[:rec.config.MaxBodyBytes]
+					} else {
+						entry.Body = body
+					}
+				}
+				// The handler sees the complete body
+				r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			}
+
+			sw := middleware.WrapResponseWriter(w)
+			next.ServeHTTP(sw, r)
+
+			entry.RequestID = middleware.GetRequestID(r.Context())
+			entry.Status = sw.Status()
+			entry.Duration = time.Since(start)
+
+			if err := rec.write(entry); err != nil {
+				log.Printf("recorder: %v", err)
+			}
+		})
+	}
+}
+
+// Close flushes and closes the current recording file.
+func (rec *Recorder) Close() error {
+	if rec == nil {
+		return nil
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.closeFile()
+}
+
+// sample decides whether r is recorded.
+func (rec *Recorder) sample(r *http.Request) bool {
+	for _, p := range rec.config.SkipPaths {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return false
+		}
+	}
+	return rec.config.SampleRate >= 1 || rand.Float64() < rec.config.SampleRate
+}
+
+// redact returns a copy of h with sensitive values replaced.
+func (rec *Recorder) redact(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range rec.config.RedactHeaders {
+		if vs, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			for i := range vs {
+				vs[i] = Redacted
+			}
+		}
+	}
+	return out
+}
+
+// write appends a record to the file of the current day.
+func (rec *Recorder) write(entry Record) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	day := entry.Time.Format("20060102")
+	if rec.file == nil || day != rec.day {
+		if err := rec.closeFile(); err != nil {
+			log.Printf("recorder: %v", err)
+		}
+		path := filepath.Join(rec.config.Dir, "requests-"+day+".jsonl")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		rec.file, rec.w, rec.day = f, bufio.NewWriter(f), day
+	}
+
+	rec.w.Write(b)
+	rec.w.WriteByte('\n')
+	// Flush per record so a crash does not lose the interesting requests
+	return rec.w.Flush()
+}
+
+// closeFile closes the current file. The caller holds rec.mu.
+func (rec *Recorder) closeFile() error {
+	if rec.file == nil {
+		return nil
+	}
+	err := rec.w.Flush()
+	if cerr := rec.file.Close(); err == nil {
+		err = cerr
+	}
+	rec.file, rec.w = nil, nil
+	return err
+}
+
+// ReadFile reads all records of a recording file.
+func ReadFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Record
+	dec := json.NewDecoder(f)
+	for line := 1; ; line++ {
+		var r Record
+		if err := dec.Decode(&r); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return out, fmt.Errorf("%s: record %d: %w", path, line, err)
+		}
+		out = append(out, r)
+	}
+}
+
+// readCloser combines a reader with the original body's Close.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,270 @@
+package redirects
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package redirects serves declarative redirects from the JSON config or a
+CSV file.
+
+Summary
+-------
+- A Rule maps a request path to a target path or URL with a status code
+  (301 by default, see Config.Status).
+- Rules from the config come first, then the lines of Config.File; the
+  first matching rule applies. Requests matching no rule pass through.
+- Captures are inserted into the target: the rest of a wildcard pattern
+  as $1, regular expression groups as $1, $2 ... or ${name}.
+- The query string of the request is kept unless the target has one.
+- Load swaps the rules in place (e.g. on SIGHUP), so moved URLs need no
+  deploy; on error the previous rules stay active.
+
+Patterns:
+
+	/old-page          exact path
+	/blog/*            /blog and everything below ($1 = rest of the path)
+	~^/p/(?P<id>\d+)$  regular expression (prefix ~) on the path
+
+Example config:
+
+	"redirects": {
+	  "status": 301,
+	  "file": "redirects.csv",
+	  "rules": [
+	    { "from": "/about-us", "to": "/about" },
+	    { "from": "/blog/*", "to": "https://blog.example.com/$1", "status": 302 }
+	  ]
+	}
+
+Example file (from,to[,status]; lines starting with # are comments):
+
+	# spring campaign
+	/spring,/notes?tag=spring,302
+	~^/n/(\d+)$,/notes/$1
+*/
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Rule maps requests to a redirect target.
+type Rule struct {
+	From   string `json:"from"`             // path pattern (see package doc)
+	To     string `json:"to"`               // target path or URL; $1, ${name} insert captures
+	Status int    `json:"status,omitempty"` // 301, 302, 303, 307 or 308; 0 uses Config.Status
+}
+
+// Config defines the redirect rules.
+type Config struct {
+	Rules  []Rule `json:"rules"`
+	File   string `json:"file,omitempty"` // CSV file with from,to[,status] lines, checked after Rules
+	Status int    `json:"status"`         // default status code
+}
+
+// DefaultConfig returns a configuration without rules.
+func DefaultConfig() Config {
+	return Config{
+		Rules:  []Rule{},
+		Status: http.StatusMovedPermanently,
+	}
+}
+
+// rule is a Rule with its compiled pattern.
+type rule struct {
+	Rule
+	re *regexp.Regexp
+}
+
+// Redirector serves a set of rules. It is safe for concurrent use.
+type Redirector struct {
+	rules atomic.Pointer[[]rule]
+}
+
+// New compiles the rules of cfg and reads Config.File. It fails on
+// malformed patterns, unknown status codes or an unreadable file.
+func New(cfg ...Config) (*Redirector, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	rd := &Redirector{}
+	if err := rd.Load(c); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// Load replaces the rules with those of cfg. On error the current rules
+// are kept.
+func (rd *Redirector) Load(cfg Config) error {
+	if cfg.Status == 0 {
+		cfg.Status = DefaultConfig().Status
+	}
+	if !validStatus(cfg.Status) {
+		return fmt.Errorf("redirects: invalid status %d", cfg.Status)
+	}
+
+	rules := make([]rule, 0, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		cr, err := compile(r, cfg.Status)
+		if err != nil {
+			return fmt.Errorf("redirects: rule %d: %w", i, err)
+		}
+		rules = append(rules, cr)
+	}
+
+	if cfg.File != "" {
+		f, err := os.Open(cfg.File)
+		if err != nil {
+			return fmt.Errorf("redirects: %w", err)
+		}
+		defer f.Close()
+
+		fileRules, err := ReadCSV(f)
+		if err != nil {
+			return fmt.Errorf("redirects: %s: %w", cfg.File, err)
+		}
+		for _, r := range fileRules {
+			cr, err := compile(r, cfg.Status)
+			if err != nil {
+				return fmt.Errorf("redirects: %s: %q: %w", cfg.File, r.From, err)
+			}
+			rules = append(rules, cr)
+		}
+	}
+
+	rd.rules.Store(&rules)
+	return nil
+}
+
+// Len returns the number of rules.
+func (rd *Redirector) Len() int {
+	return len(*rd.rules.Load())
+}
+
+// Match returns the target and status code of the first rule matching
+// r, or false if no rule matches.
+func (rd *Redirector) Match(r *http.Request) (target string, status int, ok bool) {
+	p := r.URL.Path
+	for _, ru := range *rd.rules.Load() {
+		m := ru.re.FindStringSubmatchIndex(p)
+		if m == nil {
+			continue
+		}
+
+		target = string(ru.re.ExpandString(nil, ru.To, p, m))
+		if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+			target += "?" + r.URL.RawQuery
+		}
+		if target == r.URL.RequestURI() {
+			continue // would redirect to itself
+		}
+		return target, ru.Status, true
+	}
+	return "", 0, false
+}
+
+// Middleware returns a middleware redirecting matching requests and
+// passing on all others.
+func (rd *Redirector) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if target, status, ok := rd.Match(r); ok {
+				http.Redirect(w, r, target, status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadCSV reads rules from lines "from,to[,status]". Empty lines and
+// lines starting with # are skipped, as is a header line starting with
+// "from".
+func ReadCSV(rd io.Reader) ([]Rule, error) {
+	cr := csv.NewReader(rd)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var rules []Rule
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		if len(rules) == 0 && strings.EqualFold(strings.TrimSpace(rec[0]), "from") {
+			continue
+		}
+		if len(rec) < 2 || len(rec) > 3 {
+			return nil, fmt.Errorf("line %d: want from,to[,status]", line)
+		}
+
+		r := Rule{From: strings.TrimSpace(rec[0]), To: strings.TrimSpace(rec[1])}
+		if len(rec) == 3 && strings.TrimSpace(rec[2]) != "" {
+			r.Status, err = strconv.Atoi(strings.TrimSpace(rec[2]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid status %q", line, rec[2])
+			}
+		}
+		rules = append(rules, r)
+	}
+}
+
+// compile builds the regular expression of r.
+func compile(r Rule, status int) (rule, error) {
+	if r.To == "" {
+		return rule{}, errors.New("empty target")
+	}
+	if r.Status == 0 {
+		r.Status = status
+	}
+	if !validStatus(r.Status) {
+		return rule{}, fmt.Errorf("invalid status %d", r.Status)
+	}
+
+	var expr string
+	switch {
+	case strings.HasPrefix(r.From, "~"):
+		expr = r.From[1:]
+	case !strings.HasPrefix(r.From, "/"):
+		return rule{}, fmt.Errorf("pattern %q must start with / or ~", r.From)
+	case strings.HasSuffix(r.From, "/*"):
+		expr = "^" + regexp.QuoteMeta(strings.TrimSuffix(r.From, "/*")) + "(?:/(.*))?$"
+	default:
+		expr = "^" + regexp.QuoteMeta(r.From) + "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return rule{}, err
+	}
+	return rule{Rule: r, re: re}, nil
+}
+
+// validStatus reports whether code is a redirect status.
+func validStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package render
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Standard API response envelope and application error codes.
+
+Summary
+-------
+- Envelope gives every API response the same shape:
+  {"data": ..., "error": ..., "meta": ..., "request_id": ...}.
+- OK and Fail write enveloped success and error responses and fill in
+  the request ID from the request context.
+- A registry maps application error codes (e.g. "note_not_found") to
+  HTTP status codes and default messages, so teams share one vocabulary.
+- Using the envelope is optional; JSON/XML/Text remain available.
+*/
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// Envelope is the standard API response body.
+type Envelope struct {
+	Data      any            `json:"data,omitempty"`
+	Error     *APIError      `json:"error,omitempty"`
+	Meta      map[string]any `json:"meta,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+// APIError is the error object of an enveloped error response.
+type APIError struct {
+	Code    string `json:"code"`              // Application error code
+	Message string `json:"message"`           // Human-readable message
+	Details any    `json:"details,omitempty"` // Optional details (e.g. field problems)
+}
+
+// ErrorCode is a registered application error code.
+type ErrorCode struct {
+	Code    string // Machine-readable code, e.g. "validation_failed"
+	Status  int    // HTTP status code
+	Message string // Default message
+}
+
+// Predefined error codes.
+var (
+	ErrBadRequest       = RegisterErrorCode("bad_request", http.StatusBadRequest, "The request could not be processed.")
+	ErrUnauthorized     = RegisterErrorCode("unauthorized", http.StatusUnauthorized, "Authentication is required.")
+	ErrForbidden        = RegisterErrorCode("forbidden", http.StatusForbidden, "Access to this resource is denied.")
+	ErrNotFound         = RegisterErrorCode("not_found", http.StatusNotFound, "The requested resource does not exist.")
+	ErrConflict         = RegisterErrorCode("conflict", http.StatusConflict, "The request conflicts with the current state.")
+	ErrValidationFailed = RegisterErrorCode("validation_failed", http.StatusUnprocessableEntity, "The request failed validation.")
+	ErrRateLimited      = RegisterErrorCode("rate_limited", http.StatusTooManyRequests, "Too many requests.")
+	ErrInternal         = RegisterErrorCode("internal", http.StatusInternalServerError, "An internal error occurred.")
+)
+
+var (
+	// codesMu guards codes.
+	codesMu sync.RWMutex
+
+	// codes holds all registered error codes by code.
+	codes = map[string]ErrorCode{}
+)
+
+// RegisterErrorCode registers an application error code and returns it.
+// Registering an existing code replaces it.
+func RegisterErrorCode(code string, status int, message string) ErrorCode {
+	ec := ErrorCode{Code: code, Status: status, Message: message}
+
+	codesMu.Lock()
+	codes[code] = ec
+	codesMu.Unlock()
+
+	return ec
+}
+
+// LookupErrorCode returns a registered error code.
+func LookupErrorCode(code string) (ErrorCode, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	ec, ok := codes[code]
+	return ec, ok
+}
+
+// ErrorCodes returns all registered error codes sorted by code,
+// e.g. for API documentation.
+func ErrorCodes() []ErrorCode {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	out := make([]ErrorCode, 0, len(codes))
+	for _, ec := range codes {
+		out = append(out, ec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// OK writes an enveloped success response with optional metadata
+// (e.g. pagination).
+func OK(w http.ResponseWriter, r *http.Request, code int, data any, meta ...map[string]any) {
+	env := Envelope{
+		Data:      data,
+		RequestID: middleware.GetRequestID(r.Context()),
+	}
+	if len(meta) > 0 {
+		env.Meta = meta[0]
+	}
+	JSON(w, r, code, env)
+}
+
+// Fail writes an enveloped error response for ec. An empty message uses
+// the code's default message; details are optional.
+func Fail(w http.ResponseWriter, r *http.Request, ec ErrorCode, message string, details any) {
+	if message == "" {
+		message = ec.Message
+	}
+
+	JSON(w, r, ec.Status, Envelope{
+		Error: &APIError{
+			Code:    ec.Code,
+			Message: message,
+			Details: details,
+		},
+		RequestID: middleware.GetRequestID(r.Context()),
+	})
+}
+
+// FailCode writes an enveloped error response for a registered code.
+// Unknown codes are reported as ErrInternal.
+func FailCode(w http.ResponseWriter, r *http.Request, code, message string, details any) {
+	ec, ok := LookupErrorCode(code)
+	if !ok {
+		ec = ErrInternal
+	}
+	Fail(w, r, ec, message, details)
+}
@@ -0,0 +1,243 @@
+package render
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+ETag-aware cache for rendered pages.
+
+Summary
+-------
+- PageCache.Middleware stores successful GET/HEAD responses (status 200)
+  in a cache.Cache and serves them from there until they expire or are
+  invalidated.
+- Every cached page gets an ETag (SHA-256 of the body unless the handler
+  set one); If-None-Match requests are answered with 304, also on the
+  first response. Invalidation changes the body and thus the ETag, so
+  browsers revalidating after an update get the new content.
+- Pages are keyed by path and query; htmx requests (HX-Request) are
+  cached separately since they receive only a fragment.
+- Invalidation by key, prefix or tag (see cache.Invalidator). Every page
+  is tagged with its path (PathTag); handlers add tags with TagPage.
+  Invalidate can be called from handlers, or invalidations are published
+  on an events.Bus after Subscribe.
+- Not cached: requests with an Authorization header, responses setting
+  cookies or marked Cache-Control private/no-store. Only use it for
+  pages that do not depend on the caller.
+- Responses carry X-Cache: HIT or MISS.
+- A Warmer pre-renders pages into the cache (see warm.go).
+
+Typical usage:
+
+	pages := render.NewPageCache(cache.NewMemory[render.CachedPage](), time.Minute)
+	srv.Handle("GET /notes/{id}", pages.Middleware("notes")(http.HandlerFunc(showPage)))
+
+	// after an update
+	pages.Invalidate(ctx, cache.Invalidation{Tags: []string{render.PathTag("/notes/" + id), "notes-list"}})
+*/
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/cache"
+	"github.com/bennof/gobfwebservice/ctxutil"
+	"github.com/bennof/gobfwebservice/events"
+	"github.com/bennof/gobfwebservice/middleware"
+)
+
+// CachedPage is a stored response.
+type CachedPage struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// PageCache caches rendered pages.
+type PageCache struct {
+	pages *cache.Invalidator[CachedPage]
+	ttl   time.Duration
+}
+
+// NewPageCache creates a page cache storing pages in c for ttl
+// (0 uses the cache's default TTL).
+func NewPageCache(c cache.Cache[CachedPage], ttl time.Duration) *PageCache {
+	inv, ok := c.(*cache.Invalidator[CachedPage])
+	if !ok {
+		inv = cache.NewInvalidator(c)
+	}
+	return &PageCache{pages: inv, ttl: ttl}
+}
+
+// PageKey returns the cache key of a page path (including a query, if any).
+func PageKey(path string) string {
+	return "page:" + path
+}
+
+// PathTag returns the tag every cached variant of path carries.
+func PathTag(path string) string {
+	return "path:" + path
+}
+
+// pageTagsKey collects tags added by the handler.
+var pageTagsKey = ctxutil.NewKey[*[]string]("page-tags")
+
+// TagPage adds tags to the page being rendered for r. It does nothing
+// outside PageCache.Middleware.
+func TagPage(r *http.Request, tags ...string) {
+	if p, ok := pageTagsKey.Get(r.Context()); ok {
+		*p = append(*p, tags...)
+	}
+}
+
+// Stats returns the statistics of the underlying cache.
+func (pc *PageCache) Stats() cache.Stats {
+	return pc.pages.Stats()
+}
+
+// Invalidate removes the selected pages.
+func (pc *PageCache) Invalidate(ctx context.Context, inv cache.Invalidation) (int, error) {
+	return pc.pages.Invalidate(ctx, inv)
+}
+
+// InvalidatePaths removes all cached variants of the given paths.
+func (pc *PageCache) InvalidatePaths(ctx context.Context, paths ...string) error {
+	tags := make([]string, len(paths))
+	for i, p := range paths {
+		tags[i] = PathTag(p)
+	}
+	return pc.pages.InvalidateTags(ctx, tags...)
+}
+
+// Subscribe applies invalidations published on bus (topic cache.Invalidated).
+func (pc *PageCache) Subscribe(bus *events.Bus) {
+	pc.pages.Subscribe(bus)
+}
+
+// Middleware returns a middleware caching pages with the given tags.
+func (pc *PageCache) Middleware(tags ...string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := PageKey(r.URL.RequestURI())
+			if r.Header.Get("HX-Request") == "true" {
+				key += "#hx"
+			}
+
+			// Warming requests (see warm.go) re-render the page
+			if page, ok, err := pc.pages.Get(r.Context(), key); err == nil && ok && !warmingKey.Value(r.Context()) {
+				w.Header().Set("X-Cache", "HIT")
+				servePage(w, r, page)
+				return
+			}
+
+			added := append([]string{PathTag(r.URL.Path)}, tags...)
+			buf := &pageBuffer{header: http.Header{}}
+			next.ServeHTTP(buf, r.WithContext(pageTagsKey.Set(r.Context(), &added)))
+
+			page := CachedPage{Header: buf.header, Body: buf.body.Bytes()}
+			if buf.status != http.StatusOK || !cacheable(buf.header) {
+				copyHeader(w.Header(), buf.header)
+				w.WriteHeader(buf.status)
+				w.Write(page.Body)
+				return
+			}
+
+			if page.Header.Get("ETag") == "" {
+				sum := sha256.Sum256(page.Body)
+				page.Header.Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+			}
+			page.Header.Del("Date")
+			if err := pc.pages.SetTagged(r.Context(), key, page, pc.ttl, added...); err != nil {
+				log.Printf("page cache: %v", err)
+			}
+
+			w.Header().Set("X-Cache", "MISS")
+			servePage(w, r, page)
+		})
+	}
+}
+
+// servePage writes a cached page, or 304 if the client has it.
+func servePage(w http.ResponseWriter, r *http.Request, page CachedPage) {
+	h := w.Header()
+	copyHeader(h, page.Header)
+
+	etag := page.Header.Get("ETag")
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatch(inm, etag) {
+		h.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(page.Body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(page.Body)
+	}
+}
+
+// etagMatch reports whether an If-None-Match value matches etag
+// (weak comparison).
+func etagMatch(inm, etag string) bool {
+	for _, t := range strings.Split(inm, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheable reports whether a response may be shared between clients.
+func cacheable(h http.Header) bool {
+	if h.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(h.Get("Cache-Control"))
+	return !strings.Contains(cc, "private") && !strings.Contains(cc, "no-store")
+}
+
+// copyHeader adds all values of src to dst.
+func copyHeader(dst, src http.Header) {
+	for k, vs := range src {
+		dst[k] = append([]string(nil), vs...)
+	}
+}
+
+// pageBuffer buffers a complete response.
+type pageBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the buffered header.
+func (b *pageBuffer) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the first status code.
+func (b *pageBuffer) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+// Write buffers the body.
+func (b *pageBuffer) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
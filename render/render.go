@@ -0,0 +1,97 @@
+package render
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package render provides typed response helpers for JSON, XML and plain text.
+
+Summary
+-------
+- Sets Content-Type and Content-Length headers consistently; HEAD
+  requests get the same headers without a body.
+- Marshals the complete body before writing, so encoding errors can still
+  be turned into a proper 500 response via the server error helpers.
+- Pretty-prints JSON and XML while server debug mode is enabled
+  for the request (see server.SetDebug and server.ErrorRenderer),
+  compact output otherwise.
+
+Typical usage:
+
+	render.JSON(w, r, http.StatusOK, note)
+	render.Text(w, r, http.StatusOK, "pong")
+*/
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// JSON writes v as a JSON response with the given status code.
+func JSON(w http.ResponseWriter, r *http.Request, code int, v any) {
+	var (
+		b   []byte
+		err error
+	)
+	if server.ErrorRendererFor(r).Debug() {
+		b, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		encodingError(w, r, "json", err)
+		return
+	}
+
+	write(w, r, code, "application/json; charset=utf-8", append(b, '\n'))
+}
+
+// XML writes v as an XML response (including the XML header) with the
+// given status code.
+func XML(w http.ResponseWriter, r *http.Request, code int, v any) {
+	var (
+		b   []byte
+		err error
+	)
+	if server.ErrorRendererFor(r).Debug() {
+		b, err = xml.MarshalIndent(v, "", "  ")
+	} else {
+		b, err = xml.Marshal(v)
+	}
+	if err != nil {
+		encodingError(w, r, "xml", err)
+		return
+	}
+
+	write(w, r, code, "application/xml; charset=utf-8", append([]byte(xml.Header), b...))
+}
+
+// Text writes s as a plain text response with the given status code.
+func Text(w http.ResponseWriter, r *http.Request, code int, s string) {
+	write(w, r, code, "text/plain; charset=utf-8", []byte(s))
+}
+
+/* ---------- helpers ---------- */
+
+// write sends the headers, status code and body. HEAD requests get the
+// same headers (including Content-Length) without the body.
+func write(w http.ResponseWriter, r *http.Request, code int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+	if server.SkipBody(r) {
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// encodingError logs a marshalling failure and renders a 500 response.
+func encodingError(w http.ResponseWriter, r *http.Request, format string, err error) {
+	log.Printf("render: %s encoding failed for %s %s: %v", format, r.Method, r.URL.Path, err)
+	server.InternalServerError(w, r)
+}
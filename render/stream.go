@@ -0,0 +1,180 @@
+package render
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Streaming responses.
+
+Summary
+-------
+- FlushWriter is an io.Writer that flushes the response after every
+  write (or every N bytes), so clients see data as it is produced.
+- NDJSON streams one JSON document per line (application/x-ndjson).
+- CSV streams records (text/csv), optionally as a download.
+- Flushing uses http.ResponseController, which reaches the underlying
+  connection through middleware wrappers implementing Unwrap (logging,
+  recorder), so streaming works behind the standard middleware stack.
+- Unlike JSON/XML, the body is not buffered: once the first chunk is
+  written, errors can no longer change the status code. Stop streaming
+  and return; the client sees a truncated response.
+
+Typical usage:
+
+	s := render.NewNDJSON(w, http.StatusOK)
+	for rows.Next() {
+	    if err := s.Encode(row); err != nil {
+	        return // client gone
+	    }
+	}
+
+Note: the server WriteTimeout limits the total duration of a response;
+long-running streams should call ExtendDeadline periodically.
+*/
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+)
+
+/* ---------- flush writer ---------- */
+
+// FlushWriter flushes the response after writes.
+type FlushWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	every   int // flush after this many bytes; <= 0 flushes after every write
+	pending int
+}
+
+// NewFlushWriter wraps w. every > 0 flushes only after that many bytes
+// were written since the last flush (useful for many small writes).
+func NewFlushWriter(w http.ResponseWriter, every ...int) *FlushWriter {
+	f := &FlushWriter{w: w, rc: http.NewResponseController(w)}
+	if len(every) > 0 {
+		f.every = every[0]
+	}
+	return f
+}
+
+// Write writes p and flushes according to the flush threshold.
+func (f *FlushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	f.pending += n
+	if f.pending >= f.every {
+		return n, f.Flush()
+	}
+	return n, nil
+}
+
+// Flush sends buffered data to the client. Writers that cannot flush
+// are ignored: data is then sent when the handler returns.
+func (f *FlushWriter) Flush() error {
+	f.pending = 0
+	if err := f.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// ExtendDeadline moves the write deadline d into the future, overriding
+// the server WriteTimeout for this response.
+func (f *FlushWriter) ExtendDeadline(d time.Duration) error {
+	return f.rc.SetWriteDeadline(time.Now().Add(d))
+}
+
+/* ---------- NDJSON ---------- */
+
+// NDJSONStream writes newline-delimited JSON.
+type NDJSONStream struct {
+	*FlushWriter
+	enc *json.Encoder
+}
+
+// NewNDJSON sends the headers and status code and returns a stream
+// writing one JSON document per line, flushed after each document.
+func NewNDJSON(w http.ResponseWriter, code int) *NDJSONStream {
+	startStream(w, code, "application/x-ndjson")
+
+	f := NewFlushWriter(w)
+	return &NDJSONStream{FlushWriter: f, enc: json.NewEncoder(f)}
+}
+
+// Encode writes v as a single line and flushes it.
+func (s *NDJSONStream) Encode(v any) error {
+	return s.enc.Encode(v)
+}
+
+/* ---------- CSV ---------- */
+
+// CSVStream writes CSV records.
+type CSVStream struct {
+	*FlushWriter
+	csv   *csv.Writer
+	every int
+	rows  int
+}
+
+// NewCSV sends the headers and status code and returns a CSV stream.
+// A non-empty filename marks the response as a download. Records are
+// flushed to the client every flushRows rows (default 100).
+func NewCSV(w http.ResponseWriter, code int, filename string, flushRows ...int) *CSVStream {
+	if filename != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	startStream(w, code, "text/csv; charset=utf-8")
+
+	f := NewFlushWriter(w)
+	s := &CSVStream{FlushWriter: f, csv: csv.NewWriter(f), every: 100}
+	if len(flushRows) > 0 && flushRows[0] > 0 {
+		s.every = flushRows[0]
+	}
+	return s
+}
+
+// Write writes a single record.
+func (s *CSVStream) Write(record []string) error {
+	if err := s.csv.Write(record); err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%s.every == 0 {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush sends all buffered records to the client.
+func (s *CSVStream) Flush() error {
+	s.csv.Flush()
+	if err := s.csv.Error(); err != nil {
+		return err
+	}
+	return s.FlushWriter.Flush()
+}
+
+// Close flushes the remaining records. Call it when all records are written.
+func (s *CSVStream) Close() error {
+	return s.Flush()
+}
+
+/* ---------- helpers ---------- */
+
+// startStream sends the headers of a streamed response.
+func startStream(w http.ResponseWriter, code int, contentType string) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("X-Accel-Buffering", "no") // disable nginx response buffering
+	h.Del("Content-Length")
+	w.WriteHeader(code)
+}
@@ -0,0 +1,225 @@
+package render
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Pre-rendering pages into the page cache.
+
+Summary
+-------
+- A Warmer requests a configured list of pages through the
+  application's handler, so their PageCache middleware renders and
+  stores them before the first visitor after a deploy (or an expiry)
+  arrives.
+- Pages are listed in the config and/or taken from a sitemap served by
+  the application itself (<loc> entries; only path and query are used).
+- Warming requests bypass the cache lookup and re-render the page, so a
+  scheduled Warm refreshes pages before they expire instead of only
+  filling gaps.
+- Run Warm on startup with server.OnWarmup (before /readyz passes) and
+  periodically with lifecycle.Every. Failed pages are reported in the
+  result; they never fail the start.
+
+Example config:
+
+	"warm": { "paths": ["/", "/notes"], "sitemap": "/sitemap.xml", "interval": 240 }
+
+Typical usage:
+
+	warmer := render.NewWarmer(cfg.Warm, srv.Mux())
+	srv.OnWarmup(func(ctx context.Context) error { warmer.Warm(ctx); return nil })
+	m.Add("warm", lifecycle.Every(warmer.Interval(), func(ctx context.Context) error {
+		_, err := warmer.Warm(ctx)
+		return err
+	}))
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+)
+
+// WarmConfig defines the pages to pre-render.
+// It is JSON-serializable and intended to be part of a global app config.
+type WarmConfig struct {
+	Paths    []string `json:"paths"`    // Pages to pre-render, e.g. "/" or "/notes?page=2"
+	Sitemap  string   `json:"sitemap"`  // Path of a sitemap served by the application; its pages are pre-rendered too
+	Host     string   `json:"host"`     // Host header of the warming requests (default "localhost")
+	Interval int      `json:"interval"` // Seconds between refreshes; 0 warms on startup only
+}
+
+// DefaultWarmConfig returns a configuration without pages (disabled).
+func DefaultWarmConfig() WarmConfig {
+	return WarmConfig{
+		Paths:    []string{},
+		Sitemap:  "",
+		Host:     "localhost",
+		Interval: 0,
+	}
+}
+
+// WarmResult reports a Warm run.
+type WarmResult struct {
+	Pages    int           `json:"pages"`    // pages requested
+	Failed   []string      `json:"failed"`   // pages not answered with 200
+	Duration time.Duration `json:"duration"` // duration of the run
+}
+
+// Warmer pre-renders pages through a handler.
+type Warmer struct {
+	cfg     WarmConfig
+	handler http.Handler
+}
+
+// warmingKey marks warming requests, which skip the cache lookup.
+var warmingKey = ctxutil.NewKey[bool]("page-warming")
+
+// NewWarmer creates a warmer requesting pages from h, usually the
+// server's mux, so each page passes its route's middleware.
+func NewWarmer(cfg WarmConfig, h http.Handler) *Warmer {
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	return &Warmer{cfg: cfg, handler: h}
+}
+
+// Enabled reports whether pages or a sitemap are configured.
+func (wm *Warmer) Enabled() bool {
+	return len(wm.cfg.Paths) > 0 || wm.cfg.Sitemap != ""
+}
+
+// Interval returns the refresh interval (0: startup only).
+func (wm *Warmer) Interval() time.Duration {
+	return time.Duration(wm.cfg.Interval) * time.Second
+}
+
+// Warm requests all configured pages once. The error reports a sitemap
+// that could not be read or pages that failed; all pages are tried.
+func (wm *Warmer) Warm(ctx context.Context) (WarmResult, error) {
+	start := time.Now()
+	paths := append([]string(nil), wm.cfg.Paths...)
+
+	var errs []error
+	if wm.cfg.Sitemap != "" {
+		more, err := wm.sitemap(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sitemap %s: %w", wm.cfg.Sitemap, err))
+		}
+		paths = append(paths, more...)
+	}
+
+	res := WarmResult{Failed: []string{}}
+	seen := map[string]bool{}
+	for _, p := range paths {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if ctx.Err() != nil {
+			break
+		}
+
+		res.Pages++
+		if status, _ := wm.get(ctx, p, true); status != http.StatusOK {
+			res.Failed = append(res.Failed, p)
+		}
+	}
+	res.Duration = time.Since(start)
+
+	log.Printf("Page warmup: %d pages in %s, %d failed", res.Pages, res.Duration.Round(time.Millisecond), len(res.Failed))
+	if len(res.Failed) > 0 {
+		errs = append(errs, fmt.Errorf("pages failed: %v", res.Failed))
+	}
+	return res, errors.Join(errs...)
+}
+
+// sitemap returns the paths listed in the configured sitemap.
+func (wm *Warmer) sitemap(ctx context.Context) ([]string, error) {
+	status, body := wm.get(ctx, wm.cfg.Sitemap, false)
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("status %d", status)
+	}
+
+	var set struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, u := range set.URLs {
+		loc, err := url.Parse(u.Loc)
+		if err != nil || loc.Path == "" {
+			continue
+		}
+		paths = append(paths, loc.RequestURI())
+	}
+	return paths, nil
+}
+
+// get requests path through the handler and returns the status and,
+// unless warming the page cache, the body.
+func (wm *Warmer) get(ctx context.Context, path string, warming bool) (int, []byte) {
+	if warming {
+		ctx = warmingKey.Set(ctx, true)
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, nil
+	}
+	r.Host = wm.cfg.Host
+	r.RemoteAddr = "127.0.0.1:0"
+	r.RequestURI = path
+	r.Header.Set("User-Agent", "page-warmer")
+
+	w := &warmWriter{header: http.Header{}, keep: !warming}
+	wm.handler.ServeHTTP(w, r)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.status, w.body.Bytes()
+}
+
+// warmWriter records the status and optionally the body.
+type warmWriter struct {
+	header http.Header
+	status int
+	keep   bool
+	body   bytes.Buffer
+}
+
+// Header returns the response header.
+func (w *warmWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the first status code.
+func (w *warmWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write records or discards the body.
+func (w *warmWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.keep {
+		return len(p), nil
+	}
+	return w.body.Write(p)
+}
@@ -0,0 +1,247 @@
+package scope
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package scope provides a request-scoped dependency container.
+
+Summary
+-------
+- Middleware creates a Scope per request and closes it after the
+  handler returned.
+- Provide registers a lazily constructed service by type: the
+  constructor runs on the first Resolve of that type within the request,
+  and its result (or error) is reused for the rest of the request.
+- ProvideCleanup additionally registers a cleanup function (close a DB
+  transaction, release a connection); cleanups run in reverse order of
+  construction when the scope is closed. Services never resolved are
+  never constructed and need no cleanup.
+- Resolve returns the service of type T without type assertions.
+- Later Provide calls for the same type replace earlier ones, so inner
+  middleware can override defaults (e.g. a tenant-specific repository).
+
+Typical usage:
+
+	api := middleware.Chain(scope.Middleware, tenantServices)
+	srv.Handle("GET /api/notes", api(notesHandler))
+
+	func tenantServices(next http.Handler) http.Handler {
+	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	        scope.ProvideCleanup(r.Context(), func(ctx context.Context) (*sql.Conn, func(), error) {
+	            conn, err := pools.For(tenant(r)).Conn(ctx)
+	            if err != nil {
+	                return nil, nil, err
+	            }
+	            return conn, func() { conn.Close() }, nil
+	        })
+	        next.ServeHTTP(w, r)
+	    })
+	}
+
+	// in a handler
+	conn, err := scope.Resolve[*sql.Conn](r.Context())
+
+Note: services are cleaned up when the handler returns; goroutines
+outliving the request must not keep using them.
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+)
+
+// Errors returned by Resolve.
+var (
+	ErrNoScope     = errors.New("scope: no scope in context (missing scope.Middleware)")
+	ErrNotProvided = errors.New("scope: service not provided")
+	ErrClosed      = errors.New("scope: scope closed")
+)
+
+// Constructor creates a service. ctx is the context of the first Resolve.
+type Constructor[T any] func(ctx context.Context) (T, error)
+
+// CleanupConstructor creates a service and the function releasing it.
+type CleanupConstructor[T any] func(ctx context.Context) (T, func(), error)
+
+// scopeKey is the context key of the request scope.
+var scopeKey = ctxutil.NewKey[*Scope]("scope")
+
+// Scope holds the services of a single request.
+type Scope struct {
+	mu       sync.Mutex
+	entries  map[reflect.Type]*entry
+	cleanups []func()
+	closed   bool
+}
+
+// entry is a provided service.
+type entry struct {
+	once  sync.Once
+	build func(ctx context.Context) (any, func(), error)
+	value any
+	err   error
+}
+
+// New creates an empty scope. Most code uses Middleware instead.
+func New() *Scope {
+	return &Scope{entries: map[reflect.Type]*entry{}}
+}
+
+// Middleware creates a scope for every request and closes it after the
+// next handler returned. Nested use reuses the outer scope.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := scopeKey.Get(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s := New()
+		defer s.Close()
+		next.ServeHTTP(w, r.WithContext(With(r.Context(), s)))
+	})
+}
+
+// With returns a copy of ctx carrying s (for use outside HTTP handlers,
+// e.g. background jobs). The caller must close s.
+func With(ctx context.Context, s *Scope) context.Context {
+	return scopeKey.Set(ctx, s)
+}
+
+// From returns the scope stored in ctx.
+func From(ctx context.Context) (*Scope, bool) {
+	return scopeKey.Get(ctx)
+}
+
+// Provide registers a lazily constructed service of type T in the scope
+// of ctx. It panics if ctx carries no scope: this is a wiring error.
+func Provide[T any](ctx context.Context, fn Constructor[T]) {
+	ProvideCleanup(ctx, func(ctx context.Context) (T, func(), error) {
+		v, err := fn(ctx)
+		return v, nil, err
+	})
+}
+
+// ProvideCleanup registers a lazily constructed service of type T whose
+// cleanup function (may be nil) runs when the scope is closed. It panics
+// if ctx carries no scope.
+func ProvideCleanup[T any](ctx context.Context, fn CleanupConstructor[T]) {
+	s, ok := From(ctx)
+	if !ok {
+		panic(ErrNoScope)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[reflect.TypeFor[T]()] = &entry{
+		build: func(ctx context.Context) (any, func(), error) {
+			return fn(ctx)
+		},
+	}
+}
+
+// Resolve returns the service of type T from the scope of ctx,
+// constructing it on first use.
+func Resolve[T any](ctx context.Context) (T, error) {
+	var zero T
+
+	s, ok := From(ctx)
+	if !ok {
+		return zero, ErrNoScope
+	}
+
+	t := reflect.TypeFor[T]()
+	s.mu.Lock()
+	e, ok := s.entries[t]
+	closed := s.closed
+	s.mu.Unlock()
+
+	if closed {
+		return zero, ErrClosed
+	}
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrNotProvided, t)
+	}
+
+	e.once.Do(func() { s.construct(ctx, e) })
+	if e.err != nil {
+		return zero, e.err
+	}
+	return e.value.(T), nil
+}
+
+// MustResolve is like Resolve but panics on error. Use it only where a
+// middleware guarantees the service.
+func MustResolve[T any](ctx context.Context) T {
+	v, err := Resolve[T](ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Close runs the cleanup functions of all constructed services in
+// reverse order. Panics in cleanups are recovered and logged. Close is
+// idempotent.
+func (s *Scope) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		runCleanup(cleanups[i])
+	}
+}
+
+// construct builds the service of e and records its cleanup.
+func (s *Scope) construct(ctx context.Context, e *entry) {
+	v, cleanup, err := e.build(ctx)
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.value = v
+
+	if cleanup == nil {
+		return
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	if !closed {
+		s.cleanups = append(s.cleanups, cleanup)
+	}
+	s.mu.Unlock()
+
+	// Constructed during or after Close: release immediately
+	if closed {
+		runCleanup(cleanup)
+		e.value, e.err = nil, ErrClosed
+	}
+}
+
+// runCleanup runs fn and recovers panics.
+func runCleanup(fn func()) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("scope: cleanup panicked: %v\n%s", rec, debug.Stack())
+		}
+	}()
+	fn()
+}
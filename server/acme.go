@@ -0,0 +1,624 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Automatic certificates via ACME (Let's Encrypt).
+
+Summary
+-------
+- With ServerConfig.ACME.Enabled the server obtains a certificate for
+  ACME.Domains from an ACME CA (RFC 8555, Let's Encrypt by default) and
+  renews it before it expires; no third-party client is needed.
+- Validation uses the HTTP-01 challenge. Challenge responses are served
+  under /.well-known/acme-challenge/ on the server's mux and, unless
+  ACME.HTTPPort is -1, on a plain HTTP listener (default port 80) that
+  redirects every other request to HTTPS.
+- The account key, certificate and private key are stored in
+  ACME.CacheDir, so restarts reuse them instead of hitting CA limits.
+- The certificate is obtained in the background after the listeners
+  started; TLS handshakes fail until it is available. Failures are
+  logged and retried hourly; certificates are renewed RenewDays before
+  they expire.
+- Use ACMEStaging while testing to avoid the production rate limits.
+
+Example config:
+
+	"server": {
+	  "port": 443,
+	  "acme": { "enabled": true, "domains": ["example.com", "www.example.com"],
+	            "email": "ops@example.com", "cache_dir": "/var/lib/app/acme" }
+	}
+*/
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ACME directory URLs of Let's Encrypt.
+const (
+	ACMEProduction = "https://acme-v02.api.letsencrypt.org/directory"
+	ACMEStaging    = "https://acme-staging-v02.api.letsencrypt.org/directory"
+)
+
+// ACMEConfig configures automatic certificates.
+type ACMEConfig struct {
+	Enabled   bool     `json:"enabled"`
+	Domains   []string `json:"domains"`              // names on the certificate; the first is the subject
+	Email     string   `json:"email,omitempty"`      // contact for expiry notices
+	CacheDir  string   `json:"cache_dir"`            // account key and certificates (default "acme")
+	Directory string   `json:"directory,omitempty"`  // ACME directory URL (default Let's Encrypt production)
+	HTTPPort  int      `json:"http_port,omitempty"`  // plain HTTP listener for challenges and redirects (default 80, -1 disables)
+	RenewDays int      `json:"renew_days,omitempty"` // renew this many days before expiry (default 30)
+}
+
+// acmeChallengePrefix is the HTTP-01 challenge path.
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// checkACMEConfig validates and completes the ACME settings of cfg.
+func checkACMEConfig(cfg *ServerConfig) error {
+	a := &cfg.ACME
+	if !a.Enabled {
+		return nil
+	}
+	if len(a.Domains) == 0 {
+		return errors.New("server: acme: no domains configured")
+	}
+	if cfg.CertFile != "" {
+		return errors.New("server: acme and cert_file are mutually exclusive")
+	}
+	if a.CacheDir == "" {
+		a.CacheDir = "acme"
+	}
+	if a.Directory == "" {
+		a.Directory = ACMEProduction
+	}
+	if a.HTTPPort == 0 {
+		a.HTTPPort = 80
+	}
+	if a.RenewDays <= 0 {
+		a.RenewDays = 30
+	}
+	return nil
+}
+
+// startACME starts the challenge listener and the renewal loop. It does
+// nothing if ACME is not enabled.
+func (s *Server) startACME() error {
+	if s.acme == nil {
+		return nil
+	}
+
+	if port := s.config.ACME.HTTPPort; port > 0 {
+		addr := net.JoinHostPort(s.config.Host, strconv.Itoa(port))
+		ln, err := s.listenTCP(addr)
+		if err != nil {
+			return fmt.Errorf("acme listener: %w", err)
+		}
+		ln = s.proxyListener(s.limitListener(ln, addr), addr)
+		s.acmeHTTP = &http.Server{
+			Addr:              addr,
+			Handler:           s.acme.redirectHandler(s.config.Port),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		log.Printf("ACME challenges and HTTPS redirects on %s", addr)
+		go func() {
+			if err := s.acmeHTTP.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("acme listener error: %v", err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.acmeStop = cancel
+	go s.acme.run(ctx)
+	return nil
+}
+
+// acmeManager obtains and renews the certificate.
+type acmeManager struct {
+	config ACMEConfig
+	client *http.Client
+
+	cert atomic.Pointer[tls.Certificate]
+
+	mu     sync.Mutex
+	tokens map[string]string // challenge token -> key authorization
+
+	// protocol state (used by the renewal goroutine only)
+	dir struct {
+		NewNonce   string `json:"newNonce"`
+		NewAccount string `json:"newAccount"`
+		NewOrder   string `json:"newOrder"`
+	}
+	key   *ecdsa.PrivateKey
+	kid   string
+	nonce string
+}
+
+// newACMEManager creates a manager and loads a cached certificate.
+func newACMEManager(cfg ACMEConfig) *acmeManager {
+	m := &acmeManager{
+		config: cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+		tokens: map[string]string{},
+	}
+	if cert, err := m.loadCert(); err == nil {
+		m.cert.Store(cert)
+	}
+	return m
+}
+
+// getCertificate implements tls.Config.GetCertificate.
+func (m *acmeManager) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if c := m.cert.Load(); c != nil {
+		return c, nil
+	}
+	return nil, errors.New("acme: certificate not yet available")
+}
+
+// challengeHandler serves HTTP-01 key authorizations.
+func (m *acmeManager) challengeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.URL.Path, acmeChallengePrefix)
+		m.mu.Lock()
+		ka, ok := m.tokens[token]
+		m.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, ka)
+	})
+}
+
+// withChallenges serves challenges and passes everything else to next
+// (for servers without a ServeMux).
+func (m *acmeManager) withChallenges(next http.Handler) http.Handler {
+	challenges := m.challengeHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, acmeChallengePrefix) {
+			challenges.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// redirectHandler serves challenges and redirects everything else to HTTPS.
+func (m *acmeManager) redirectHandler(httpsPort int) http.Handler {
+	challenges := m.challengeHandler()
+	redirect := RedirectHandler(httpsPort)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, acmeChallengePrefix) {
+			challenges.ServeHTTP(w, r)
+			return
+		}
+		redirect.ServeHTTP(w, r)
+	})
+}
+
+// run obtains the certificate if needed and renews it until ctx ends.
+func (m *acmeManager) run(ctx context.Context) {
+	for {
+		wait := time.Hour
+		if m.needsRenewal() {
+			if err := m.obtain(ctx); err != nil {
+				log.Printf("acme: %v", err)
+			} else {
+				log.Printf("acme: certificate for %s obtained", strings.Join(m.config.Domains, ", "))
+				wait = 12 * time.Hour
+			}
+		} else {
+			wait = 12 * time.Hour
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// needsRenewal reports whether there is no certificate or it expires soon.
+func (m *acmeManager) needsRenewal() bool {
+	c := m.cert.Load()
+	if c == nil || c.Leaf == nil {
+		return true
+	}
+	renew := time.Duration(m.config.RenewDays) * 24 * time.Hour
+	return time.Until(c.Leaf.NotAfter) < renew
+}
+
+/* ---------- protocol ---------- */
+
+// acmeProblem is an ACME error document.
+type acmeProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+// acmeOrder is an order resource.
+type acmeOrder struct {
+	Status         string   `json:"status"`
+	Authorizations []string `json:"authorizations"`
+	Finalize       string   `json:"finalize"`
+	Certificate    string   `json:"certificate"`
+}
+
+// acmeAuthz is an authorization resource.
+type acmeAuthz struct {
+	Status     string `json:"status"`
+	Identifier struct {
+		Value string `json:"value"`
+	} `json:"identifier"`
+	Challenges []struct {
+		Type  string `json:"type"`
+		URL   string `json:"url"`
+		Token string `json:"token"`
+	} `json:"challenges"`
+}
+
+// obtain runs a complete ACME order and stores the new certificate.
+func (m *acmeManager) obtain(ctx context.Context) error {
+	if err := m.account(ctx); err != nil {
+		return fmt.Errorf("account: %w", err)
+	}
+
+	ids := make([]map[string]string, len(m.config.Domains))
+	for i, d := range m.config.Domains {
+		ids[i] = map[string]string{"type": "dns", "value": d}
+	}
+	var order acmeOrder
+	resp, err := m.post(ctx, m.dir.NewOrder, map[string]any{"identifiers": ids}, &order)
+	if err != nil {
+		return fmt.Errorf("new order: %w", err)
+	}
+	orderURL := resp.Header.Get("Location")
+
+	for _, authzURL := range order.Authorizations {
+		if err := m.authorize(ctx, authzURL); err != nil {
+			return err
+		}
+	}
+
+	// Finalize with a fresh key
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: m.config.Domains[0]},
+		DNSNames: m.config.Domains,
+	}, certKey)
+	if err != nil {
+		return err
+	}
+	if _, err := m.post(ctx, order.Finalize, map[string]string{"csr": b64(csr)}, &order); err != nil {
+		return fmt.Errorf("finalize: %w", err)
+	}
+	if err := m.poll(ctx, orderURL, &order, func() (bool, error) {
+		switch order.Status {
+		case "valid":
+			return true, nil
+		case "invalid":
+			return false, errors.New("order became invalid")
+		}
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	resp, err = m.post(ctx, order.Certificate, nil, nil)
+	if err != nil {
+		return fmt.Errorf("download certificate: %w", err)
+	}
+	chain, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(certKey)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(chain, keyPEM)
+	if err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+
+	base := filepath.Join(m.config.CacheDir, m.config.Domains[0])
+	if err := os.WriteFile(base+".key", keyPEM, 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".crt", chain, 0644); err != nil {
+		return err
+	}
+	m.cert.Store(&cert)
+	return nil
+}
+
+// authorize completes the HTTP-01 challenge of one authorization.
+func (m *acmeManager) authorize(ctx context.Context, url string) error {
+	var authz acmeAuthz
+	if _, err := m.post(ctx, url, nil, &authz); err != nil {
+		return fmt.Errorf("authorization: %w", err)
+	}
+	if authz.Status == "valid" {
+		return nil
+	}
+
+	idx := -1
+	for i, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("authorization %s: no http-01 challenge offered", authz.Identifier.Value)
+	}
+	ch := authz.Challenges[idx]
+
+	m.mu.Lock()
+	m.tokens[ch.Token] = ch.Token + "." + m.thumbprint()
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.tokens, ch.Token)
+		m.mu.Unlock()
+	}()
+
+	if _, err := m.post(ctx, ch.URL, struct{}{}, nil); err != nil {
+		return fmt.Errorf("challenge %s: %w", authz.Identifier.Value, err)
+	}
+	return m.poll(ctx, url, &authz, func() (bool, error) {
+		switch authz.Status {
+		case "valid":
+			return true, nil
+		case "invalid":
+			return false, fmt.Errorf("challenge for %s failed", authz.Identifier.Value)
+		}
+		return false, nil
+	})
+}
+
+// poll fetches url into v until done reports true (at most two minutes).
+func (m *acmeManager) poll(ctx context.Context, url string, v any, done func() (bool, error)) error {
+	for range 60 {
+		if ok, err := done(); ok || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+		if _, err := m.post(ctx, url, nil, v); err != nil {
+			return err
+		}
+	}
+	return errors.New("timed out")
+}
+
+// account loads or creates the account key and registers the account.
+func (m *acmeManager) account(ctx context.Context) error {
+	if m.kid != "" {
+		return nil
+	}
+	if err := os.MkdirAll(m.config.CacheDir, 0700); err != nil {
+		return err
+	}
+
+	keyPath := filepath.Join(m.config.CacheDir, "account.key")
+	if b, err := os.ReadFile(keyPath); err == nil {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return errors.New("invalid account key")
+		}
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		ek, ok := k.(*ecdsa.PrivateKey)
+		if !ok {
+			return errors.New("account key is not ECDSA")
+		}
+		m.key = ek
+	} else {
+		ek, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(ek)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+			return err
+		}
+		m.key = ek
+	}
+
+	resp, err := m.get(ctx, m.config.Directory)
+	if err != nil {
+		return err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&m.dir)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("directory: %w", err)
+	}
+
+	req := map[string]any{"termsOfServiceAgreed": true}
+	if m.config.Email != "" {
+		req["contact"] = []string{"mailto:" + m.config.Email}
+	}
+	resp, err = m.post(ctx, m.dir.NewAccount, req, nil)
+	if err != nil {
+		return err
+	}
+	m.kid = resp.Header.Get("Location")
+	return nil
+}
+
+// get performs a plain GET request.
+func (m *acmeManager) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.client.Do(req)
+}
+
+// post sends a JWS-signed request; a nil payload is a POST-as-GET.
+// If out is nil the response is returned with an open body (closed for
+// JSON responses decoded into out). A badNonce error is retried once.
+func (m *acmeManager) post(ctx context.Context, url string, payload, out any) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := m.postOnce(ctx, url, payload)
+		if err != nil {
+			return nil, err
+		}
+		if n := resp.Header.Get("Replay-Nonce"); n != "" {
+			m.nonce = n
+		}
+
+		if resp.StatusCode >= 400 {
+			var p acmeProblem
+			json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&p)
+			resp.Body.Close()
+			if p.Type == "urn:ietf:params:acme:error:badNonce" && attempt == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %s (%s)", resp.Status, p.Detail, p.Type)
+		}
+
+		if out != nil {
+			defer resp.Body.Close()
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return nil, err
+			}
+		} else if payload != nil {
+			resp.Body.Close()
+		}
+		return resp, nil
+	}
+}
+
+// postOnce signs and sends a single request.
+func (m *acmeManager) postOnce(ctx context.Context, url string, payload any) (*http.Response, error) {
+	if m.nonce == "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, m.dir.NewNonce, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := m.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		m.nonce = resp.Header.Get("Replay-Nonce")
+	}
+
+	protected := map[string]any{"alg": "ES256", "nonce": m.nonce, "url": url}
+	if m.kid != "" {
+		protected["kid"] = m.kid
+	} else {
+		protected["jwk"] = m.jwk()
+	}
+	m.nonce = ""
+
+	ph, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+	body := ""
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = b64(b)
+	}
+
+	signingInput := b64(ph) + "." + body
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, m.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	jws, err := json.Marshal(map[string]string{"protected": b64(ph), "payload": body, "signature": b64(sig)})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jws))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/jose+json")
+	return m.client.Do(req)
+}
+
+// jwk returns the public account key as JWK (members in thumbprint order).
+func (m *acmeManager) jwk() map[string]string {
+	pub, _ := m.key.PublicKey.ECDH()
+	raw := pub.Bytes() // 0x04 || X || Y
+	return map[string]string{"crv": "P-256", "kty": "EC", "x": b64(raw[1:33]), "y": b64(raw[33:])}
+}
+
+// thumbprint returns the RFC 7638 JWK thumbprint of the account key.
+func (m *acmeManager) thumbprint() string {
+	j := m.jwk()
+	canon := fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, j["x"], j["y"])
+	sum := sha256.Sum256([]byte(canon))
+	return b64(sum[:])
+}
+
+// loadCert reads a cached certificate.
+func (m *acmeManager) loadCert() (*tls.Certificate, error) {
+	base := filepath.Join(m.config.CacheDir, m.config.Domains[0])
+	cert, err := tls.LoadX509KeyPair(base+".crt", base+".key")
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// b64 encodes b as unpadded base64url.
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
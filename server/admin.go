@@ -0,0 +1,138 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Admin listener for debug endpoints.
+
+Summary
+-------
+- With ServerConfig.Admin.Port set, the server opens a second listener
+  (default 127.0.0.1) for operators, separate from the public mux: no
+  middleware, no connection limits, nothing a public route could shadow.
+- Built in: net/http/pprof (/debug/pprof/), expvar (/debug/vars) and an
+  index of all admin routes (/). Applications add their own, e.g. build
+  info or the effective config, with HandleAdmin.
+- Admin.Token requires "Authorization: Bearer <token>" on every request;
+  set it whenever the listener is reachable from other hosts.
+- The listener starts with the server, is handed over by Upgrade and
+  shuts down with it.
+
+Example config:
+
+	"server": { "admin": { "port": 6060, "token": "..." } }
+
+Usage:
+
+	go tool pprof http://127.0.0.1:6060/debug/pprof/profile?seconds=10
+*/
+
+import (
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strings"
+)
+
+// AdminConfig defines the admin listener.
+type AdminConfig struct {
+	Port  int    `json:"port"`  // admin port; 0 disables the admin listener
+	Host  string `json:"host"`  // bind address (default 127.0.0.1)
+	Token string `json:"token"` // bearer token required on every request; empty allows all (masked in redacted config output)
+}
+
+// newAdmin creates the admin server if configured.
+func (s *Server) newAdmin() {
+	cfg := s.config.Admin
+	if cfg.Port <= 0 {
+		return
+	}
+	host := cfg.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	s.adminMux = http.NewServeMux()
+	s.adminServer = &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", host, cfg.Port),
+		Handler: s.adminAuth(s.adminMux),
+	}
+
+	s.HandleAdmin("GET /debug/pprof/", http.HandlerFunc(pprof.Index))
+	s.HandleAdmin("GET /debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+	s.HandleAdmin("GET /debug/pprof/profile", http.HandlerFunc(pprof.Profile))
+	s.HandleAdmin("GET /debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	s.HandleAdmin("POST /debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	s.HandleAdmin("GET /debug/pprof/trace", http.HandlerFunc(pprof.Trace))
+	s.HandleAdmin("GET /debug/vars", expvar.Handler())
+	s.adminMux.HandleFunc("GET /{$}", s.adminIndex)
+}
+
+// HandleAdmin registers a handler on the admin listener. It does nothing
+// if the admin listener is disabled, so applications can register their
+// debug endpoints unconditionally.
+func (s *Server) HandleAdmin(pattern string, handler http.Handler) {
+	if s.adminMux == nil {
+		return
+	}
+	s.adminMux.Handle(pattern, handler)
+
+	s.adminMu.Lock()
+	s.adminRoutes = append(s.adminRoutes, pattern)
+	s.adminMu.Unlock()
+}
+
+// adminIndex lists the admin routes.
+func (s *Server) adminIndex(w http.ResponseWriter, r *http.Request) {
+	s.adminMu.Lock()
+	routes := append([]string(nil), s.adminRoutes...)
+	s.adminMu.Unlock()
+	sort.Strings(routes)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, p := range routes {
+		fmt.Fprintln(w, p)
+	}
+}
+
+// adminAuth requires the configured bearer token.
+func (s *Server) adminAuth(next http.Handler) http.Handler {
+	token := s.config.Admin.Token
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// startAdmin starts the admin listener in the background.
+func (s *Server) startAdmin() error {
+	a := s.adminServer
+	if a == nil {
+		return nil
+	}
+	ln, err := s.listenTCP(a.Addr)
+	if err != nil {
+		return fmt.Errorf("admin listener: %w", err)
+	}
+	log.Printf("Admin listening on %s", a.Addr)
+	go func() {
+		if err := a.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("admin server error: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,109 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Base and connection contexts.
+
+Summary
+-------
+- SetBaseContext sets the context every request context derives from
+  (http.Server.BaseContext), e.g. one carrying the database pool or a
+  tracer, so handlers find application-scoped dependencies in
+  r.Context() instead of package globals.
+- AddConnContext registers hooks deriving a context per connection
+  (http.Server.ConnContext), e.g. with a connection ID or the remote
+  address. Hooks run in registration order, each receiving the result
+  of the previous one. They run before the TLS handshake, so the peer's
+  client certificate is not known yet; read it from r.TLS in a
+  handler or middleware instead.
+- Provide and Value store and look up dependencies by type on the base
+  context (ctxutil.TypeKey) without declaring a key.
+- Both apply to the HTTP server and a separate gRPC listener. Call them
+  before the server is started.
+- WithRequestID and RequestID carry the request ID (set by
+  middleware.RequestID), so error pages and problem details can show
+  it without depending on the middleware package.
+
+Typical usage:
+
+	server.Provide(srv, db) // *sql.DB
+
+	func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+		db, ok := server.Value[*sql.DB](r.Context())
+		...
+	}
+*/
+
+import (
+	"context"
+	"net"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
+)
+
+// SetBaseContext sets fn to create the base context of all requests
+// accepted on a listener. Values added by Provide are placed on top of
+// the returned context. It must not return nil.
+func (s *Server) SetBaseContext(fn func(ln net.Listener) context.Context) {
+	s.baseContext = fn
+}
+
+// AddConnContext registers fn to derive the context of a new
+// connection from ctx. It runs when the connection is accepted, before
+// a TLS handshake. It must not return nil.
+func (s *Server) AddConnContext(fn func(ctx context.Context, c net.Conn) context.Context) {
+	s.connContexts = append(s.connContexts, fn)
+}
+
+// Provide adds v to the base context of srv, to be looked up in handlers
+// with Value[T]. A later value of the same type replaces an earlier one.
+func Provide[T any](srv *Server, v T) {
+	srv.provided = append(srv.provided, func(ctx context.Context) context.Context {
+		return ctxutil.TypeKey[T]().Set(ctx, v)
+	})
+}
+
+// Value returns the dependency of type T provided to the server handling
+// the request of ctx (see Provide).
+func Value[T any](ctx context.Context) (T, bool) {
+	return ctxutil.TypeKey[T]().Get(ctx)
+}
+
+// serverKey stores the server handling a request (for the debug page).
+var serverKey = ctxutil.NewKey[*Server]("server")
+
+// baseCtx implements http.Server.BaseContext.
+func (s *Server) baseCtx(ln net.Listener) context.Context {
+	ctx := context.Background()
+	if s.baseContext != nil {
+		ctx = s.baseContext(ln)
+	}
+	ctx = serverKey.Set(ctx, s)
+	for _, fn := range s.provided {
+		ctx = fn(ctx)
+	}
+	return ctx
+}
+
+// connCtx implements http.Server.ConnContext.
+func (s *Server) connCtx(ctx context.Context, c net.Conn) context.Context {
+	for _, fn := range s.connContexts {
+		ctx = fn(ctx, c)
+	}
+	return ctx
+}
+
+// requestIDKey stores the ID of a request.
+var requestIDKey = ctxutil.NewKey[string]("request-id")
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return requestIDKey.Set(ctx, id)
+}
+
+// RequestID returns the request ID of ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	return requestIDKey.Value(ctx)
+}
@@ -0,0 +1,48 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Client disconnect detection.
+
+Summary
+-------
+- ClientGone reports whether the client closed the connection (or
+  cancelled the HTTP/2 stream) before the handler finished. The request
+  context is then cancelled with context.Canceled; server-side
+  deadlines (context.DeadlineExceeded) do not count.
+- Nobody reads the response of such a request, so RenderError,
+  RenderPanic and RespondError render nothing for it and RespondError
+  does not log the (usually misleading) error, e.g. a database query aborted
+  with "context canceled".
+- middleware.Logging marks these requests as "client closed" with the
+  non-standard status 499 (as known from nginx) if no response was sent,
+  and counts them (see middleware.ClientClosedRequests).
+
+Typical usage in long-running handlers:
+
+	rows, err := db.QueryContext(r.Context(), q)
+	if err != nil {
+	    if server.ClientGone(r) {
+	        return nil // nothing to report
+	    }
+	    return err
+	}
+*/
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is logged for requests aborted by the client
+// before a response was sent. It is never sent to clients.
+const StatusClientClosedRequest = 499
+
+// ClientGone reports whether the client of r went away.
+func ClientGone(r *http.Request) bool {
+	ctx := r.Context()
+	return ctx.Err() != nil && errors.Is(context.Cause(ctx), context.Canceled)
+}
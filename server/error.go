@@ -9,76 +9,263 @@ Package server provides reusable HTTP error handling helpers for web services.
 Summary
 -------
 - Centralizes rendering of common HTTP error responses (4xx / 5xx).
-- Supports an optional, shared HTML template for error pages.
+- An ErrorRenderer holds the error page settings: an optional HTML
+  template, selected and translated by locale (see errorlocale.go),
+  debug output and silent asset extensions. Servers can have their own
+  (Server.SetErrorRenderer); the package-level helpers (NotFound,
+  RenderError, SetErrorTemplate, ...) use the renderer of the server
+  handling the request, or the process-wide default.
+- Answers API clients (Accept: application/json, application/problem+json
+  or any +json type) with RFC 9457 problem details (see handler.go).
 - Falls back to plain status codes if no template is configured.
+- Error templates receive .Code, .Title, .Message, .Path, .Locale,
+  .RequestID (see middleware.RequestID) and .Time, plus the fields of
+  an optional SetData hook.
 - Suppresses HTML error pages for static asset requests
   (e.g. JS, CSS, images, fonts) to avoid polluting asset responses.
+  The extension list is configurable (SetSilentExtensions) and the
+  behavior can be switched off (SetSilentErrors), e.g. for APIs serving
+  .json paths.
+- Answers gRPC requests with a gRPC status (see grpc.go).
+- Renders nothing for requests whose client went away (see disconnect.go).
+- Passes 5xx responses and panics to an optional error reporter
+  (see errorreport.go).
+- Renders a diagnostic page with cause, stack, request and routing
+  details in debug mode (see errordebug.go).
 - Designed to be framework-agnostic and usable with net/http directly.
+
+Typical usage:
+
+	errs := server.NewErrorRenderer(tmpl, "error")
+	srv.SetErrorRenderer(errs)
+	...
+	errs.SetTemplate(reloaded, "error") // on template reload
 */
 
 import (
+	"context"
+	"fmt"
 	"html/template"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bennof/gobfwebservice/ctxutil"
 )
 
-var (
-	// errorTemplate holds the parsed template used to render error pages.
-	errorTemplate *template.Template
+// DefaultSilentExtensions are the file extensions whose requests get a
+// bare status code instead of an HTML error page.
+var DefaultSilentExtensions = []string{
+	".js", ".css", ".map", ".ico", ".png", ".svg", ".jpg", ".jpeg", ".webp",
+	".woff", ".woff2", ".ttf", ".eot", ".gif", ".pdf", ".json", ".xml",
+}
+
+/* ---------- renderer ---------- */
 
-	// errorTemplateName is the name of the template block to execute.
-	// If empty, errors are returned without rendering HTML.
-	errorTemplateName string = ""
-)
+// ErrorRenderer renders error responses. Configure it before serving;
+// only the template may be replaced while serving (SetTemplate).
+type ErrorRenderer struct {
+	page atomic.Pointer[errorPage] // swapped on template reload
+
+	// debug enables diagnostic output (panic values and stack traces).
+	// Never enable this in production.
+	debug bool
+
+	// silent enables bare status codes for static asset requests.
+	silent bool
 
-// SetErrorTemplate configures a shared HTML template for error pages.
-// If name is empty, HTML rendering is disabled and only status codes are sent.
+	// silentExtensions holds the lower-case extensions (with dot) of
+	// static asset requests.
+	silentExtensions map[string]struct{}
+
+	// locale determines the locale of a request (see errorlocale.go).
+	locale func(r *http.Request) string
+
+	// translator translates titles and messages (nil: none).
+	translator ErrorTranslator
+
+	// data adds fields to the template data (nil: none).
+	data ErrorDataFunc
+
+	// reporter receives 5xx responses (nil: none, see errorreport.go).
+	reporter ErrorReporter
+}
+
+// ErrorDataFunc returns additional fields for the error page of r,
+// e.g. a support contact or the trace ID of a tracing middleware.
+type ErrorDataFunc func(r *http.Request) map[string]any
+
+// errorPage is the template of HTML error pages.
+type errorPage struct {
+	tpl  *template.Template
+	name string // template block to execute; empty renders no HTML
+}
+
+// NewErrorRenderer creates a renderer executing the template name of tpl
+// for error pages. With an empty name only status codes are sent.
+func NewErrorRenderer(tpl *template.Template, name string) *ErrorRenderer {
+	e := &ErrorRenderer{
+		silent:           true,
+		silentExtensions: extensionSet(DefaultSilentExtensions),
+		locale:           AcceptLanguage,
+	}
+	e.SetTemplate(tpl, name)
+	return e
+}
+
+// defaultErrors is used for requests not served by a Server with its
+// own renderer.
+var defaultErrors = NewErrorRenderer(nil, "")
+
+// errorsKey stores the renderer of the server handling a request.
+var errorsKey = ctxutil.NewKey[*ErrorRenderer]("error-renderer")
+
+// DefaultErrorRenderer returns the process-wide renderer configured by
+// the package-level setters (SetErrorTemplate, SetDebug, ...).
+func DefaultErrorRenderer() *ErrorRenderer {
+	return defaultErrors
+}
+
+// ErrorRendererFor returns the renderer for r: the one of the server
+// handling it (see Server.SetErrorRenderer) or the default.
+func ErrorRendererFor(r *http.Request) *ErrorRenderer {
+	if e, ok := errorsKey.Get(r.Context()); ok {
+		return e
+	}
+	return defaultErrors
+}
+
+// SetErrorRenderer makes the server render its errors with e, also for
+// the package-level helpers called by its handlers and middleware. Call
+// it before the server is started.
+func (s *Server) SetErrorRenderer(e *ErrorRenderer) {
+	s.provided = append(s.provided, func(ctx context.Context) context.Context {
+		return errorsKey.Set(ctx, e)
+	})
+}
+
+// SetTemplate sets the HTML template for error pages. If name is empty,
+// HTML rendering is disabled and only status codes are sent. It is safe
+// to call while serving.
+func (e *ErrorRenderer) SetTemplate(tpl *template.Template, name string) {
+	if tpl == nil {
+		name = ""
+	}
+	e.page.Store(&errorPage{tpl: tpl, name: name})
+}
+
+// SetDebug enables or disables diagnostic error output for development.
+// When enabled, error pages show the cause, panic value, stack trace and
+// request details (see errordebug.go).
+func (e *ErrorRenderer) SetDebug(enabled bool) {
+	e.debug = enabled
+}
+
+// Debug reports whether diagnostic error output is enabled.
+func (e *ErrorRenderer) Debug() bool {
+	return e.debug
+}
+
+// SetData sets fn to add fields to the template data of error pages.
+// They do not replace the standard fields. nil removes the hook.
+func (e *ErrorRenderer) SetData(fn ErrorDataFunc) {
+	e.data = fn
+}
+
+// SetSilentErrors enables or disables bare status codes for static asset
+// requests (enabled by default). When disabled, every request gets the
+// regular error page.
+func (e *ErrorRenderer) SetSilentErrors(enabled bool) {
+	e.silent = enabled
+}
+
+// SetSilentExtensions replaces the extensions (e.g. ".js") of static
+// asset requests answered with a bare status code.
+func (e *ErrorRenderer) SetSilentExtensions(exts ...string) {
+	e.silentExtensions = extensionSet(exts)
+}
+
+/* ---------- package-level configuration ---------- */
+
+// SetErrorTemplate configures the HTML template for error pages of the
+// default renderer. If name is empty, HTML rendering is disabled and
+// only status codes are sent.
 func SetErrorTemplate(tpl *template.Template, name string) {
-	errorTemplate = tpl
-	errorTemplateName = name
+	defaultErrors.SetTemplate(tpl, name)
+}
+
+// SetDebug enables or disables diagnostic error output of the default
+// renderer (see ErrorRenderer.SetDebug).
+func SetDebug(enabled bool) {
+	defaultErrors.SetDebug(enabled)
+}
+
+// SetErrorData sets the template data hook of the default renderer (see
+// ErrorRenderer.SetData).
+func SetErrorData(fn ErrorDataFunc) {
+	defaultErrors.SetData(fn)
+}
+
+// SetSilentErrors enables or disables bare status codes for static asset
+// requests in the default renderer (see ErrorRenderer.SetSilentErrors).
+func SetSilentErrors(enabled bool) {
+	defaultErrors.SetSilentErrors(enabled)
+}
+
+// SetSilentExtensions replaces the silent asset extensions of the
+// default renderer (see ErrorRenderer.SetSilentExtensions).
+func SetSilentExtensions(exts ...string) {
+	defaultErrors.SetSilentExtensions(exts...)
+}
+
+// Debug reports whether diagnostic error output of the default renderer
+// is enabled.
+func Debug() bool {
+	return defaultErrors.Debug()
 }
 
 /* ---------- HTTP error handlers ---------- */
 
 // BadRequest renders a 400 Bad Request error.
-func BadRequest(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusBadRequest, "Bad Request", "The request could not be processed.")
+func (e *ErrorRenderer) BadRequest(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusBadRequest, "Bad Request", "The request could not be processed.")
 }
 
 // Unauthorized renders a 401 Unauthorized error.
-func Unauthorized(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusUnauthorized, "Unauthorized", "You must authenticate to access this resource.")
+func (e *ErrorRenderer) Unauthorized(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusUnauthorized, "Unauthorized", "You must authenticate to access this resource.")
 }
 
 // Forbidden renders a 403 Forbidden error.
-func Forbidden(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusForbidden, "Forbidden", "You do not have permission to access this resource.")
+func (e *ErrorRenderer) Forbidden(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusForbidden, "Forbidden", "You do not have permission to access this resource.")
 }
 
 // NotFound renders a 404 Not Found error.
-func NotFound(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusNotFound, "Not Found", "The requested page does not exist.")
+func (e *ErrorRenderer) NotFound(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusNotFound, "Not Found", "The requested page does not exist.")
 }
 
 // MethodNotAllowed renders a 405 Method Not Allowed error.
-func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusMethodNotAllowed, "Method Not Allowed", "The HTTP method used is not allowed for this resource.")
+func (e *ErrorRenderer) MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusMethodNotAllowed, "Method Not Allowed", "The HTTP method used is not allowed for this resource.")
 }
 
 // InternalServerError renders a 500 Internal Server Error.
-func InternalServerError(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusInternalServerError, "Internal Server Error", "An error occurred on the server.")
+func (e *ErrorRenderer) InternalServerError(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusInternalServerError, "Internal Server Error", "An error occurred on the server.")
 }
 
 // ServiceUnavailable renders a 503 Service Unavailable error.
-func ServiceUnavailable(w http.ResponseWriter, r *http.Request) {
-	RenderError(w, r, http.StatusServiceUnavailable, "Service Unavailable", "The server is currently unavailable. Please try again later.")
+func (e *ErrorRenderer) ServiceUnavailable(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(w, r, http.StatusServiceUnavailable, "Service Unavailable", "The server is currently unavailable. Please try again later.")
 }
 
 // TooManyRequests renders a 429 Too Many Requests error.
-func TooManyRequests(w http.ResponseWriter, r *http.Request) {
-	RenderError(
+func (e *ErrorRenderer) TooManyRequests(w http.ResponseWriter, r *http.Request) {
+	e.RenderError(
 		w,
 		r,
 		http.StatusTooManyRequests,
@@ -87,56 +274,188 @@ func TooManyRequests(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// BadRequest renders a 400 Bad Request error (see ErrorRendererFor).
+func BadRequest(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).BadRequest(w, r)
+}
+
+// Unauthorized renders a 401 Unauthorized error (see ErrorRendererFor).
+func Unauthorized(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).Unauthorized(w, r)
+}
+
+// Forbidden renders a 403 Forbidden error (see ErrorRendererFor).
+func Forbidden(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).Forbidden(w, r)
+}
+
+// NotFound renders a 404 Not Found error (see ErrorRendererFor).
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).NotFound(w, r)
+}
+
+// MethodNotAllowed renders a 405 Method Not Allowed error (see ErrorRendererFor).
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).MethodNotAllowed(w, r)
+}
+
+// InternalServerError renders a 500 Internal Server Error (see ErrorRendererFor).
+func InternalServerError(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).InternalServerError(w, r)
+}
+
+// ServiceUnavailable renders a 503 Service Unavailable error (see ErrorRendererFor).
+func ServiceUnavailable(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).ServiceUnavailable(w, r)
+}
+
+// TooManyRequests renders a 429 Too Many Requests error (see ErrorRendererFor).
+func TooManyRequests(w http.ResponseWriter, r *http.Request) {
+	ErrorRendererFor(r).TooManyRequests(w, r)
+}
+
 /* ---------- core rendering ---------- */
 
 // RenderError renders an HTTP error response with the given status code,
-// title, and message. Depending on configuration, this either renders an
-// HTML template or sends a plain status code.
+// title, and message with the renderer of r (see ErrorRendererFor).
 func RenderError(w http.ResponseWriter, r *http.Request, code int, title, message string) {
+	ErrorRendererFor(r).RenderError(w, r, code, title, message)
+}
+
+// RenderPanic renders the response for a recovered panic with the
+// renderer of r (see ErrorRenderer.RenderPanic).
+func RenderPanic(w http.ResponseWriter, r *http.Request, rec any, stack []byte) {
+	ErrorRendererFor(r).RenderPanic(w, r, rec, stack)
+}
+
+// RenderError renders an HTTP error response with the given status code,
+// title, and message. Clients accepting JSON get application/problem+json
+// (see WriteProblem); otherwise, depending on configuration, this either
+// renders an HTML template or sends a plain status code.
+func (e *ErrorRenderer) RenderError(w http.ResponseWriter, r *http.Request, code int, title, message string) {
+	if code >= http.StatusInternalServerError {
+		e.report(r, ErrorReport{Code: code, Message: message})
+	}
+	e.writeError(w, r, code, title, message, ErrorReport{})
+}
+
+// writeError writes the error response of RenderError. diag holds the
+// cause, panic and stack shown on the debug page (see errordebug.go).
+func (e *ErrorRenderer) writeError(w http.ResponseWriter, r *http.Request, code int, title, message string, diag ErrorReport) {
+	// Nobody reads the response of an aborted request
+	if ClientGone(r) {
+		return
+	}
+
+	// gRPC clients expect a gRPC status, not a page
+	if IsGRPC(r) {
+		WriteGRPCError(w, GRPCCodeFromHTTP(code), message)
+		return
+	}
+
+	// API clients get problem details instead of a page
+	if wantsProblem(r) {
+		WriteProblem(w, r, Problem{Title: title, Status: code, Detail: message})
+		return
+	}
+
 	// Suppress HTML error pages for static asset requests
-	if isSilentError(w, r, code) {
+	if e.isSilentError(w, r, code) {
+		return
+	}
+
+	// Diagnostic page instead of the template in debug mode
+	if e.debug {
+		e.writeDebugPage(w, r, code, title, message, diag)
 		return
 	}
 
 	// If no template is configured, return only the status code
-	if errorTemplateName == "" {
+	page := e.page.Load()
+	if page.name == "" {
 		w.Header().Del("Content-Type")
 		w.WriteHeader(code)
 		return
 	}
 
-	// Render the configured HTML error template
+	// Render the configured HTML error template in the request's locale
+	locale, name, title, message := e.localize(page, r, code, title, message)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+	w.Header().Add("Vary", "Accept-Language")
 	w.WriteHeader(code)
 
-	data := map[string]interface{}{
-		"Code":    code,
-		"Title":   title,
-		"Message": message,
-		"Path":    r.URL.Path,
+	data := map[string]interface{}{}
+	if e.data != nil {
+		for k, v := range e.data(r) {
+			data[k] = v
+		}
 	}
+	data["Code"] = code
+	data["Title"] = title
+	data["Message"] = message
+	data["Path"] = r.URL.Path
+	data["Locale"] = locale
+	data["RequestID"] = RequestID(r.Context())
+	data["Time"] = time.Now()
 
-	if err := errorTemplate.ExecuteTemplate(w, errorTemplateName, data); err != nil {
+	if err := page.tpl.ExecuteTemplate(w, name, data); err != nil {
 		// Fallback to a plain HTTP error if template rendering fails
 		http.Error(w, message, code)
 	}
 }
 
+// RenderPanic renders the response for a recovered panic: a generic 500
+// Internal Server Error, or in debug mode the diagnostic page with the
+// panic value and stack trace (the panic value only for API and gRPC
+// clients).
+func (e *ErrorRenderer) RenderPanic(w http.ResponseWriter, r *http.Request, rec any, stack []byte) {
+	if ClientGone(r) {
+		return
+	}
+	e.report(r, ErrorReport{Code: http.StatusInternalServerError, Panic: rec, Stack: stack})
+
+	msg := "An error occurred on the server."
+	if e.debug {
+		msg = fmt.Sprintf("panic: %v", rec)
+	}
+	e.writeError(w, r, http.StatusInternalServerError, "Internal Server Error", msg, ErrorReport{Panic: rec, Stack: stack})
+}
+
 /* ---------- helpers ---------- */
 
 // isSilentError returns true for requests targeting static assets.
 // In these cases, no HTML error page is rendered to avoid corrupting
 // asset responses (e.g. JS, CSS, images, fonts).
-func isSilentError(w http.ResponseWriter, r *http.Request, code int) bool {
-	ext := strings.ToLower(filepath.Ext(r.URL.Path))
+func (e *ErrorRenderer) isSilentError(w http.ResponseWriter, r *http.Request, code int) bool {
+	if !e.silent {
+		return false
+	}
 
-	switch ext {
-	case ".js", ".css", ".map", ".ico", ".png", ".svg", ".jpg", ".jpeg", ".webp",
-		".woff", ".woff2", ".ttf", ".eot", ".gif", ".pdf", ".json", ".xml":
-		w.Header().Del("Content-Type")
-		w.WriteHeader(code)
-		return true
+	ext := strings.ToLower(filepath.Ext(r.URL.Path))
+	if _, ok := e.silentExtensions[ext]; !ok || ext == "" {
+		return false
 	}
 
-	return false
+	w.Header().Del("Content-Type")
+	w.WriteHeader(code)
+	return true
+}
+
+// extensionSet normalizes extensions to a lower-case set with leading dots.
+func extensionSet(exts []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(exts))
+	for _, e := range exts {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		set[e] = struct{}{}
+	}
+	return set
 }
@@ -0,0 +1,159 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Developer error pages.
+
+Summary
+-------
+- In debug mode (SetDebug, e.g. the -dev flag of the example server)
+  browsers get a diagnostic page instead of the error template, for
+  every error rendered by RenderError, RespondError, HandlerE and
+  RenderPanic (middleware.Recovery).
+- The page shows the status, message, cause or panic value, the stack
+  trace, the request (method, URL, remote address, request ID,
+  headers, query) and the routing: the matched pattern and the routes
+  of the server with their middleware.
+- Credentials (Authorization, Proxy-Authorization, Cookie) are masked.
+- API and gRPC clients keep their problem+json or gRPC status, with the
+  cause as detail. Never enable debug mode in production.
+*/
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// debugHeaders are masked on the debug page.
+var debugHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// debugPage is the template of the developer error page.
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Code}} {{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 0; color: #222; }
+header { background: {{if ge .Code 500}}#c0392b{{else}}#d68910{{end}}; color: white; padding: 20px 30px; }
+header h1 { margin: 0 0 8px; font-size: 24px; }
+section { padding: 10px 30px; }
+h2 { font-size: 16px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+pre { background: #f5f5f5; padding: 12px; overflow-x: auto; font-size: 12px; }
+table { border-collapse: collapse; font-size: 13px; }
+td { padding: 2px 12px 2px 0; vertical-align: top; }
+td:first-child { color: #666; white-space: nowrap; }
+.match { font-weight: bold; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Code}} {{.Title}}</h1>
+<div>{{.Message}}</div>
+</header>
+{{if .Cause}}<section><h2>Cause</h2><pre>{{.Cause}}</pre></section>{{end}}
+{{if .Stack}}<section><h2>Stack trace</h2><pre>{{.Stack}}</pre></section>{{end}}
+<section>
+<h2>Request</h2>
+<table>
+<tr><td>Method</td><td>{{.Method}}</td></tr>
+<tr><td>URL</td><td>{{.URL}}</td></tr>
+<tr><td>Host</td><td>{{.Host}}</td></tr>
+<tr><td>Protocol</td><td>{{.Proto}}</td></tr>
+<tr><td>Remote</td><td>{{.Remote}}</td></tr>
+{{with .RequestID}}<tr><td>Request ID</td><td>{{.}}</td></tr>{{end}}
+</table>
+<h2>Headers</h2>
+<table>
+{{range .Headers}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{with .Query}}<h2>Query</h2>
+<table>
+{{range .}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+<section>
+<h2>Routing</h2>
+<p>Matched pattern: {{with .Pattern}}<code>{{.}}</code>{{else}}none{{end}}</p>
+{{with .Routes}}<table>
+{{range .}}<tr{{if .Match}} class="match"{{end}}><td>{{.Pattern}}</td><td>{{.Middleware}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+</body>
+</html>
+`))
+
+// debugRoute is a route on the debug page.
+type debugRoute struct {
+	Pattern    string
+	Middleware string
+	Match      bool
+}
+
+// writeDebugPage writes the developer error page.
+func (e *ErrorRenderer) writeDebugPage(w http.ResponseWriter, r *http.Request, code int, title, message string, diag ErrorReport) {
+	data := map[string]any{
+		"Code":      code,
+		"Title":     title,
+		"Message":   message,
+		"Method":    r.Method,
+		"URL":       r.URL.String(),
+		"Host":      r.Host,
+		"Proto":     r.Proto,
+		"Remote":    r.RemoteAddr,
+		"RequestID": RequestID(r.Context()),
+		"Pattern":   r.Pattern,
+		"Headers":   debugValues(r.Header, true),
+		"Query":     debugValues(r.URL.Query(), false),
+		"Stack":     string(diag.Stack),
+	}
+	switch {
+	case diag.Panic != nil:
+		data["Cause"] = fmt.Sprintf("panic: %v", diag.Panic)
+	case diag.Err != nil:
+		data["Cause"] = diag.Err.Error()
+	}
+	if srv, ok := serverKey.Get(r.Context()); ok {
+		var routes []debugRoute
+		for _, rt := range srv.Routes() {
+			var mw []string
+			for _, m := range rt.Middleware {
+				mw = append(mw, m.Name)
+			}
+			routes = append(routes, debugRoute{
+				Pattern:    rt.Pattern,
+				Middleware: strings.Join(mw, ", "),
+				Match:      rt.Pattern == r.Pattern,
+			})
+		}
+		data["Routes"] = routes
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := debugPage.Execute(w, data); err != nil {
+		http.Error(w, message, code)
+	}
+}
+
+// debugValues returns the sorted name/value pairs of h, with credentials
+// masked if mask is set.
+func debugValues(h map[string][]string, mask bool) [][2]string {
+	var out [][2]string
+	for name, values := range h {
+		for _, v := range values {
+			if mask && slices.Contains(debugHeaders, http.CanonicalHeaderKey(name)) {
+				v = "[masked]"
+			}
+			out = append(out, [2]string{name, v})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b [2]string) int { return strings.Compare(a[0], b[0]) })
+	return out
+}
@@ -0,0 +1,137 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Locale-aware error pages.
+
+Summary
+-------
+- RenderError determines the locale of a request (by default the first
+  language of Accept-Language; SetLocale or SetErrorLocale plug in a
+  locale middleware or a user setting) and passes it to the template as
+  .Locale.
+- The error template is selected by locale: for locale "de-AT" and
+  template name "error" the first defined of "error.de-AT", "error.de"
+  and "error" is executed.
+- SetTranslator (SetErrorTranslator) translates titles and messages
+  (e.g. from an i18n catalog). ErrorCatalog is a simple in-memory translator keyed by
+  locale and status code.
+
+Typical usage:
+
+	server.SetErrorTranslator(server.ErrorCatalog{
+		"de": {
+			404: {Title: "Nicht gefunden", Message: "Die Seite existiert nicht."},
+			500: {Title: "Serverfehler", Message: "Auf dem Server ist ein Fehler aufgetreten."},
+		},
+	}.Translate)
+*/
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorText is a translated error title and message.
+type ErrorText struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// ErrorTranslator returns the title and message of an error in locale.
+// It returns the given texts if it has no translation.
+type ErrorTranslator func(locale string, code int, title, message string) (string, string)
+
+// SetLocale sets the function determining the locale of a request for
+// error pages. nil restores the default (AcceptLanguage).
+func (e *ErrorRenderer) SetLocale(fn func(r *http.Request) string) {
+	if fn == nil {
+		fn = AcceptLanguage
+	}
+	e.locale = fn
+}
+
+// SetTranslator sets the translator of error titles and messages.
+// nil disables translation.
+func (e *ErrorRenderer) SetTranslator(fn ErrorTranslator) {
+	e.translator = fn
+}
+
+// SetErrorLocale sets the locale function of the default renderer (see
+// ErrorRenderer.SetLocale).
+func SetErrorLocale(fn func(r *http.Request) string) {
+	defaultErrors.SetLocale(fn)
+}
+
+// SetErrorTranslator sets the translator of the default renderer (see
+// ErrorRenderer.SetTranslator).
+func SetErrorTranslator(fn ErrorTranslator) {
+	defaultErrors.SetTranslator(fn)
+}
+
+// AcceptLanguage returns the first language tag of the Accept-Language
+// header (e.g. "de-AT"), or "" if there is none.
+func AcceptLanguage(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// ErrorCatalog maps locales to error texts by status code.
+type ErrorCatalog map[string]map[int]ErrorText
+
+// Translate implements ErrorTranslator. A locale like "de-AT" falls back
+// to "de"; texts missing in the catalog stay untranslated.
+func (c ErrorCatalog) Translate(locale string, code int, title, message string) (string, string) {
+	for _, l := range localeCandidates(locale) {
+		if t, ok := c[l][code]; ok {
+			if t.Title != "" {
+				title = t.Title
+			}
+			if t.Message != "" {
+				message = t.Message
+			}
+			break
+		}
+	}
+	return title, message
+}
+
+// localize returns the locale, template name, title and message used
+// to render an error for r with page.
+func (e *ErrorRenderer) localize(page *errorPage, r *http.Request, code int, title, message string) (string, string, string, string) {
+	locale := e.locale(r)
+	if e.translator != nil && locale != "" {
+		title, message = e.translator(locale, code, title, message)
+	}
+
+	name := page.name
+	for _, l := range localeCandidates(locale) {
+		if page.tpl.Lookup(page.name+"."+l) != nil {
+			name = page.name + "." + l
+			break
+		}
+	}
+	return locale, name, title, message
+}
+
+// localeCandidates returns locale and its base languages, most specific
+// first ("de-AT" -> "de-AT", "de").
+func localeCandidates(locale string) []string {
+	var out []string
+	for locale != "" {
+		out = append(out, locale)
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Error reporting.
+
+Summary
+-------
+- SetReporter (SetErrorReporter for the default renderer) registers a
+  callback receiving every 5xx response rendered by RenderError,
+  RespondError and HandlerE, and every panic rendered by RenderPanic
+  (middleware.Recovery), e.g. to forward crashes to Sentry or a chat
+  webhook.
+- An ErrorReport carries the request metadata, the error cause if known
+  and a stack trace: the panic's, or the caller's for other errors.
+- The reporter runs in its own goroutine, so a slow webhook does not
+  delay the response; a panicking reporter is logged. Requests whose
+  client went away are not reported.
+
+Typical usage:
+
+	errs.SetReporter(func(rep server.ErrorReport) {
+		body, _ := json.Marshal(map[string]string{"text": rep.String()})
+		http.Post(slackHook, "application/json", bytes.NewReader(body))
+	})
+*/
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// ErrorReport describes a 5xx response or a panic.
+type ErrorReport struct {
+	Time      time.Time
+	Code      int    // status code
+	Message   string // client-facing message
+	Err       error  // cause, if known (RespondError)
+	Panic     any    // recovered value, nil for other errors
+	Stack     []byte // stack trace of the panic or the reporting caller
+	Method    string
+	URL       string // request URI
+	Host      string
+	Remote    string // client address
+	UserAgent string
+	RequestID string // see middleware.RequestID
+}
+
+// ErrorReporter receives error reports (see SetReporter).
+type ErrorReporter func(rep ErrorReport)
+
+// String returns a one-line summary, e.g.
+// "500 GET /notes (req 3f2a…): panic: boom".
+func (rep ErrorReport) String() string {
+	cause := rep.Message
+	switch {
+	case rep.Panic != nil:
+		cause = fmt.Sprintf("panic: %v", rep.Panic)
+	case rep.Err != nil:
+		cause = rep.Err.Error()
+	}
+	if rep.RequestID != "" {
+		return fmt.Sprintf("%d %s %s (req %s): %s", rep.Code, rep.Method, rep.URL, rep.RequestID, cause)
+	}
+	return fmt.Sprintf("%d %s %s: %s", rep.Code, rep.Method, rep.URL, cause)
+}
+
+// SetReporter sets the callback receiving 5xx responses and panics.
+// nil disables reporting.
+func (e *ErrorRenderer) SetReporter(fn ErrorReporter) {
+	e.reporter = fn
+}
+
+// SetErrorReporter sets the reporter of the default renderer (see
+// ErrorRenderer.SetReporter).
+func SetErrorReporter(fn ErrorReporter) {
+	defaultErrors.SetReporter(fn)
+}
+
+// report completes rep with the request metadata and hands it to the
+// reporter.
+func (e *ErrorRenderer) report(r *http.Request, rep ErrorReport) {
+	fn := e.reporter
+	if fn == nil || ClientGone(r) {
+		return
+	}
+
+	rep.Time = time.Now()
+	if rep.Stack == nil {
+		rep.Stack = debug.Stack()
+	}
+	rep.Method = r.Method
+	rep.URL = r.URL.RequestURI()
+	rep.Host = r.Host
+	rep.Remote = r.RemoteAddr
+	rep.UserAgent = r.UserAgent()
+	rep.RequestID = RequestID(r.Context())
+
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("panic in error reporter: %v", rec)
+			}
+		}()
+		fn(rep)
+	}()
+}
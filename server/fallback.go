@@ -0,0 +1,107 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Fallback error pages for the ServeMux.
+
+Summary
+-------
+- http.ServeMux answers unmatched paths and methods with its own plain
+  text 404 and 405 responses. WrapMux replaces them with NotFound and
+  MethodNotAllowed, so they get the themed error page, problem+json for
+  API clients and the renderer of the server (see error.go).
+- The Allow header of a 405 is kept; the mux's redirects (e.g. to add
+  a trailing slash) pass through unchanged.
+- Servers created with NewServer (or NewServerWithHandler and a
+  ServeMux) wrap their mux automatically. A catch-all pattern ("/")
+  still takes precedence, since the mux then matches every path.
+- Middleware given to WrapMux (or Server.WrapFallback) only wraps
+  requests without a route, e.g. to redirect moved URLs before the 404
+  is rendered, instead of a catch-all route that would also hide 405.
+
+Typical usage with a separate mux:
+
+	http.ListenAndServe(":8080", server.WrapMux(mux))
+
+Typical usage with a server:
+
+	srv.WrapFallback(moved.Middleware())
+*/
+
+import (
+	"net/http"
+)
+
+// WrapMux returns a handler serving mux, with NotFound and
+// MethodNotAllowed for requests the mux has no route for. Requests
+// without a route pass mw first (outermost first).
+func WrapMux(mux *http.ServeMux, mw ...func(http.Handler) http.Handler) http.Handler {
+	fallback := muxFallback(mux)
+	for i := len(mw) - 1; i >= 0; i-- {
+		fallback = mw[i](fallback)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		fallback.ServeHTTP(w, r)
+	})
+}
+
+// WrapFallback wraps the requests no route matches in mw (see WrapMux).
+// A later call replaces the middleware of an earlier one. It does nothing
+// if the server was created with a handler that is not a ServeMux. Call
+// it before the server is started.
+func (s *Server) WrapFallback(mw ...func(http.Handler) http.Handler) {
+	if !s.wrapped {
+		return
+	}
+	s.handler = WrapMux(s.mux, mw...)
+}
+
+// muxFallback answers requests mux has no route for.
+func muxFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Let the mux's handler decide between 404, 405 and a redirect,
+		// and render the errors ourselves
+		h, _ := mux.Handler(r)
+		fw := &fallbackWriter{ResponseWriter: w}
+		h.ServeHTTP(fw, r)
+		switch fw.code {
+		case http.StatusNotFound:
+			NotFound(w, r)
+		case http.StatusMethodNotAllowed:
+			MethodNotAllowed(w, r)
+		}
+	})
+}
+
+// fallbackWriter swallows the plain 404 and 405 responses of the mux.
+type fallbackWriter struct {
+	http.ResponseWriter
+	code int // intercepted status; 0 if passed through
+}
+
+// WriteHeader intercepts 404 and 405 and passes other statuses on.
+func (w *fallbackWriter) WriteHeader(code int) {
+	if code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		w.code = code
+		h := w.Header()
+		h.Del("Content-Type")
+		h.Del("X-Content-Type-Options")
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write discards the body of an intercepted response.
+func (w *fallbackWriter) Write(b []byte) (int, error) {
+	if w.code != 0 {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
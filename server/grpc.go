@@ -0,0 +1,163 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+gRPC alongside HTTP.
+
+Summary
+-------
+- HandleGRPC mounts a gRPC handler (e.g. *grpc.Server from
+  google.golang.org/grpc, which implements http.Handler) next to the
+  HTTP routes.
+- Shared port: gRPC requests (HTTP/2, Content-Type application/grpc)
+  are dispatched to the gRPC handler, everything else to the mux.
+  Without TLS the server accepts unencrypted HTTP/2 (h2c).
+- Separate port: set ServerConfig.GRPCPort to serve gRPC on its own
+  listener; start and shutdown are handled together with HTTP.
+- Because gRPC is served as an http.Handler, the HTTP middleware
+  (request ID, logging, recovery, bearer auth, rate limiting) applies
+  unchanged. Error helpers (RenderError, RenderPanic) answer gRPC
+  requests with a gRPC status instead of an HTML page.
+
+Typical usage:
+
+	gs := grpc.NewServer()
+	pb.RegisterNotesServer(gs, notesService)
+
+	srv.HandleGRPC(middleware.Chain(
+		middleware.Recovery,
+		middleware.RequestID,
+		middleware.Logging,
+		middleware.BearerContextMap(parser),
+		middleware.RequireBearer(),
+	)(gs))
+*/
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GRPCCode is a gRPC status code.
+type GRPCCode int
+
+// gRPC status codes (see google.golang.org/grpc/codes).
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+// IsGRPC reports whether r is a gRPC request.
+func IsGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// HandleGRPC mounts h for gRPC requests. Call it before the server is
+// started. With ServerConfig.GRPCPort == 0 gRPC shares the HTTP port;
+// otherwise it is served on its own port.
+func (s *Server) HandleGRPC(h http.Handler) {
+	s.grpcHandler = h
+
+	if s.config.GRPCPort != 0 {
+		s.grpcServer = &http.Server{
+			Addr: fmt.Sprintf("%s:%d", s.config.Host, s.config.GRPCPort),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				s.countRequest(h, w, r)
+			}),
+			ReadTimeout: time.Duration(s.config.ReadTimeout) * time.Second,
+			Protocols:   h2cProtocols(),
+			BaseContext: s.baseCtx,
+			ConnContext: s.connCtx,
+			ConnState:   s.connState,
+		}
+		return
+	}
+
+	// gRPC needs HTTP/2; accept it without TLS as well
+	s.httpServer.Protocols = h2cProtocols()
+}
+
+// WriteGRPCError writes a trailers-only gRPC error response.
+func WriteGRPCError(w http.ResponseWriter, code GRPCCode, message string) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/grpc")
+	h.Set("Grpc-Status", strconv.Itoa(int(code)))
+	if message != "" {
+		h.Set("Grpc-Message", url.PathEscape(message))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GRPCCodeFromHTTP maps an HTTP status code to the closest gRPC code
+// (following the gRPC HTTP-to-gRPC status mapping).
+func GRPCCodeFromHTTP(code int) GRPCCode {
+	switch code {
+	case http.StatusOK:
+		return GRPCOK
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return GRPCInvalidArgument
+	case http.StatusUnauthorized:
+		return GRPCUnauthenticated
+	case http.StatusForbidden:
+		return GRPCPermissionDenied
+	case http.StatusNotFound:
+		return GRPCNotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return GRPCUnimplemented
+	case http.StatusConflict:
+		return GRPCAlreadyExists
+	case http.StatusTooManyRequests:
+		return GRPCResourceExhausted
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return GRPCDeadlineExceeded
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return GRPCUnavailable
+	case http.StatusInternalServerError:
+		return GRPCInternal
+	}
+	return GRPCUnknown
+}
+
+// serveHTTP dispatches gRPC requests on the shared port and everything
+// else to the root handler, counting them (see stats.go).
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	h := s.handler
+	switch {
+	case s.grpcHandler != nil && s.grpcServer == nil && IsGRPC(r):
+		h = s.grpcHandler
+	case r.Method == http.MethodHead:
+		h = HeadHandler(s.handler)
+	}
+	s.countRequest(h, w, r)
+}
+
+// h2cProtocols enables HTTP/1 and HTTP/2 with and without TLS.
+func h2cProtocols() *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	p.SetHTTP2(true)
+	p.SetUnencryptedHTTP2(true)
+	return p
+}
@@ -0,0 +1,222 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Error-returning handlers.
+
+Summary
+-------
+- HandlerE is a handler that returns an error instead of writing error
+  responses itself; it implements http.Handler.
+- Returned errors are rendered in one place:
+  - *HTTPError (also wrapped) uses its status code, title and message.
+  - sql.ErrNoRows and fs.ErrNotExist become 404, fs.ErrPermission 403.
+  - Any other error becomes a 500; the error is logged, its text is only
+    shown to the client in debug mode (see SetDebug and errordebug.go).
+  - Errors after the client went away are ignored (see ClientGone).
+- Errors go through RenderError: API clients (Accept: application/json
+  or application/problem+json) get an RFC 9457 problem+json body,
+  everything else an HTML error page, or a gRPC status for gRPC
+  requests.
+- Panics are logged with their stack trace and rendered by RenderPanic.
+- Handlers not using HandlerE call RespondError with the error instead
+  of choosing an error helper themselves.
+
+Typical usage:
+
+	srv.Handle("GET /api/notes/{id}", server.HandlerE(func(w http.ResponseWriter, r *http.Request) error {
+	    note, ok := store.Get(r.PathValue("id"))
+	    if !ok {
+	        return server.Errorf(http.StatusNotFound, "note %s not found", r.PathValue("id"))
+	    }
+	    render.JSON(w, r, http.StatusOK, note)
+	    return nil
+	}))
+*/
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// HandlerE is an HTTP handler returning an error.
+type HandlerE func(w http.ResponseWriter, r *http.Request) error
+
+// HTTPError is an error with an HTTP status code and a client-facing
+// title and message (both default to the status text). Err is the
+// optional cause (logged, never shown to clients).
+type HTTPError struct {
+	Code    int
+	Title   string
+	Message string
+	Err     error
+}
+
+// Error returns the message and the cause.
+func (e *HTTPError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Code)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, msg, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, msg)
+}
+
+// Unwrap returns the cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// NewHTTPError creates an HTTPError. An empty message uses the status text.
+func NewHTTPError(code int, msg string) *HTTPError {
+	return &HTTPError{Code: code, Message: msg}
+}
+
+// Errorf creates an HTTPError with a formatted message.
+func Errorf(code int, format string, args ...any) *HTTPError {
+	return &HTTPError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ServeHTTP calls h and renders a returned error or panic.
+func (h HandlerE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			stack := debug.Stack()
+			log.Printf("panic: %v\n%s", rec, stack)
+			RenderPanic(w, r, rec, stack)
+		}
+	}()
+
+	if err := h(w, r); err != nil {
+		RespondError(w, r, err)
+	}
+}
+
+// errorStatus maps well-known errors to status codes for RespondError.
+var errorStatus = []struct {
+	err  error
+	code int
+}{
+	{sql.ErrNoRows, http.StatusNotFound},
+	{fs.ErrNotExist, http.StatusNotFound},
+	{fs.ErrPermission, http.StatusForbidden},
+}
+
+// RespondError renders err as described in the HandlerE documentation.
+// It can be used directly by handlers not using HandlerE, instead of
+// picking an error helper:
+//   - an *HTTPError in the chain sets status code, title and message;
+//   - sql.ErrNoRows and fs.ErrNotExist become 404, fs.ErrPermission 403;
+//   - anything else becomes a logged 500.
+func RespondError(w http.ResponseWriter, r *http.Request, err error) {
+	if ClientGone(r) {
+		return // nobody reads the response; the access log marks the request
+	}
+
+	code, title, msg := http.StatusInternalServerError, "", "An internal error occurred."
+	var he *HTTPError
+	if errors.As(err, &he) {
+		code, title, msg = he.Code, he.Title, he.Message
+	} else {
+		for _, m := range errorStatus {
+			if errors.Is(err, m.err) {
+				code, msg = m.code, ""
+				break
+			}
+		}
+	}
+	if title == "" {
+		title = http.StatusText(code)
+	}
+	if msg == "" {
+		msg = http.StatusText(code)
+	}
+
+	e := ErrorRendererFor(r)
+	if code >= 500 {
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+		e.report(r, ErrorReport{Code: code, Message: msg, Err: err})
+		if e.Debug() && he == nil {
+			msg = err.Error()
+		}
+	}
+
+	e.writeError(w, r, code, title, msg, ErrorReport{Err: err})
+}
+
+// WriteError renders err; it is kept for compatibility, see RespondError.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	RespondError(w, r, err)
+}
+
+// WriteProblem writes p as application/problem+json. Instance and
+// RequestID default to the request path and the request ID of the
+// context or the X-Request-ID response header (set by
+// middleware.RequestID).
+func WriteProblem(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = RequestID(r.Context())
+	}
+	if p.RequestID == "" {
+		p.RequestID = w.Header().Get("X-Request-ID")
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, p.Detail, p.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(append(b, '\n'))
+}
+
+// wantsProblem reports whether the client prefers a JSON error body.
+func wantsProblem(r *http.Request) bool {
+	if IsGRPC(r) {
+		return false
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mt == "application/json" || mt == "application/problem+json" || strings.HasSuffix(mt, "+json") {
+			return true
+		}
+	}
+	return false
+}
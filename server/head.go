@@ -0,0 +1,115 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+HEAD requests.
+
+Summary
+-------
+- GET patterns on the ServeMux also match HEAD, and net/http discards
+  the body of HEAD responses. What it cannot do is announce the length
+  of a body it never sees: hand-written handlers answered HEAD without
+  Content-Length.
+- The server runs HEAD requests through HeadHandler: body writes are
+  counted and discarded, and once the handler returns the header is sent
+  with the Content-Length the GET response would have (unless the
+  handler set one). Headers are taken as they were when WriteHeader was
+  called, as for GET.
+- Handlers can skip producing the body with SkipBody; handlers setting
+  Content-Length themselves (render helpers, static files) are left
+  unchanged.
+*/
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// SkipBody reports whether the response body of r is discarded (HEAD),
+// so handlers may return right after setting the headers.
+func SkipBody(r *http.Request) bool {
+	return r.Method == http.MethodHead
+}
+
+// HeadHandler adds Content-Length to HEAD responses of h (see above).
+// Other methods are passed through unchanged.
+func HeadHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		hw := &headWriter{ResponseWriter: w}
+		defer hw.finish()
+		h.ServeHTTP(hw, r)
+	})
+}
+
+// headWriter counts body bytes and sends the header when the handler is done.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header // snapshot taken at WriteHeader
+	length int64
+	sent   bool
+}
+
+// WriteHeader records the status and the header at this point.
+func (w *headWriter) WriteHeader(code int) {
+	if w.status != 0 || w.sent {
+		return
+	}
+	if code < 200 {
+		// Informational responses (103 Early Hints) go out immediately
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	w.header = w.Header().Clone()
+}
+
+// Write counts and discards the body.
+func (w *headWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.length += int64(len(b))
+	return len(b), nil
+}
+
+// Flush is a no-op: the header is sent when the handler returns.
+func (w *headWriter) Flush() {}
+
+// Unwrap returns the underlying writer (for http.ResponseController).
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish sends the header with the counted Content-Length.
+func (w *headWriter) finish() {
+	if w.sent {
+		return
+	}
+	w.sent = true
+	if w.status == 0 {
+		// Nothing written: net/http sends 200 without a length
+		return
+	}
+
+	h := w.ResponseWriter.Header()
+	clear(h)
+	for k, v := range w.header {
+		h[k] = v
+	}
+	if w.length > 0 && h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" && bodyAllowed(w.status) {
+		h.Set("Content-Length", strconv.FormatInt(w.length, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// bodyAllowed reports whether a status may carry a body.
+func bodyAllowed(code int) bool {
+	return code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,312 @@
+package health
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package health serves liveness, readiness and health probes.
+
+Summary
+-------
+- A Checker holds named check functions of two kinds: liveness checks
+  (the process works at all; a failure means restart it) and readiness
+  checks (dependencies such as the database are reachable; a failure
+  means send no traffic for now).
+- LiveHandler (/livez) runs the liveness checks, ReadyHandler (/readyz)
+  all checks plus the ready flag, HealthHandler (/healthz) all checks.
+  The ready flag is set by the server once it accepts traffic and
+  cleared when it shuts down, so load balancers stop routing to a
+  draining instance.
+- Checks run concurrently with a timeout each (Config.Timeout); a
+  panicking check fails instead of crashing the server.
+- Responses are JSON (Report) with status 200 or 503. Error messages of
+  failed checks are only included with Config.Details, since probes are
+  usually reachable without authentication.
+- Ready-made checks: Ping (database/sql and anything with PingContext)
+  and DiskSpace. Any func(ctx) error is a Check.
+- With server.ServerConfig.Health.Enabled the server mounts the three
+  endpoints and exposes its Checker via Server.Health.
+
+Typical usage:
+
+	h := srv.Health()
+	h.AddReadiness("db", health.Ping(db))
+	h.AddReadiness("disk", health.DiskSpace("/var/lib/app", 1<<30))
+
+Example response (GET /readyz):
+
+	{"status":"fail","checks":[{"name":"ready","status":"ok","duration_ms":0},
+	 {"name":"db","status":"fail","duration_ms":5000,"error":"context deadline exceeded"}]}
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Config controls the probe endpoints. It is part of server.ServerConfig.
+type Config struct {
+	Enabled bool `json:"enabled"` // Mount /healthz, /readyz and /livez
+	Timeout int  `json:"timeout"` // Seconds per check (default 5)
+	Details bool `json:"details"` // Include error messages of failed checks in the response
+}
+
+// DefaultTimeout is used if Config.Timeout is not set.
+const DefaultTimeout = 5 * time.Second
+
+// Check reports an error if a component is unhealthy.
+type Check func(ctx context.Context) error
+
+// Kind selects the probes a check belongs to.
+type Kind int
+
+// Kinds of checks.
+const (
+	Liveness  Kind = iota // /livez, /readyz and /healthz
+	Readiness             // /readyz and /healthz
+)
+
+// Status values of a Report and a Result.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration int64  `json:"duration_ms"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the response of a probe.
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// check is a registered check.
+type check struct {
+	name string
+	kind Kind
+	fn   Check
+}
+
+// Checker holds the checks and the ready flag. It is safe for
+// concurrent use.
+type Checker struct {
+	config Config
+
+	mu     sync.Mutex
+	checks []check
+
+	ready atomic.Bool
+}
+
+// New creates a Checker. It is not ready until SetReady(true).
+func New(cfg Config) *Checker {
+	return &Checker{config: cfg}
+}
+
+// Config returns the configuration.
+func (c *Checker) Config() Config {
+	return c.config
+}
+
+// Add registers a check of the given kind.
+func (c *Checker) Add(name string, kind Kind, fn Check) {
+	c.mu.Lock()
+	c.checks = append(c.checks, check{name: name, kind: kind, fn: fn})
+	c.mu.Unlock()
+}
+
+// AddLiveness registers a liveness check.
+func (c *Checker) AddLiveness(name string, fn Check) {
+	c.Add(name, Liveness, fn)
+}
+
+// AddReadiness registers a readiness check.
+func (c *Checker) AddReadiness(name string, fn Check) {
+	c.Add(name, Readiness, fn)
+}
+
+// SetReady sets the ready flag reported by /readyz.
+func (c *Checker) SetReady(ready bool) {
+	c.ready.Store(ready)
+}
+
+// Ready reports the ready flag.
+func (c *Checker) Ready() bool {
+	return c.ready.Load()
+}
+
+// Run runs the checks of the given kinds concurrently and returns the
+// report; results keep registration order.
+func (c *Checker) Run(ctx context.Context, kinds ...Kind) Report {
+	c.mu.Lock()
+	var checks []check
+	for _, ch := range c.checks {
+		for _, k := range kinds {
+			if ch.kind == k {
+				checks = append(checks, ch)
+				break
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	timeout := DefaultTimeout
+	if c.config.Timeout > 0 {
+		timeout = time.Duration(c.config.Timeout) * time.Second
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, ch, timeout)
+		}()
+	}
+	wg.Wait()
+
+	rep := Report{Status: StatusOK, Checks: results}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			rep.Status = StatusFail
+		}
+	}
+	return rep
+}
+
+// LiveHandler serves the liveness probe.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.write(w, c.Run(r.Context(), Liveness))
+	})
+}
+
+// ReadyHandler serves the readiness probe: the ready flag and all checks.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flag := Result{Name: "ready", Status: StatusOK}
+		if !c.Ready() {
+			flag.Status, flag.Error = StatusFail, "not accepting traffic"
+		}
+
+		rep := c.Run(r.Context(), Liveness, Readiness)
+		rep.Checks = append([]Result{flag}, rep.Checks...)
+		if flag.Status != StatusOK {
+			rep.Status = StatusFail
+		}
+		c.write(w, rep)
+	})
+}
+
+// HealthHandler serves the combined health check: all checks.
+func (c *Checker) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.write(w, c.Run(r.Context(), Liveness, Readiness))
+	})
+}
+
+// write sends rep as JSON with status 200 or 503.
+func (c *Checker) write(w http.ResponseWriter, rep Report) {
+	if !c.config.Details {
+		for i := range rep.Checks {
+			if rep.Checks[i].Name != "ready" {
+				rep.Checks[i].Error = ""
+			}
+		}
+	}
+
+	status := http.StatusOK
+	if rep.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(rep)
+}
+
+// run runs a single check with its timeout and recovers panics.
+func run(ctx context.Context, ch check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// Do not wait for checks ignoring their context beyond the timeout
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				errc <- fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		errc <- ch.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := Result{Name: ch.name, Status: StatusOK, Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		res.Status, res.Error = StatusFail, err.Error()
+	}
+	return res
+}
+
+/* ---------- checks ---------- */
+
+// Pinger is implemented by *sql.DB and most database clients.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks that p answers a ping.
+func Ping(p Pinger) Check {
+	return p.PingContext
+}
+
+// DiskSpace checks that the file system holding path has at least
+// minFree bytes available. If path does not exist yet (e.g. an upload
+// directory created on first write), its nearest existing parent is used.
+func DiskSpace(path string, minFree uint64) Check {
+	return func(context.Context) error {
+		var st syscall.Statfs_t
+		dir := path
+		for {
+			err := syscall.Statfs(dir, &st)
+			if err == nil {
+				break
+			}
+			parent := filepath.Dir(dir)
+			if !errors.Is(err, fs.ErrNotExist) || parent == dir {
+				return err
+			}
+			dir = parent
+		}
+		free := st.Bavail * uint64(st.Bsize)
+		if free < minFree {
+			return fmt.Errorf("%s: %d MiB free, need %d MiB", path, free>>20, minFree>>20)
+		}
+		return nil
+	}
+}
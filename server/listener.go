@@ -0,0 +1,228 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Connection limits at the listener level.
+
+Summary
+-------
+- LimitListener wraps a net.Listener and caps concurrent connections
+  in total and per remote IP, before any request is read. This bounds
+  memory and goroutines even for clients that open connections without
+  sending requests (slowloris) and runs before any middleware.
+- When the total limit is reached, Accept waits for a slot: further
+  clients queue in the kernel backlog instead of being dropped.
+- Connections from an IP above its limit are closed immediately.
+- Configured via ServerConfig.MaxConns and ServerConfig.MaxConnsPerIP;
+  zero disables a limit. Both apply to the HTTP and the gRPC listener
+  and to the plain HTTP listeners (redirects, ACME challenges), each
+  counting on its own.
+- Rejected connections of all listeners are reported in
+  Server.Stats (ConnsRejected).
+
+Note: behind a reverse proxy all connections come from the proxy's IP
+(also with PROXY protocol, whose header is read after Accept); set
+MaxConnsPerIP to 0 there and limit per client at the proxy.
+*/
+
+import (
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// LimitListener limits concurrent connections of a listener.
+type LimitListener struct {
+	net.Listener
+
+	perIP int
+	slots chan struct{} // nil without total limit
+
+	mu    sync.Mutex
+	conns map[string]int // open connections per IP
+
+	done     chan struct{}
+	once     sync.Once
+	rejected atomic.Uint64
+}
+
+// NewLimitListener wraps ln. maxConns limits the total and maxPerIP the
+// per-IP number of open connections (0 means unlimited).
+func NewLimitListener(ln net.Listener, maxConns, maxPerIP int) *LimitListener {
+	l := &LimitListener{
+		Listener: ln,
+		perIP:    maxPerIP,
+		conns:    map[string]int{},
+		done:     make(chan struct{}),
+	}
+	if maxConns > 0 {
+		l.slots = make(chan struct{}, maxConns)
+	}
+	return l
+}
+
+// Accept waits for a free slot and returns the next connection whose
+// IP is below its limit.
+func (l *LimitListener) Accept() (net.Conn, error) {
+	for {
+		if !l.acquire() {
+			return nil, net.ErrClosed
+		}
+
+		c, err := l.Listener.Accept()
+		if err != nil {
+			l.release()
+			return nil, err
+		}
+
+		ip := remoteIP(c)
+		if !l.track(ip) {
+			l.release()
+			l.rejected.Add(1)
+			c.Close()
+			continue
+		}
+		return &limitConn{Conn: c, l: l, ip: ip}, nil
+	}
+}
+
+// Close closes the listener and releases a blocked Accept.
+func (l *LimitListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+// Open returns the number of open connections.
+func (l *LimitListener) Open() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := 0
+	for _, c := range l.conns {
+		n += c
+	}
+	return n
+}
+
+// Rejected returns the number of connections closed because their IP
+// was at its limit.
+func (l *LimitListener) Rejected() uint64 {
+	return l.rejected.Load()
+}
+
+// acquire takes a total slot; it returns false if the listener closed.
+func (l *LimitListener) acquire() bool {
+	if l.slots == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-l.done:
+		return false
+	}
+}
+
+// release returns a total slot.
+func (l *LimitListener) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// track counts a connection for ip; it returns false if ip is at its limit.
+func (l *LimitListener) track(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.perIP > 0 && l.conns[ip] >= l.perIP {
+		return false
+	}
+	l.conns[ip]++
+	return true
+}
+
+// untrack removes a closed connection.
+func (l *LimitListener) untrack(ip string) {
+	l.mu.Lock()
+	if l.conns[ip]--; l.conns[ip] <= 0 {
+		delete(l.conns, ip)
+	}
+	l.mu.Unlock()
+
+	l.release()
+}
+
+// limitConn releases its slot when closed.
+type limitConn struct {
+	net.Conn
+	l    *LimitListener
+	ip   string
+	once sync.Once
+}
+
+// Close closes the connection and frees its slot.
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { c.l.untrack(c.ip) })
+	return err
+}
+
+// remoteIP returns the IP of a connection's remote address.
+func remoteIP(c net.Conn) string {
+	addr := c.RemoteAddr().String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// listen opens a TCP listener on addr (or takes over an inherited one,
+// see upgrade.go) with the configured limits and PROXY protocol support.
+func (s *Server) listen(addr string) (net.Listener, error) {
+	ln, err := s.listenTCP(addr)
+	if err != nil {
+		return nil, err
+	}
+	return s.proxyListener(s.limitListener(ln, addr), addr), nil
+}
+
+// limitListener wraps ln with the configured connection limits, if any.
+func (s *Server) limitListener(ln net.Listener, addr string) net.Listener {
+	if s.config.MaxConns <= 0 && s.config.MaxConnsPerIP <= 0 {
+		return ln
+	}
+	log.Printf("Connection limits on %s: total=%d per-ip=%d", addr, s.config.MaxConns, s.config.MaxConnsPerIP)
+	l := NewLimitListener(ln, s.config.MaxConns, s.config.MaxConnsPerIP)
+	s.listenersMu.Lock()
+	s.limits = append(s.limits, l)
+	s.listenersMu.Unlock()
+	return l
+}
+
+// connsRejected sums the rejected connections of all limited listeners.
+func (s *Server) connsRejected() uint64 {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+
+	var n uint64
+	for _, l := range s.limits {
+		n += l.Rejected()
+	}
+	return n
+}
+
+// proxyListener wraps ln for PROXY protocol support if configured. The
+// plain HTTP listeners (redirects, ACME challenges) use it as well, since
+// a balancer usually forwards port 80 the same way.
+func (s *Server) proxyListener(ln net.Listener, addr string) net.Listener {
+	if !s.config.ProxyProtocol {
+		return ln
+	}
+	log.Printf("PROXY protocol on %s (trusted: %v)", addr, s.config.ProxyTrusted)
+	return NewProxyListener(ln, s.proxyTrusted)
+}
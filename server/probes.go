@@ -0,0 +1,52 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Health, readiness and liveness probes.
+
+Summary
+-------
+- Every server has a health.Checker (Health). With
+  ServerConfig.Health.Enabled, GET /healthz, /readyz and /livez are
+  mounted on the mux without middleware, so probes are neither rate
+  limited nor logged. Servers created with NewServerWithHandler mount
+  the handlers of Health themselves.
+- The ready flag follows the lifecycle state (state.go): it is set once
+  the listener accepts traffic and the warmup hooks are done (Running,
+  see warmup.go) and cleared when shutdown
+  begins (Draining).
+
+Example config:
+
+	"server": { "health": { "enabled": true, "timeout": 2 } }
+*/
+
+import (
+	"github.com/bennof/gobfwebservice/server/health"
+)
+
+// Health returns the checker behind the probe endpoints; register checks
+// with AddLiveness and AddReadiness.
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
+// mountProbes registers the probe endpoints if enabled.
+func (s *Server) mountProbes() {
+	if !s.config.Health.Enabled || s.mux == nil {
+		return
+	}
+
+	tags := []string{"health"}
+	s.Handle("GET /livez", s.health.LiveHandler(), RouteDoc{
+		Summary: "Liveness probe", Tags: tags, Response: health.Report{},
+	})
+	s.Handle("GET /readyz", s.health.ReadyHandler(), RouteDoc{
+		Summary: "Readiness probe", Tags: tags, Response: health.Report{},
+	})
+	s.Handle("GET /healthz", s.health.HealthHandler(), RouteDoc{
+		Summary: "Health check", Tags: tags, Response: health.Report{},
+	})
+}
@@ -0,0 +1,249 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+PROXY protocol (v1 and v2) at the listener.
+
+Summary
+-------
+- Behind a TCP load balancer (HAProxy, AWS NLB, ...) every connection
+  comes from the balancer, so logs, rate limits and ACLs see its
+  address. With ServerConfig.ProxyProtocol the listener reads the PROXY
+  header the balancer sends first and reports the client address it
+  carries as the connection's RemoteAddr, i.e. as r.RemoteAddr.
+- Text (v1) and binary (v2) headers are detected automatically. LOCAL
+  connections (v2 health checks) and "UNKNOWN" keep the peer address.
+- Connections from ProxyTrusted networks (CIDR) must start with a
+  header; they are closed otherwise. Other peers are served without a
+  header, so a spoofed one is never trusted. An empty list trusts every
+  peer: only use that if the port is reachable through the balancer only.
+- The header is read on first use of the connection (in the serving
+  goroutine, with a 5s deadline), never in Accept, so a slow peer cannot
+  stall the listener. Connection limits (listener.go) count the
+  balancer's address.
+- Applies to the HTTP, gRPC, redirect and ACME challenge listeners; the
+  admin listener is never behind the balancer.
+
+Example config:
+
+	"server": { "port": 8080, "proxy_protocol": true, "proxy_trusted": ["10.0.0.0/8"] }
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyHeaderTimeout bounds reading the PROXY header.
+const proxyHeaderTimeout = 5 * time.Second
+
+// proxyV2Sig starts every binary PROXY header.
+var proxyV2Sig = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// ErrProxyHeader is returned for a missing or malformed PROXY header.
+var ErrProxyHeader = errors.New("server: invalid PROXY protocol header")
+
+// ProxyListener reads PROXY protocol headers of accepted connections.
+type ProxyListener struct {
+	net.Listener
+	trusted []netip.Prefix // empty trusts every peer
+}
+
+// NewProxyListener wraps ln. Connections from trusted networks (all
+// if trusted is empty) must start with a PROXY header.
+func NewProxyListener(ln net.Listener, trusted []netip.Prefix) *ProxyListener {
+	return &ProxyListener{Listener: ln, trusted: trusted}
+}
+
+// Accept returns the next connection; the header is read on first use.
+func (l *ProxyListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.trusts(c.RemoteAddr()) {
+		return c, nil
+	}
+	return &proxyConn{Conn: c, r: bufio.NewReader(c)}, nil
+}
+
+// trusts reports whether addr belongs to a trusted network.
+func (l *ProxyListener) trusts(addr net.Addr) bool {
+	if len(l.trusted) == 0 {
+		return true
+	}
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := ap.Addr().Unmap()
+	for _, p := range l.trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxyTrusted parses the trusted networks of ServerConfig.
+// Single addresses are accepted as /32 or /128 networks.
+func parseProxyTrusted(nets []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(nets))
+	for _, n := range nets {
+		if !strings.Contains(n, "/") {
+			ip, err := netip.ParseAddr(n)
+			if err != nil {
+				return nil, fmt.Errorf("server: proxy_trusted: %w", err)
+			}
+			out = append(out, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, fmt.Errorf("server: proxy_trusted: %w", err)
+		}
+		out = append(out, p.Masked())
+	}
+	return out, nil
+}
+
+// proxyConn is a connection starting with a PROXY header.
+type proxyConn struct {
+	net.Conn
+	r *bufio.Reader
+
+	once   sync.Once
+	remote net.Addr // client address from the header (nil: peer address)
+	err    error
+}
+
+// init reads the header once.
+func (c *proxyConn) init() {
+	c.once.Do(func() {
+		c.Conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+		c.remote, c.err = readProxyHeader(c.r)
+		c.Conn.SetReadDeadline(time.Time{})
+		if c.err != nil {
+			c.Conn.Close()
+		}
+	})
+}
+
+// Read reads after the header.
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.init()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+// RemoteAddr returns the client address from the header.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.init()
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyHeader parses a v1 or v2 header. It returns a nil address for
+// LOCAL and UNKNOWN connections.
+func readProxyHeader(r *bufio.Reader) (net.Addr, error) {
+	sig, err := r.Peek(len(proxyV2Sig))
+	if err == nil && bytes.Equal(sig, proxyV2Sig) {
+		return readProxyV2(r)
+	}
+	if sig, _ := r.Peek(6); string(sig) == "PROXY " {
+		return readProxyV1(r)
+	}
+	return nil, ErrProxyHeader
+}
+
+// readProxyV1 parses "PROXY TCP4|TCP6|UNKNOWN src dst sport dport\r\n".
+func readProxyV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < 107 { // maximum v1 header length
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, ErrProxyHeader
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	s, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, ErrProxyHeader
+	}
+
+	f := strings.Split(s, " ")
+	if len(f) >= 2 && f[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(f) != 6 || (f[1] != "TCP4" && f[1] != "TCP6") {
+		return nil, ErrProxyHeader
+	}
+	ip, err := netip.ParseAddr(f[2])
+	if err != nil {
+		return nil, ErrProxyHeader
+	}
+	port, err := strconv.ParseUint(f[4], 10, 16)
+	if err != nil {
+		return nil, ErrProxyHeader
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))), nil
+}
+
+// readProxyV2 parses a binary header (TLVs are skipped).
+func readProxyV2(r *bufio.Reader) (net.Addr, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, ErrProxyHeader
+	}
+	if hdr[12]>>4 != 2 {
+		return nil, ErrProxyHeader
+	}
+	body := make([]byte, binary.BigEndian.Uint16(hdr[14:16]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, ErrProxyHeader
+	}
+
+	switch hdr[12] & 0x0f {
+	case 0x0: // LOCAL
+		return nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, ErrProxyHeader
+	}
+
+	switch hdr[13] >> 4 {
+	case 0x1: // AF_INET
+		if len(body) < 12 {
+			return nil, ErrProxyHeader
+		}
+		ip := netip.AddrFrom4([4]byte(body[0:4]))
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(body[8:10]))), nil
+	case 0x2: // AF_INET6
+		if len(body) < 36 {
+			return nil, ErrProxyHeader
+		}
+		ip := netip.AddrFrom16([16]byte(body[0:16])).Unmap()
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(body[32:34]))), nil
+	}
+	// AF_UNSPEC, AF_UNIX: keep the peer address
+	return nil, nil
+}
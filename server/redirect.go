@@ -0,0 +1,91 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+HTTP to HTTPS redirects.
+
+Summary
+-------
+- NewRedirectServer returns a plain HTTP server (default port 80)
+  answering every request with 301 to the same host and path on the
+  HTTPS port.
+- With ServerConfig.RedirectHTTP set, the server starts it along with
+  the HTTPS listener, hands it over on Upgrade and shuts it down with
+  the server. It is skipped if TLS is off, and with ACME, whose
+  challenge listener redirects already.
+- With ServerConfig.ProxyProtocol the redirect listener reads PROXY
+  headers like the HTTPS listener.
+
+Example config:
+
+	"server": { "port": 443, "cert_file": "cert.pem", "key_file": "key.pem", "redirect_http": true }
+*/
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewRedirectServer creates an HTTP server on cfg.Host and
+// cfg.RedirectPort (default 80) redirecting to HTTPS on cfg.Port.
+func NewRedirectServer(cfg *ServerConfig) *http.Server {
+	port := cfg.RedirectPort
+	if port <= 0 {
+		port = 80
+	}
+	return &http.Server{
+		Addr:              net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		Handler:           RedirectHandler(cfg.Port),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// RedirectHandler redirects every request to the same URL with scheme
+// https on httpsPort (omitted for 443).
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// startRedirect starts the redirect server if configured.
+func (s *Server) startRedirect() error {
+	if !s.config.RedirectHTTP {
+		return nil
+	}
+	if !s.TLS() {
+		log.Println("redirect_http ignored: TLS is not enabled")
+		return nil
+	}
+	if s.acmeHTTP != nil {
+		return nil // the ACME listener redirects
+	}
+
+	rs := NewRedirectServer(s.config)
+	ln, err := s.listenTCP(rs.Addr)
+	if err != nil {
+		return err
+	}
+	ln = s.proxyListener(s.limitListener(ln, rs.Addr), rs.Addr)
+	s.redirectServer = rs
+	log.Printf("HTTPS redirects on %s", rs.Addr)
+	go func() {
+		if err := rs.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("redirect server error: %v", err)
+		}
+	}()
+	return nil
+}
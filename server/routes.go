@@ -0,0 +1,116 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Route registration with documentation metadata.
+
+Summary
+-------
+- Handle/HandleFunc register handlers on the server's ServeMux and record
+  the route in a registry.
+- Optional RouteDoc annotations (summary, tags, request/response types)
+  are kept with each route for documentation generators (e.g. OpenAPI).
+- Routes returns a snapshot of all registered routes in registration order.
+- Each route lists the middleware wrapping its handler (outermost first),
+  as far as the middleware describes itself (see middleware.Named). The
+  routes command, documentation generators and admin pages use this to
+  show which protections apply to which route.
+*/
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RouteDoc holds optional documentation for a route.
+// Request and Response are example values whose types describe the
+// request and success response bodies (nil means no body).
+type RouteDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Request     any
+	Response    any
+}
+
+// Route describes a registered route.
+type Route struct {
+	Pattern string   // ServeMux pattern as registered, e.g. "GET /api/notes/{id}"
+	Method  string   // HTTP method from the pattern; empty matches all methods
+	Path    string   // path part of the pattern
+	Doc     RouteDoc // optional documentation
+
+	// Middleware wrapping the handler, outermost first
+	Middleware []MiddlewareInfo
+}
+
+// MiddlewareInfo describes a middleware wrapping a route.
+type MiddlewareInfo struct {
+	Name   string `json:"name"`
+	Config any    `json:"config,omitempty"` // configuration in effect, JSON-serializable
+}
+
+// MiddlewareOf returns the middleware recorded on h, outermost first.
+// Handlers report them by implementing MiddlewareInfo() []MiddlewareInfo.
+func MiddlewareOf(h http.Handler) []MiddlewareInfo {
+	if d, ok := h.(interface{ MiddlewareInfo() []MiddlewareInfo }); ok {
+		return d.MiddlewareInfo()
+	}
+	return nil
+}
+
+// Handle registers handler for pattern on the server's ServeMux and
+// records the route together with the optional documentation.
+// It panics if the server has no ServeMux (see NewServerWithHandler).
+func (s *Server) Handle(pattern string, handler http.Handler, doc ...RouteDoc) {
+	if s.mux == nil {
+		panic("server: Handle " + pattern + ": server has no ServeMux (created with NewServerWithHandler)")
+	}
+	s.mux.Handle(pattern, handler)
+
+	r := parseRoute(pattern)
+	r.Middleware = MiddlewareOf(handler)
+	if len(doc) > 0 {
+		r.Doc = doc[0]
+	}
+
+	s.routesMu.Lock()
+	s.routes = append(s.routes, r)
+	s.routesMu.Unlock()
+}
+
+// HandleFunc registers fn for pattern; see Handle.
+func (s *Server) HandleFunc(pattern string, fn http.HandlerFunc, doc ...RouteDoc) {
+	s.Handle(pattern, fn, doc...)
+}
+
+// Routes returns all routes registered via Handle/HandleFunc in
+// registration order. Routes added directly to the mux are not included.
+func (s *Server) Routes() []Route {
+	s.routesMu.Lock()
+	defer s.routesMu.Unlock()
+
+	out := make([]Route, len(s.routes))
+	copy(out, s.routes)
+	return out
+}
+
+// parseRoute splits a ServeMux pattern ("[METHOD ][HOST]/PATH") into
+// method and path.
+func parseRoute(pattern string) Route {
+	r := Route{Pattern: pattern, Path: pattern}
+
+	if method, rest, ok := strings.Cut(pattern, " "); ok {
+		r.Method = method
+		r.Path = strings.TrimSpace(rest)
+	}
+
+	// Drop an optional host prefix
+	if i := strings.Index(r.Path, "/"); i > 0 {
+		r.Path = r.Path[i:]
+	}
+
+	return r
+}
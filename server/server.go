@@ -10,21 +10,53 @@ with typed configuration and graceful shutdown support.
 Summary
 -------
 - Defines a ServerConfig struct for JSON-serializable server settings.
-- Wraps http.Server together with a ServeMux for route registration.
-- Supports blocking start as well as managed run modes.
+- Wraps http.Server together with a ServeMux for route registration, or
+  any root http.Handler (NewServerWithHandler). Requests without a
+  route get the package's error pages (fallback.go).
+- Supports blocking and non-blocking start (StartAsync, with the bound
+  address in Addr) as well as managed run modes.
 - Implements graceful shutdown using OS signals and contexts.
 - Allows integration into larger applications via context-based lifecycle control.
+- Reloads configuration in place on SIGHUP via registered reload hooks.
+- Runs registered shutdown hooks (DB pools, caches, workers) after the
+  listeners have drained.
+- Optionally serves gRPC on the same or a separate port (grpc.go).
+- Runs registered startup checks before accepting traffic (startup.go)
+  and warmup hooks before reporting ready (warmup.go).
+- Caps concurrent connections in total and per IP (listener.go).
+- Serves HTTPS when a certificate is configured (tls.go) and redirects
+  plain HTTP to it (redirect.go).
+- Logs a summary of the effective configuration on start (summary.go).
+- Hands its sockets to a new process for zero-downtime restarts (upgrade.go).
+- Serves /healthz, /readyz and /livez with pluggable checks (probes.go).
+- Serves pprof, expvar and application debug endpoints on a separate
+  admin listener (admin.go).
+- Reports lifecycle states (Starting, Running, Draining, Stopped) to
+  OnStateChange callbacks (state.go).
+- Passes application dependencies to handlers through the base and
+  connection contexts (context.go).
+- Counts connections, in-flight requests and responses by status class
+  (stats.go).
 */
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"github.com/bennof/gobfwebservice/logging"
+	"github.com/bennof/gobfwebservice/server/health"
 )
 
 /* ---------- configuration ---------- */
@@ -36,6 +68,28 @@ type ServerConfig struct {
 	Port         int    `json:"port"`
 	ReadTimeout  int    `json:"read_timeout"`  // seconds
 	WriteTimeout int    `json:"write_timeout"` // seconds
+	GRPCPort     int    `json:"grpc_port"`     // separate gRPC port; 0 shares the HTTP port (see HandleGRPC)
+	H2C          bool   `json:"h2c,omitempty"` // accept HTTP/2 without TLS (prior knowledge), e.g. behind a reverse proxy
+
+	MaxConns      int `json:"max_conns"`        // concurrent connections in total; 0 means unlimited (see listener.go)
+	MaxConnsPerIP int `json:"max_conns_per_ip"` // concurrent connections per remote IP; 0 means unlimited
+
+	ProxyProtocol bool     `json:"proxy_protocol,omitempty"` // read PROXY v1/v2 headers from a load balancer (see proxyproto.go)
+	ProxyTrusted  []string `json:"proxy_trusted,omitempty"`  // networks (CIDR) sending PROXY headers; empty trusts every peer
+
+	CertFile string `json:"cert_file,omitempty"` // PEM certificate (chain); enables HTTPS (see tls.go)
+	KeyFile  string `json:"key_file,omitempty"`  // PEM private key for CertFile
+
+	RedirectHTTP bool `json:"redirect_http,omitempty"` // redirect plain HTTP to HTTPS (see redirect.go)
+	RedirectPort int  `json:"redirect_port,omitempty"` // port of the redirect listener (default 80)
+
+	ClientCAFile string `json:"client_ca_file,omitempty"` // PEM CA bundle verifying client certificates (mTLS)
+	ClientAuth   string `json:"client_auth,omitempty"`    // none, request, require, verify_if_given or require_and_verify (see tls.go)
+
+	ACME ACMEConfig `json:"acme"` // automatic certificates (see acme.go)
+
+	Health health.Config `json:"health"` // probe endpoints (see probes.go)
+	Admin  AdminConfig   `json:"admin"`  // pprof/expvar/debug listener (see admin.go)
 }
 
 /* ---------- server wrapper ---------- */
@@ -45,7 +99,61 @@ type ServerConfig struct {
 type Server struct {
 	config     *ServerConfig
 	httpServer *http.Server
-	mux        *http.ServeMux
+	mux        *http.ServeMux // nil if created with NewServerWithHandler
+	handler    http.Handler   // root handler: mux or the handler passed in
+	wrapped    bool           // handler is the mux wrapped by WrapMux (see fallback.go)
+
+	reloadMu    sync.Mutex
+	reloadHooks []func() error
+
+	shutdownMu    sync.Mutex
+	shutdownHooks []func(context.Context) error
+
+	warmupMu    sync.Mutex
+	warmupHooks []func(context.Context) error // see warmup.go
+
+	routesMu sync.Mutex
+	routes   []Route
+
+	grpcHandler http.Handler // optional gRPC handler (see HandleGRPC)
+	grpcServer  *http.Server // separate gRPC listener if GRPCPort is set
+
+	checksMu sync.Mutex
+	checks   []startupCheck // see AddStartupCheck
+
+	tlsHooks []func(*tls.Config)             // see ConfigureTLS
+	cert     atomic.Pointer[tls.Certificate] // loaded from CertFile/KeyFile
+
+	proxyTrusted []netip.Prefix // parsed ProxyTrusted
+
+	health *health.Checker // see probes.go
+	states stateMachine    // see state.go
+	stats  serverStats     // see stats.go
+
+	baseContext  func(net.Listener) context.Context                // see context.go
+	connContexts []func(context.Context, net.Conn) context.Context // see AddConnContext
+	provided     []func(context.Context) context.Context           // see Provide
+
+	adminMux    *http.ServeMux // nil if the admin listener is disabled (see admin.go)
+	adminServer *http.Server
+	adminMu     sync.Mutex
+	adminRoutes []string
+
+	acme     *acmeManager       // set if ACME is enabled
+	acmeHTTP *http.Server       // plain HTTP listener for challenges
+	acmeStop context.CancelFunc // stops certificate renewal
+
+	redirectServer *http.Server // see redirect.go
+
+	summaryMu sync.Mutex
+	summary   []logging.Field // application lines of the startup report
+
+	listenersMu sync.Mutex
+	listeners   map[string]*net.TCPListener // bound sockets by address (see Upgrade)
+	addr        net.Addr                    // bound HTTP address (see Addr)
+	limits      []*LimitListener            // see listener.go
+	handover    chan struct{}               // closed when a new process took over
+	handoverOne sync.Once
 }
 
 // NewServer creates a new Server instance using the provided configuration
@@ -54,26 +162,80 @@ func NewServer(cfg *ServerConfig, mux *http.ServeMux) (*Server, error) {
 	if mux == nil {
 		mux = http.NewServeMux()
 	}
+	return newServer(cfg, mux, mux)
+}
+
+// NewServerWithHandler creates a Server serving h, e.g. a third-party
+// router or a handler already wrapped in middleware. If h is a
+// *http.ServeMux it is used for Handle and Mux as with NewServer;
+// otherwise routes are registered on h directly, Mux returns nil and
+// Handle panics.
+func NewServerWithHandler(cfg *ServerConfig, h http.Handler) (*Server, error) {
+	if h == nil {
+		return nil, errors.New("server: nil handler")
+	}
+	mux, _ := h.(*http.ServeMux)
+	return newServer(cfg, mux, h)
+}
+
+// newServer creates a server serving root; mux is the ServeMux routes
+// are registered on (nil if root is not a ServeMux).
+func newServer(cfg *ServerConfig, mux *http.ServeMux, root http.Handler) (*Server, error) {
+	if err := checkTLSConfig(cfg); err != nil {
+		return nil, err
+	}
+	trusted, err := parseProxyTrusted(cfg.ProxyTrusted)
+	if err != nil {
+		return nil, err
+	}
 
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
+	wrapped := mux != nil && root == http.Handler(mux)
+	if wrapped {
+		root = WrapMux(mux) // themed 404 and 405 (see fallback.go)
+	}
+
 	s := &Server{
-		config: cfg,
-		mux:    mux,
-		httpServer: &http.Server{
-			Addr:         addr,
-			Handler:      mux,
-			ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
-			WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
-		},
+		config:       cfg,
+		mux:          mux,
+		handler:      root,
+		wrapped:      wrapped,
+		proxyTrusted: trusted,
+		health:       health.New(cfg.Health),
+		handover:     make(chan struct{}),
 	}
+	s.httpServer = &http.Server{
+		Addr:         addr,
+		Handler:      http.HandlerFunc(s.serveHTTP),
+		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+		BaseContext:  s.baseCtx,
+		ConnContext:  s.connCtx,
+		ConnState:    s.connState,
+	}
+	if cfg.H2C {
+		s.httpServer.Protocols = h2cProtocols()
+	}
+	if cfg.ACME.Enabled {
+		s.acme = newACMEManager(cfg.ACME)
+		if mux != nil {
+			mux.Handle("GET "+acmeChallengePrefix, s.acme.challengeHandler())
+		} else {
+			s.handler = s.acme.withChallenges(root)
+		}
+	}
+	s.mountProbes()
+	s.newAdmin()
+	s.OnStateChange(func(_, to State) { s.health.SetReady(to == StateRunning) })
 
 	return s, nil
 }
 
 /* ---------- accessors ---------- */
 
-// Mux returns the underlying ServeMux used for route registration.
+// Mux returns the underlying ServeMux used for route registration, or
+// nil if the server was created with a handler that is not a ServeMux.
 func (s *Server) Mux() *http.ServeMux {
 	return s.mux
 }
@@ -84,102 +246,359 @@ func (s *Server) Config() *ServerConfig {
 	return s.config
 }
 
+/* ---------- reload ---------- */
+
+// OnReload registers a callback executed by Reload, e.g. to re-read the
+// config file, reload templates or swap middleware settings in place.
+// Hooks run in registration order.
+func (s *Server) OnReload(fn func() error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	s.reloadHooks = append(s.reloadHooks, fn)
+}
+
+// Reload runs all registered reload hooks without interrupting the listener
+// or open connections. All hooks are executed; their errors are joined.
+// Run and RunWithContext call Reload on SIGHUP.
+func (s *Server) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	var errs []error
+	for _, fn := range s.reloadHooks {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reload runs Reload in response to SIGHUP and logs the outcome.
+func (s *Server) reload() {
+	log.Println("Received SIGHUP, reloading...")
+	if err := s.Reload(); err != nil {
+		log.Printf("reload failed: %v", err)
+		return
+	}
+	log.Println("Reload complete")
+}
+
+// OnShutdown registers a cleanup callback, e.g. to close a database pool
+// or stop background workers. Hooks run once, in registration order,
+// after the HTTP (and gRPC) server has shut down, so no request is still
+// using the resources. They receive the shutdown context; all hooks run
+// even if one fails or the context expired.
+func (s *Server) OnShutdown(fn func(ctx context.Context) error) {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, fn)
+}
+
+// runShutdownHooks runs and removes the registered shutdown hooks.
+func (s *Server) runShutdownHooks(ctx context.Context) error {
+	s.shutdownMu.Lock()
+	hooks := s.shutdownHooks
+	s.shutdownHooks = nil
+	s.shutdownMu.Unlock()
+
+	var errs []error
+	for _, fn := range hooks {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 /* ---------- lifecycle ---------- */
 
+// upgrade handles SIGUSR2: it reports whether a new process took over
+// and this one should shut down.
+func (s *Server) upgrade() bool {
+	log.Println("Received SIGUSR2, upgrading...")
+	if err := s.Upgrade(); err != nil {
+		log.Printf("upgrade failed: %v", err)
+		return false
+	}
+	return true
+}
+
 // Start starts the HTTP server and blocks until it stops.
 // This method does not handle graceful shutdown.
 func (s *Server) Start() error {
 	log.Printf("Starting server on %s", s.httpServer.Addr)
-	return s.httpServer.ListenAndServe()
+	return s.listenAndServe()
 }
 
 // Shutdown gracefully shuts down the server using the provided context.
+// Buffered log records are flushed once the server has stopped.
 func (s *Server) Shutdown(ctx context.Context) error {
 	log.Println("Shutting down server...")
-	return s.httpServer.Shutdown(ctx)
+	defer logging.Flush()
+	return s.shutdown(ctx)
 }
 
-// Run starts the server and installs OS signal handlers for graceful shutdown.
-// It listens for SIGINT and SIGTERM and shuts the server down with a fixed timeout.
-func (s *Server) Run() error {
-	// Channel to receive server runtime errors
-	serverErrors := make(chan error, 1)
+// StartAsync binds the listeners, starts serving in the background and
+// runs the warmup hooks. Once it returns, Addr reports the bound address
+// (useful with Port 0) and the server is ready. Startup errors (failed
+// checks, a port in use, a failed warmup) are returned directly; the
+// channel receives the result of serving, nil after Shutdown, and is
+// closed then.
+func (s *Server) StartAsync() (<-chan error, error) {
+	ln, err := s.start()
+	if err != nil {
+		return nil, err
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.serveMain(ln) }()
 
-	// Start server asynchronously
+	if err := s.warmup(); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return nil, errors.Join(err, s.shutdown(ctx))
+	}
+	notifyReady()
+	s.setState(StateRunning)
+
+	errc := make(chan error, 1)
 	go func() {
-		log.Printf("Server listening on %s", s.httpServer.Addr)
-		serverErrors <- s.httpServer.ListenAndServe()
+		defer close(errc)
+		if err := <-served; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errc <- err
+			return
+		}
+		errc <- nil
 	}()
+	return errc, nil
+}
 
-	// Setup signal handling for graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+// Addr returns the address of the bound HTTP listener, or nil before
+// the server was started.
+func (s *Server) Addr() net.Addr {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	return s.addr
+}
 
-	// Wait for either a server error or an OS shutdown signal
-	select {
-	case err := <-serverErrors:
-		if err != nil && err != http.ErrServerClosed {
-			return fmt.Errorf("server error: %w", err)
+// listenAndServe starts the server and serves HTTP until it is shut
+// down; it then returns http.ErrServerClosed.
+func (s *Server) listenAndServe() error {
+	errc, err := s.StartAsync()
+	if err != nil {
+		return err
+	}
+	if err := <-errc; err != nil {
+		return err
+	}
+	return http.ErrServerClosed
+}
+
+// start runs the startup checks, starts the optional gRPC listener and
+// binds the HTTP listener.
+func (s *Server) start() (_ net.Listener, err error) {
+	defer func() {
+		if err != nil {
+			s.setState(StateStopped) // the start failed
 		}
+	}()
 
-	case sig := <-quit:
-		log.Printf("Received signal: %v", sig)
+	if err := s.preflight(); err != nil {
+		return nil, err
+	}
+	if err := s.setupTLS(); err != nil {
+		return nil, err
+	}
+	if err := s.startACME(); err != nil {
+		return nil, err
+	}
+	if err := s.startRedirect(); err != nil {
+		return nil, fmt.Errorf("redirect listener: %w", err)
+	}
+	s.logSummary()
+	if err := s.startAdmin(); err != nil {
+		return nil, err
+	}
+	if g := s.grpcServer; g != nil {
+		ln, err := s.listen(g.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("grpc listener: %w", err)
+		}
+		log.Printf("gRPC listening on %s", g.Addr)
+		go func() {
+			if err := s.serve(g, ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("grpc server error: %v", err)
+			}
+		}()
+	}
 
-		// Create shutdown context with a fixed timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	ln, err := s.listen(s.httpServer.Addr)
+	if err != nil {
+		return nil, err
+	}
+	s.listenersMu.Lock()
+	s.addr = ln.Addr()
+	s.listenersMu.Unlock()
+	return ln, nil
+}
 
-		// Attempt graceful shutdown
-		if err := s.httpServer.Shutdown(ctx); err != nil {
-			return fmt.Errorf("server shutdown error: %w", err)
-		}
+// serveMain serves HTTP on ln until the server is shut down.
+func (s *Server) serveMain(ln net.Listener) error {
+	err := s.serve(s.httpServer, ln)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.setState(StateStopped)
+	}
+	return err
+}
 
-		log.Println("Server stopped gracefully")
+// serve serves srv on ln, with TLS if configured.
+func (s *Server) serve(srv *http.Server, ln net.Listener) error {
+	if srv.TLSConfig != nil {
+		return srv.ServeTLS(ln, "", "")
 	}
+	return srv.Serve(ln)
+}
 
-	return nil
+// shutdown gracefully stops the HTTP and the optional gRPC server and
+// then runs the shutdown hooks.
+func (s *Server) shutdown(ctx context.Context) error {
+	s.setState(StateDraining)
+	defer s.setState(StateStopped)
+
+	err := s.httpServer.Shutdown(ctx)
+	if s.grpcServer != nil {
+		err = errors.Join(err, s.grpcServer.Shutdown(ctx))
+	}
+	if s.acmeStop != nil {
+		s.acmeStop()
+	}
+	if s.acmeHTTP != nil {
+		err = errors.Join(err, s.acmeHTTP.Shutdown(ctx))
+	}
+	if s.redirectServer != nil {
+		err = errors.Join(err, s.redirectServer.Shutdown(ctx))
+	}
+	if s.adminServer != nil {
+		err = errors.Join(err, s.adminServer.Shutdown(ctx))
+	}
+	return errors.Join(err, s.runShutdownHooks(ctx))
 }
 
-// RunWithContext starts the server and shuts it down when either the given
-// context is cancelled or an OS shutdown signal is received.
-// The shutdown timeout is configurable.
-func (s *Server) RunWithContext(ctx context.Context, shutdownTimeout time.Duration) error {
-	// Channel to receive server runtime errors
-	serverErrors := make(chan error, 1)
+// Run starts the server and installs OS signal handlers for graceful shutdown.
+// It listens for SIGINT and SIGTERM and shuts the server down with a fixed timeout.
+// SIGHUP triggers Reload while the server keeps running; SIGUSR2 hands
+// the sockets to a new process (Upgrade) and then shuts down.
+func (s *Server) Run() error {
+	// Setup signal handling for graceful shutdown and reload
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+	defer signal.Stop(usr2)
 
 	// Start server asynchronously
-	go func() {
-		log.Printf("Server listening on %s", s.httpServer.Addr)
-		serverErrors <- s.httpServer.ListenAndServe()
-	}()
+	serverErrors, err := s.StartAsync()
+	if err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+	log.Printf("Server listening on %s", s.Addr())
 
-	// Setup signal handling for graceful shutdown
+	// Wait for either a server error or an OS shutdown signal
+	for {
+		select {
+		case err := <-serverErrors:
+			if err != nil {
+				return fmt.Errorf("server error: %w", err)
+			}
+			return nil
+
+		case <-hup:
+			s.reload()
+
+		case <-usr2:
+			if s.upgrade() {
+				return s.stop(30 * time.Second)
+			}
+
+		case sig := <-quit:
+			log.Printf("Received signal: %v", sig)
+			return s.stop(30 * time.Second)
+		}
+	}
+}
+
+// RunWithContext starts the server and shuts it down when either the given
+// context is cancelled or an OS shutdown signal is received.
+// The shutdown timeout is configurable. SIGHUP triggers Reload, SIGUSR2
+// an Upgrade followed by shutdown.
+func (s *Server) RunWithContext(ctx context.Context, shutdownTimeout time.Duration) error {
+	// Setup signal handling for graceful shutdown and reload
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
 
-	// Wait for server error, context cancellation, or OS signal
-	select {
-	case err := <-serverErrors:
-		if err != nil && err != http.ErrServerClosed {
-			return fmt.Errorf("server error: %w", err)
-		}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 
-	case <-ctx.Done():
-		log.Println("Context cancelled, shutting down...")
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+	defer signal.Stop(usr2)
 
-	case sig := <-quit:
-		log.Printf("Received signal: %v", sig)
+	// Start server asynchronously
+	serverErrors, err := s.StartAsync()
+	if err != nil {
+		return fmt.Errorf("server error: %w", err)
 	}
+	log.Printf("Server listening on %s", s.Addr())
+
+	// Wait for server error, context cancellation, or OS signal
+wait:
+	for {
+		select {
+		case err := <-serverErrors:
+			if err != nil {
+				return fmt.Errorf("server error: %w", err)
+			}
+			break wait
+
+		case <-hup:
+			s.reload()
+
+		case <-usr2:
+			if s.upgrade() {
+				break wait
+			}
+
+		case <-ctx.Done():
+			log.Println("Context cancelled, shutting down...")
+			break wait
+
+		case sig := <-quit:
+			log.Printf("Received signal: %v", sig)
+			break wait
+		}
+	}
+
+	return s.stop(shutdownTimeout)
+}
 
-	// Create shutdown context with the provided timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+// stop shuts the server down gracefully within timeout.
+func (s *Server) stop(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// Attempt graceful shutdown
-	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+	if err := s.shutdown(ctx); err != nil {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
 
 	log.Println("Server stopped gracefully")
+	_ = logging.Flush()
 	return nil
 }
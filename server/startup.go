@@ -0,0 +1,163 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Startup (preflight) checks.
+
+Summary
+-------
+- AddStartupCheck registers a named check (template parse, DB ping,
+  cache warm-up, JWKS fetch) that must pass before the listener accepts
+  traffic.
+- Checks run concurrently, each with its own timeout, when the server
+  starts (Start, Run, RunWithContext and lifecycle.Server). If one
+  fails, the server does not bind its port and the start returns a
+  *StartupError listing every check with its outcome and duration.
+- RunStartupChecks runs them on demand (e.g. for a --check flag).
+
+Typical usage:
+
+	srv.AddStartupCheck("db", db.PingContext)
+	srv.AddStartupCheck("jwks", jwks.Refresh, 5*time.Second)
+
+Example report:
+
+	startup checks: 1 of 2 failed
+	  ok    db (3ms)
+	  FAIL  jwks (5s): context deadline exceeded
+*/
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStartupCheckTimeout is used for checks registered without timeout.
+const DefaultStartupCheckTimeout = 10 * time.Second
+
+// startupCheck is a registered check.
+type startupCheck struct {
+	name    string
+	fn      func(ctx context.Context) error
+	timeout time.Duration
+}
+
+// CheckResult is the outcome of a single startup check.
+type CheckResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// StartupError is returned when at least one startup check failed.
+type StartupError struct {
+	Results []CheckResult // all checks in registration order
+}
+
+// Error returns the full report.
+func (e *StartupError) Error() string {
+	failed := 0
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "startup checks: %d of %d failed", failed, len(e.Results))
+	for _, r := range e.Results {
+		d := r.Duration.Round(time.Millisecond)
+		if r.Err != nil {
+			fmt.Fprintf(&b, "\n  FAIL  %s (%s): %v", r.Name, d, r.Err)
+		} else {
+			fmt.Fprintf(&b, "\n  ok    %s (%s)", r.Name, d)
+		}
+	}
+	return b.String()
+}
+
+// AddStartupCheck registers a check run before the server accepts
+// traffic. The optional timeout defaults to DefaultStartupCheckTimeout.
+func (s *Server) AddStartupCheck(name string, fn func(ctx context.Context) error, timeout ...time.Duration) {
+	t := DefaultStartupCheckTimeout
+	if len(timeout) > 0 && timeout[0] > 0 {
+		t = timeout[0]
+	}
+
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.checks = append(s.checks, startupCheck{name: name, fn: fn, timeout: t})
+}
+
+// RunStartupChecks runs all registered checks concurrently and returns
+// their results in registration order. The error is a *StartupError if
+// any check failed.
+func (s *Server) RunStartupChecks(ctx context.Context) ([]CheckResult, error) {
+	s.checksMu.Lock()
+	checks := append([]startupCheck(nil), s.checks...)
+	s.checksMu.Unlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			return results, &StartupError{Results: results}
+		}
+	}
+	return results, nil
+}
+
+// preflight runs the startup checks before the listener is bound.
+func (s *Server) preflight() error {
+	results, err := s.RunStartupChecks(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		log.Printf("Startup checks passed (%d)", len(results))
+	}
+	return nil
+}
+
+// runCheck runs a single check with its timeout and recovers panics.
+func runCheck(ctx context.Context, c startupCheck) (res CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	res.Name = c.name
+	defer func() { res.Duration = time.Since(start) }()
+
+	// Do not wait for checks ignoring their context beyond the timeout
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				errc <- fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		errc <- c.fn(ctx)
+	}()
+
+	select {
+	case res.Err = <-errc:
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Server lifecycle states.
+
+Summary
+-------
+- A server moves forward through Starting (created, startup checks, TLS
+  setup, warmup), Running (listener accepting, warmup done), Draining
+  (shutdown started, running requests finish) and Stopped (listeners
+  closed, shutdown hooks done, or the start failed). States never go
+  backwards; a server shut down before it ran skips Running.
+- OnStateChange registers callbacks run on every transition, in
+  registration order and synchronously: Draining is reported before the
+  listener stops accepting, so a callback can stop background work or
+  refuse new jobs. Callbacks must not block for long.
+- The readiness probe (/readyz, see probes.go) follows the state: it is
+  ready only while Running.
+
+Typical usage:
+
+	srv.OnStateChange(func(from, to server.State) {
+		if to == server.StateDraining {
+			queue.Pause()
+		}
+	})
+*/
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// State is a lifecycle state of a Server.
+type State int32
+
+// Lifecycle states in order.
+const (
+	StateStarting State = iota
+	StateRunning
+	StateDraining
+	StateStopped
+)
+
+// String returns the state name, e.g. "running".
+func (st State) String() string {
+	switch st {
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+// stateMachine holds the state and the callbacks of a Server.
+type stateMachine struct {
+	mu        sync.Mutex // serializes transitions and callbacks
+	state     atomic.Int32
+	callbacks []func(from, to State)
+}
+
+// State returns the current lifecycle state.
+func (s *Server) State() State {
+	return State(s.states.state.Load())
+}
+
+// OnStateChange registers a callback run on every state transition.
+func (s *Server) OnStateChange(fn func(from, to State)) {
+	s.states.mu.Lock()
+	defer s.states.mu.Unlock()
+	s.states.callbacks = append(s.states.callbacks, fn)
+}
+
+// setState moves the server to a later state and runs the callbacks. It
+// does nothing if the server already reached to or a later state.
+func (s *Server) setState(to State) {
+	m := &s.states
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	from := State(m.state.Load())
+	if to <= from {
+		return
+	}
+	m.state.Store(int32(to))
+	for _, fn := range m.callbacks {
+		fn(from, to)
+	}
+}
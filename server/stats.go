@@ -0,0 +1,149 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Server-level request statistics.
+
+Summary
+-------
+- The server counts open connections (HTTP and gRPC listener), requests
+  in flight and handled requests by status class, independent of any
+  middleware: probes, 404s and requests rejected by middleware count
+  as well.
+- Stats returns a snapshot, to be fed into any metrics backend (see
+  metrics.Server) or logged.
+- Hijacked requests (WebSockets) count as handled but in no status
+  class; a handler that writes nothing counts as 200.
+*/
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the server counters.
+type Stats struct {
+	Connections   int64  `json:"connections"`    // open connections
+	ConnsRejected uint64 `json:"conns_rejected"` // connections closed by MaxConnsPerIP (see listener.go)
+	InFlight      int64  `json:"in_flight"`      // requests being handled
+	Requests      uint64 `json:"requests"`       // requests handled
+	Status1xx     uint64 `json:"status_1xx"`
+	Status2xx     uint64 `json:"status_2xx"`
+	Status3xx     uint64 `json:"status_3xx"`
+	Status4xx     uint64 `json:"status_4xx"`
+	Status5xx     uint64 `json:"status_5xx"`
+}
+
+// serverStats holds the live counters.
+type serverStats struct {
+	conns    atomic.Int64
+	inFlight atomic.Int64
+	requests atomic.Uint64
+	classes  [6]atomic.Uint64 // by status / 100
+}
+
+// Stats returns a snapshot of the counters.
+func (s *Server) Stats() Stats {
+	st := &s.stats
+	return Stats{
+		Connections:   st.conns.Load(),
+		ConnsRejected: s.connsRejected(),
+		InFlight:      st.inFlight.Load(),
+		Requests:      st.requests.Load(),
+		Status1xx:     st.classes[1].Load(),
+		Status2xx:     st.classes[2].Load(),
+		Status3xx:     st.classes[3].Load(),
+		Status4xx:     st.classes[4].Load(),
+		Status5xx:     st.classes[5].Load(),
+	}
+}
+
+// connState implements http.Server.ConnState.
+func (s *Server) connState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		s.stats.conns.Add(1)
+	case http.StateHijacked, http.StateClosed:
+		s.stats.conns.Add(-1)
+	}
+}
+
+// countRequest serves r through h and counts it.
+func (s *Server) countRequest(h http.Handler, w http.ResponseWriter, r *http.Request) {
+	st := &s.stats
+	st.inFlight.Add(1)
+	sw := &statsWriter{ResponseWriter: w}
+	defer func() {
+		st.inFlight.Add(-1)
+		st.requests.Add(1)
+		code := sw.status
+		if code == 0 && !sw.hijacked {
+			code = http.StatusOK
+		}
+		if c := code / 100; c >= 1 && c <= 5 {
+			st.classes[c].Add(1)
+		}
+	}()
+	h.ServeHTTP(sw, r)
+}
+
+// statsWriter records the final status of a response.
+type statsWriter struct {
+	http.ResponseWriter
+	status   int
+	hijacked bool
+}
+
+// WriteHeader records the first final status.
+func (w *statsWriter) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records an implicit 200.
+func (w *statsWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// ReadFrom keeps sendfile for static files.
+func (w *statsWriter) ReadFrom(src io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+	return io.Copy(struct{ io.Writer }{w.ResponseWriter}, src)
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *statsWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker if the underlying writer does.
+func (w *statsWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, brw, err
+}
+
+// Unwrap returns the underlying writer (for http.ResponseController).
+func (w *statsWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,141 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Startup report.
+
+Summary
+-------
+- Before accepting traffic the server logs a summary of how it is
+  actually configured (logging.Summary): listen address and protocols,
+  TLS mode, gRPC, connection limits, PROXY protocol, health probes, the
+  number of routes and the middleware found on them (see
+  middleware.Named).
+- Applications append their own lines with AddSummary, e.g. the number
+  of templates or where the configuration came from.
+- Summary returns the same lines, e.g. for a "--check" run that does not
+  start the server.
+
+Example output:
+
+	Startup
+	  listen:        0.0.0.0:8080 (HTTP/1.1, h2c)
+	  tls:           off
+	  routes:        42
+	  middleware:    recovery, request-id, logging, cors, rate-limit
+	  templates:     17
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/logging"
+)
+
+// AddSummary appends a line to the startup report.
+func (s *Server) AddSummary(key, value string) {
+	s.summaryMu.Lock()
+	s.summary = append(s.summary, logging.Field{Key: key, Value: value})
+	s.summaryMu.Unlock()
+}
+
+// Summary returns the lines of the startup report.
+func (s *Server) Summary() []logging.Field {
+	cfg := s.config
+	var out []logging.Field
+	add := func(k, v string) { out = append(out, logging.Field{Key: k, Value: v}) }
+
+	listen := s.httpServer.Addr
+	if cfg.H2C {
+		listen += " (HTTP/1.1, h2c)"
+	}
+	add("listen", listen)
+
+	switch {
+	case cfg.ACME.Enabled:
+		add("tls", "acme "+strings.Join(cfg.ACME.Domains, ", "))
+	case cfg.CertFile != "":
+		add("tls", "certificate "+cfg.CertFile)
+	case s.TLS():
+		add("tls", "custom (ConfigureTLS)")
+	default:
+		add("tls", "off")
+	}
+	if rs := s.redirectServer; rs != nil {
+		add("http redirect", rs.Addr)
+	}
+	if mode := clientAuthMode(cfg); s.TLS() && mode != "none" {
+		add("client certs", strings.TrimSpace(mode+" "+cfg.ClientCAFile))
+	}
+
+	switch {
+	case s.grpcServer != nil:
+		add("grpc", s.grpcServer.Addr)
+	case s.grpcHandler != nil:
+		add("grpc", "shared with HTTP")
+	}
+	if cfg.MaxConns > 0 || cfg.MaxConnsPerIP > 0 {
+		add("connections", fmt.Sprintf("max %s, per IP %s", limit(cfg.MaxConns), limit(cfg.MaxConnsPerIP)))
+	}
+	if cfg.ProxyProtocol {
+		trusted := "any peer"
+		if len(cfg.ProxyTrusted) > 0 {
+			trusted = strings.Join(cfg.ProxyTrusted, ", ")
+		}
+		add("proxy protocol", "trusted "+trusted)
+	}
+	if a := s.adminServer; a != nil {
+		auth := "no token"
+		if cfg.Admin.Token != "" {
+			auth = "token"
+		}
+		add("admin", a.Addr+" ("+auth+")")
+	}
+	if cfg.Health.Enabled {
+		add("health", "/livez, /readyz, /healthz")
+	}
+
+	routes := s.Routes()
+	add("routes", strconv.Itoa(len(routes)))
+	if names := middlewareNames(routes); len(names) > 0 {
+		add("middleware", strings.Join(names, ", "))
+	}
+
+	s.summaryMu.Lock()
+	out = append(out, s.summary...)
+	s.summaryMu.Unlock()
+	return out
+}
+
+// logSummary logs the startup report.
+func (s *Server) logSummary() {
+	logging.Summary("Startup", s.Summary())
+}
+
+// middlewareNames returns the distinct middleware of routes in order of
+// first appearance.
+func middlewareNames(routes []Route) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, r := range routes {
+		for _, m := range r.Middleware {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				names = append(names, m.Name)
+			}
+		}
+	}
+	return names
+}
+
+// limit formats a connection limit (0 is unlimited).
+func limit(n int) string {
+	if n <= 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(n)
+}
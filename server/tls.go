@@ -0,0 +1,184 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+HTTPS support.
+
+Summary
+-------
+- Setting ServerConfig.CertFile and KeyFile (PEM) serves HTTPS on the
+  configured port; the separate gRPC port (if any) uses TLS as well.
+- The certificate is loaded before the listener opens, so a missing or
+  invalid file fails Start/Run immediately, and reloaded on SIGHUP
+  (see Reload): renewed certificates apply without a restart.
+- ConfigureTLS adjusts the tls.Config (cipher suites, client
+  certificates, a custom GetCertificate ...). If the hook provides
+  certificates itself, CertFile and KeyFile may stay empty.
+- Alternatively ServerConfig.ACME obtains certificates automatically
+  (see acme.go).
+- The default configuration requires TLS 1.2 or newer.
+- Client certificates (mTLS) for service-to-service APIs: ClientCAFile
+  names the CAs clients must be signed by and ClientAuth the policy:
+
+	none                 do not ask for a certificate (default without ClientCAFile)
+	request              ask, accept none or any certificate unverified
+	require              require any certificate, unverified
+	verify_if_given      verify a certificate if one is sent
+	require_and_verify   require a verified certificate (default with ClientCAFile)
+
+  The CA bundle is read at start. middleware.ClientCert puts the
+  verified certificate into the request context.
+
+For local testing, "servercli cert" creates a self-signed pair:
+
+	"server": { "port": 8443, "cert_file": "cert.pem", "key_file": "key.pem" }
+*/
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// clientAuthTypes maps ServerConfig.ClientAuth to the tls policies.
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// ConfigureTLS registers fn to adjust the TLS configuration before the
+// server starts. Calling it enables TLS even without CertFile/KeyFile,
+// provided fn sets Certificates or GetCertificate.
+func (s *Server) ConfigureTLS(fn func(cfg *tls.Config)) {
+	s.tlsHooks = append(s.tlsHooks, fn)
+}
+
+// TLS reports whether the server serves HTTPS.
+func (s *Server) TLS() bool {
+	return s.config.CertFile != "" || s.config.ACME.Enabled || len(s.tlsHooks) > 0
+}
+
+// checkTLSConfig validates the certificate settings of cfg.
+func checkTLSConfig(cfg *ServerConfig) error {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return errors.New("server: cert_file and key_file must be set together")
+	}
+	if _, err := clientAuth(cfg); err != nil {
+		return err
+	}
+	return checkACMEConfig(cfg)
+}
+
+// clientAuthMode returns ClientAuth or its default.
+func clientAuthMode(cfg *ServerConfig) string {
+	switch {
+	case cfg.ClientAuth != "":
+		return cfg.ClientAuth
+	case cfg.ClientCAFile != "":
+		return "require_and_verify"
+	}
+	return "none"
+}
+
+// clientAuth returns the client certificate policy of cfg.
+func clientAuth(cfg *ServerConfig) (tls.ClientAuthType, error) {
+	mode := clientAuthMode(cfg)
+	t, ok := clientAuthTypes[mode]
+	if !ok {
+		return 0, fmt.Errorf("server: unknown client_auth %q", mode)
+	}
+	if (t == tls.VerifyClientCertIfGiven || t == tls.RequireAndVerifyClientCert) && cfg.ClientCAFile == "" {
+		return 0, fmt.Errorf("server: client_auth %q needs client_ca_file", mode)
+	}
+	return t, nil
+}
+
+// setupTLS builds the TLS configuration and loads the certificate.
+// It does nothing if TLS is not enabled.
+func (s *Server) setupTLS() error {
+	if !s.TLS() {
+		return nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.config.CertFile != "" {
+		if err := s.loadCertificate(); err != nil {
+			return err
+		}
+		cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.cert.Load(), nil
+		}
+		s.OnReload(s.loadCertificate)
+	}
+	if s.acme != nil {
+		cfg.GetCertificate = s.acme.getCertificate
+	}
+	if err := s.setupClientAuth(cfg); err != nil {
+		return err
+	}
+	for _, fn := range s.tlsHooks {
+		fn(cfg)
+	}
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil && cfg.GetConfigForClient == nil {
+		return errors.New("server: TLS enabled but no certificate configured")
+	}
+
+	s.httpServer.TLSConfig = cfg
+	if s.grpcServer != nil {
+		s.grpcServer.TLSConfig = cfg
+	}
+	return nil
+}
+
+// loadCertificate (re)loads CertFile and KeyFile.
+func (s *Server) loadCertificate() error {
+	cert, err := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("server: load certificate: %w", err)
+	}
+	if s.cert.Swap(&cert) != nil {
+		log.Printf("TLS certificate reloaded from %s", s.config.CertFile)
+	}
+	return nil
+}
+
+// setupClientAuth sets the client certificate policy of cfg and loads
+// ClientCAFile; hooks may still override both.
+func (s *Server) setupClientAuth(cfg *tls.Config) error {
+	auth, err := clientAuth(s.config)
+	if err != nil {
+		return err
+	}
+	cfg.ClientAuth = auth
+	if s.config.ClientCAFile == "" {
+		return nil
+	}
+
+	pool, err := loadClientCAs(s.config.ClientCAFile)
+	if err != nil {
+		return err
+	}
+	cfg.ClientCAs = pool
+	return nil
+}
+
+// loadClientCAs reads a PEM CA bundle.
+func loadClientCAs(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("server: load client CAs: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("server: load client CAs: no certificates in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,248 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Zero-downtime restarts by passing the listening sockets to a new process.
+
+Summary
+-------
+- Upgrade starts the current executable again (after a deploy: the new
+  binary at the same path) with the same arguments and hands it the
+  listening sockets as inherited file descriptors. The kernel keeps
+  accepting connections into the shared socket the whole time, so no
+  connection is refused.
+- The new process picks up inherited sockets in listen instead of
+  binding again and reports readiness once all its listeners are up
+  (startup checks passed, TLS loaded). The old process then stops
+  accepting, so queued connections go to the new one, and Upgrade
+  returns after a short grace period; the caller shuts down gracefully
+  and finishes the running requests. (net/http drops connections whose
+  first request arrives after Shutdown started; the grace period lets
+  the last accepted ones get there first.)
+- If the new process fails to start or exits before it is ready, it is
+  killed and Upgrade returns an error: the old process keeps serving.
+- Run and RunWithContext upgrade on SIGUSR2. With lifecycle.Manager,
+  call Upgrade from a SIGUSR2 handler and cancel the manager's context.
+- Sockets are matched by address: if the new config listens elsewhere,
+  the new process binds normally. Unix only.
+
+Deploy:
+
+	cp servercli.new /usr/local/bin/servercli
+	kill -USR2 $(pidof servercli)
+*/
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment of a process started by Upgrade.
+const (
+	envListenFDs    = "SERVER_LISTEN_FDS"    // inherited sockets: "addr=fd,addr=fd"
+	envUpgradeReady = "SERVER_UPGRADE_READY" // pipe to report readiness on
+)
+
+// upgradeTimeout bounds how long Upgrade waits for the new process.
+const upgradeTimeout = 60 * time.Second
+
+// handoverGrace is the time between handing over the sockets and
+// returning from Upgrade (see above).
+const handoverGrace = 500 * time.Millisecond
+
+// inherited holds the sockets passed by the parent process, by address.
+var inherited = sync.OnceValue(func() map[string]*os.File {
+	files := map[string]*os.File{}
+	for _, e := range strings.Split(os.Getenv(envListenFDs), ",") {
+		addr, fd, ok := strings.Cut(e, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			continue
+		}
+		files[addr] = os.NewFile(uintptr(n), "listener "+addr)
+	}
+	os.Unsetenv(envListenFDs)
+	return files
+})
+
+// listenTCP returns the inherited socket for addr or binds a new one and
+// records it for Upgrade.
+func (s *Server) listenTCP(addr string) (net.Listener, error) {
+	var (
+		ln  net.Listener
+		err error
+	)
+	if f, ok := inherited()[addr]; ok {
+		ln, err = net.FileListener(f)
+		f.Close()
+		if err == nil {
+			log.Printf("Using inherited listener on %s", addr)
+		}
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tl, ok := ln.(*net.TCPListener)
+	if !ok {
+		return ln, nil
+	}
+	s.listenersMu.Lock()
+	if s.listeners == nil {
+		s.listeners = map[string]*net.TCPListener{}
+	}
+	s.listeners[addr] = tl
+	s.listenersMu.Unlock()
+	return &handoverListener{Listener: tl, handover: s.handover, done: make(chan struct{})}, nil
+}
+
+// handoverListener stops accepting once the socket was handed over.
+type handoverListener struct {
+	net.Listener
+	handover <-chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Accept returns the next connection until the socket was handed over;
+// then it blocks until the listener is closed.
+func (l *handoverListener) Accept() (net.Conn, error) {
+	select {
+	case <-l.handover:
+		<-l.done
+		return nil, net.ErrClosed
+	default:
+	}
+	return l.Listener.Accept()
+}
+
+// Close closes the listener and releases a blocked Accept.
+func (l *handoverListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+// notifyReady tells the parent process that all listeners are serving.
+// It does nothing if the process was not started by Upgrade.
+func notifyReady() {
+	fd, err := strconv.Atoi(os.Getenv(envUpgradeReady))
+	if err != nil {
+		return
+	}
+	os.Unsetenv(envUpgradeReady)
+
+	f := os.NewFile(uintptr(fd), "upgrade ready")
+	f.Write([]byte{1})
+	f.Close()
+}
+
+// Upgrade starts a new process taking over the listening sockets and
+// waits until it serves. The caller should then shut this server down
+// gracefully (Run and RunWithContext do so on SIGUSR2).
+func (s *Server) Upgrade() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("server: upgrade: %w", err)
+	}
+
+	s.listenersMu.Lock()
+	var (
+		files []*os.File
+		fds   []string
+	)
+	for addr, ln := range s.listeners {
+		f, err := ln.File()
+		if err != nil {
+			s.listenersMu.Unlock()
+			closeFiles(files)
+			return fmt.Errorf("server: upgrade: %s: %w", addr, err)
+		}
+		files = append(files, f)
+		fds = append(fds, fmt.Sprintf("%s=%d", addr, 2+len(files))) // ExtraFiles start at fd 3
+	}
+	s.listenersMu.Unlock()
+	defer closeFiles(files)
+
+	if len(files) == 0 {
+		return errors.New("server: upgrade: not listening")
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("server: upgrade: %w", err)
+	}
+	defer ready.Close()
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(upgradeEnv(),
+		envListenFDs+"="+strings.Join(fds, ","),
+		envUpgradeReady+"="+strconv.Itoa(3+len(files)),
+	)
+
+	log.Printf("Upgrade: starting %s", exe)
+	err = cmd.Start()
+	readyW.Close()
+	if err != nil {
+		return fmt.Errorf("server: upgrade: %w", err)
+	}
+
+	// The pipe reports readiness (one byte) or EOF if the child exits
+	done := make(chan bool, 1)
+	go func() {
+		var b [1]byte
+		n, _ := io.ReadFull(ready, b[:])
+		done <- n == 1
+	}()
+
+	select {
+	case ok := <-done:
+		if ok {
+			log.Printf("Upgrade: new process %d is serving", cmd.Process.Pid)
+			go cmd.Wait()
+			s.handoverOne.Do(func() { close(s.handover) })
+			time.Sleep(handoverGrace)
+			return nil
+		}
+	case <-time.After(upgradeTimeout):
+	}
+
+	cmd.Process.Kill()
+	cmd.Wait()
+	return errors.New("server: upgrade: new process did not become ready")
+}
+
+// upgradeEnv returns the environment without upgrade variables.
+func upgradeEnv() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, envListenFDs+"=") && !strings.HasPrefix(e, envUpgradeReady+"=") {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+// closeFiles closes duplicated socket files.
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Warmup hooks.
+
+Summary
+-------
+- OnWarmup registers callbacks (template preloading, cache priming,
+  connection pool filling) run once the listeners accept connections,
+  but before the server reports itself ready: the state stays Starting,
+  /livez passes while /readyz fails, Run has not logged "listening" and
+  a parent process of Upgrade keeps serving.
+- Hooks run in registration order with DefaultWarmupTimeout each, the
+  server goes Running after the last one.
+- If a hook fails, the server is shut down again and the start returns
+  the error. Unlike startup checks (startup.go), which must pass before
+  the port is bound, warmup may take long without failing liveness
+  probes.
+
+Typical usage:
+
+	srv.OnWarmup(func(ctx context.Context) error {
+		return cache.Prime(ctx, db)
+	})
+*/
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultWarmupTimeout limits a single warmup hook.
+const DefaultWarmupTimeout = 60 * time.Second
+
+// OnWarmup registers fn to run after the listeners are bound and before
+// the server becomes ready. Register hooks before the server is started.
+func (s *Server) OnWarmup(fn func(ctx context.Context) error) {
+	s.warmupMu.Lock()
+	defer s.warmupMu.Unlock()
+	s.warmupHooks = append(s.warmupHooks, fn)
+}
+
+// warmup runs the warmup hooks in order and stops at the first error.
+func (s *Server) warmup() error {
+	s.warmupMu.Lock()
+	hooks := s.warmupHooks
+	s.warmupMu.Unlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	for i, fn := range hooks {
+		if err := runWarmup(fn); err != nil {
+			return fmt.Errorf("warmup hook %d: %w", i+1, err)
+		}
+	}
+	log.Printf("Warmup done (%d) in %s", len(hooks), time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// runWarmup runs fn with DefaultWarmupTimeout and recovers a panic.
+func runWarmup(fn func(ctx context.Context) error) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultWarmupTimeout)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,141 @@
+package static
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package static serves static files and single-page app (SPA) builds.
+
+Summary
+-------
+- Serves files from a directory without directory listings.
+- SPA mode falls back to index.html for unknown paths without a file
+  extension (client-side routing / history API), except for excluded
+  prefixes such as /api/.
+- Fingerprinted assets (e.g. app.3f9a1c2b.js, chunk-5d41402a.css) are served
+  with long-lived immutable caching; index.html is always revalidated.
+- GET and HEAD only; HEAD responses carry the headers of GET (including
+  Content-Length) without a body.
+- Missing files are rendered through the server error helpers.
+- Configured via a JSON-serializable Config struct.
+
+Typical usage:
+
+	mux.Handle("/", static.Handler(static.Config{Dir: "web/dist", SPA: true}))
+*/
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Config defines the configuration of the static file handler.
+// All fields are JSON-serializable and intended to be part of a global app config.
+type Config struct {
+	Dir             string   `json:"dir"`               // Directory to serve
+	Index           string   `json:"index"`             // Index file name for directories and SPA fallback
+	SPA             bool     `json:"spa"`               // Fall back to the index file for unknown routes
+	SPAExclude      []string `json:"spa_exclude"`       // Path prefixes that never fall back (e.g. "/api/")
+	MaxAge          int      `json:"max_age"`           // Cache max-age in seconds for regular files
+	ImmutableMaxAge int      `json:"immutable_max_age"` // Cache max-age in seconds for fingerprinted assets
+}
+
+// DefaultConfig returns a default configuration serving ./static.
+func DefaultConfig() Config {
+	return Config{
+		Dir:             "static",
+		Index:           "index.html",
+		SPA:             false,
+		SPAExclude:      []string{"/api/"},
+		MaxAge:          300,
+		ImmutableMaxAge: 31536000,
+	}
+}
+
+// hashedAsset matches file names containing a hex content hash segment,
+// e.g. "app.3f9a1c2b.js" or "chunk-5d41402abc.css". Words such as
+// "app-settings.js" or "jquery.validate.js" are not fingerprints.
+var hashedAsset = regexp.MustCompile(`[.-][0-9a-f]{8,}\.[a-z0-9]+$`)
+
+// Handler creates a static file handler using the provided configuration.
+// If no configuration is supplied, DefaultConfig() is used.
+func Handler(cfg ...Config) http.Handler {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Index == "" {
+		c.Index = "index.html"
+	}
+
+	fsys := os.DirFS(c.Dir)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			server.MethodNotAllowed(w, r)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "."
+		}
+
+		info, err := fs.Stat(fsys, name)
+		if err == nil && info.IsDir() {
+			name = path.Join(name, c.Index)
+			info, err = fs.Stat(fsys, name)
+		}
+
+		switch {
+		case err == nil:
+			serveFile(w, r, fsys, name, c)
+
+		case errors.Is(err, fs.ErrNotExist) && fallback(r.URL.Path, c):
+			serveFile(w, r, fsys, c.Index, c)
+
+		default:
+			server.NotFound(w, r)
+		}
+	})
+}
+
+// fallback reports whether an unknown path should serve the SPA index.
+func fallback(p string, c Config) bool {
+	if !c.SPA {
+		return false
+	}
+	for _, prefix := range c.SPAExclude {
+		if strings.HasPrefix(p, prefix) {
+			return false
+		}
+	}
+	// Missing assets (with extension) stay 404s
+	ext := path.Ext(p)
+	return ext == "" || ext == ".html"
+}
+
+// serveFile writes name with caching headers derived from its file name.
+func serveFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string, c Config) {
+	base := path.Base(name)
+
+	switch {
+	case base == c.Index:
+		w.Header().Set("Cache-Control", "no-cache")
+	case hashedAsset.MatchString(base) && c.ImmutableMaxAge > 0:
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(c.ImmutableMaxAge)+", immutable")
+	case c.MaxAge > 0:
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(c.MaxAge))
+	}
+
+	http.ServeFileFS(w, r, fsys, name)
+}
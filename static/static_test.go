@@ -0,0 +1,30 @@
+package static
+
+import "testing"
+
+// TestHashedAsset checks which file names get immutable caching.
+func TestHashedAsset(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"app.3f9a1c2b.js", true},
+		{"chunk-5d41402abc.css", true},
+		{"app.3f9a1c2b4d.css", true},
+		{"logo.0123456789abcdef.svg", true},
+		{"app.js", false},
+		{"index.html", false},
+		{"app-settings.js", false},
+		{"jquery.validate.js", false},
+		{"bootstrap-datepicker.css", false},
+		{"dashboard-overview.html", false},
+		{"app.3f9a1c2.js", false},   // too short
+		{"app.3F9A1C2B.js", false},  // not lowercase hex
+		{"app.3f9a1c2bx.js", false}, // not hex
+	}
+	for _, tt := range tests {
+		if got := hashedAsset.MatchString(tt.name); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package storage
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Local disk backend.
+
+Summary
+-------
+- Stores objects as files below LocalConfig.Dir; writes go to a temporary
+  file that is renamed into place (no partially written objects).
+- Presigned URLs are BaseURL/<key>?expires=<unix>&sig=<hmac> signed with
+  LocalConfig.Secret and served by Handler. With LocalConfig.Keys the
+  active key of the ring signs and its ID is added as "kid", so the
+  secret can be rotated while issued URLs stay valid.
+*/
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/keys"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// LocalConfig defines the configuration of the local disk backend.
+type LocalConfig struct {
+	Dir     string `json:"dir"`      // storage root
+	BaseURL string `json:"base_url"` // URL prefix Handler is mounted at, e.g. "/files"
+	Secret  string `json:"secret"`   // HMAC key for presigned URLs
+
+	// Keys replaces Secret with a rotating key ring (see package keys).
+	Keys *keys.Ring `json:"-"`
+}
+
+// DefaultLocalConfig returns a configuration storing files in ./uploads.
+func DefaultLocalConfig() LocalConfig {
+	return LocalConfig{
+		Dir:     "uploads",
+		BaseURL: "/files",
+		Secret:  "",
+	}
+}
+
+// Local stores objects on the local filesystem.
+type Local struct {
+	config LocalConfig
+}
+
+// NewLocal creates a local backend. Directories are created on the
+// first Put.
+func NewLocal(cfg LocalConfig) (*Local, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("storage: local dir not configured")
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	if cfg.Keys == nil && cfg.Secret != "" {
+		cfg.Keys = keys.Static(cfg.Secret)
+	}
+	return &Local{config: cfg}, nil
+}
+
+// Put writes an object atomically.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	target := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return Object{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	r, ct := detect(key, r, opts.ContentType)
+	n, err := io.Copy(tmp, readerCtx{ctx, r})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return Object{}, err
+	}
+	return Object{Key: key, Size: n, ContentType: ct, Modified: time.Now()}, nil
+}
+
+// Get opens an object file.
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	f, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	st, err := f.Stat()
+	if err != nil || st.IsDir() {
+		f.Close()
+		return nil, Object{}, ErrNotFound
+	}
+
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(f, head)
+		ct = http.DetectContentType(head[:n])
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			f.Close()
+			return nil, Object{}, err
+		}
+	}
+
+	return f, Object{Key: key, Size: st.Size(), ContentType: ct, Modified: st.ModTime()}, nil
+}
+
+// Delete removes an object file.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(l.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PresignGet returns a signed URL served by Handler.
+func (l *Local) PresignGet(key string, ttl time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	ring := l.config.Keys
+	if ring == nil {
+		return "", errors.New("storage: local secret not configured")
+	}
+
+	exp := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	kid, sig, err := ring.Sign(signData(key, exp))
+	if err != nil {
+		return "", fmt.Errorf("storage: %w", err)
+	}
+	q := url.Values{"expires": {exp}, "sig": {hex.EncodeToString(sig)}}
+	if kid != "" {
+		q.Set("kid", kid)
+	}
+	return l.config.BaseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + q.Encode(), nil
+}
+
+// Handler serves objects for presigned URLs. Mount it below BaseURL:
+//
+//	mux.Handle("GET /files/", http.StripPrefix("/files", store.Handler()))
+func (l *Local) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := CleanKey(r.URL.Path)
+		if err != nil {
+			server.NotFound(w, r)
+			return
+		}
+
+		q := r.URL.Query()
+		exp := q.Get("expires")
+		sig, serr := hex.DecodeString(q.Get("sig"))
+		unix, err := strconv.ParseInt(exp, 10, 64)
+		ring := l.config.Keys
+		if err != nil || serr != nil || ring == nil ||
+			!ring.Verify(q.Get("kid"), signData(key, exp), sig) {
+			server.Forbidden(w, r)
+			return
+		}
+		if time.Now().Unix() > unix {
+			server.Forbidden(w, r)
+			return
+		}
+
+		rc, obj, err := l.Get(r.Context(), key)
+		if errors.Is(err, ErrNotFound) {
+			server.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("storage: %v", err)
+			server.InternalServerError(w, r)
+			return
+		}
+		defer rc.Close()
+
+		w.Header().Set("Content-Type", obj.ContentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		// Uploaded HTML/SVG must not run scripts in our origin
+		w.Header().Set("Content-Security-Policy", "sandbox")
+		http.ServeContent(w, r, path.Base(key), obj.Modified, rc.(io.ReadSeeker))
+	})
+}
+
+// signData returns the signed part of a presigned URL.
+func signData(key, exp string) []byte {
+	return []byte(key + "\n" + exp)
+}
+
+// path maps a clean key to a file path.
+func (l *Local) path(key string) string {
+	return filepath.Join(l.config.Dir, filepath.FromSlash(key))
+}
+
+// readerCtx aborts reads once ctx is done.
+type readerCtx struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r readerCtx) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
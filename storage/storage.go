@@ -0,0 +1,132 @@
+package storage
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package storage abstracts object storage for uploaded files.
+
+Summary
+-------
+- Storage is implemented by a local disk backend (local.go) and an
+  S3-compatible backend (s3.go: AWS S3, MinIO, Ceph, ...).
+- Objects are addressed by slash-separated keys ("avatars/42.png").
+- Put detects the content type from the key extension and, failing
+  that, from the first 512 bytes of content.
+- PresignGet returns a time-limited download URL: S3 presigned URLs or
+  HMAC-signed URLs served by Local.Handler.
+- New selects the backend from the JSON configuration.
+
+Typical usage:
+
+	store, err := storage.New(cfg.Storage)
+	obj, err := store.Put(ctx, "uploads/"+name, file, storage.PutOptions{Size: header.Size})
+	url, err := store.PresignGet(obj.Key, 15*time.Minute)
+*/
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Errors returned by all backends.
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Object describes a stored object.
+type Object struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	Modified    time.Time `json:"modified"`
+}
+
+// PutOptions are optional parameters of Put.
+type PutOptions struct {
+	ContentType string // detected if empty
+	Size        int64  // content length if known; <= 0 means unknown
+}
+
+// Storage is an object store.
+type Storage interface {
+	// Put stores the content of r under key, replacing existing objects.
+	Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error)
+	// Get opens an object. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL allowing anyone to download key until ttl expires.
+	PresignGet(key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Backend string      `json:"backend"` // "local" or "s3"
+	Local   LocalConfig `json:"local"`
+	S3      S3Config    `json:"s3"`
+}
+
+// DefaultConfig returns a local disk configuration.
+func DefaultConfig() Config {
+	return Config{
+		Backend: "local",
+		Local:   DefaultLocalConfig(),
+		S3:      DefaultS3Config(),
+	}
+}
+
+// New creates the configured backend.
+func New(cfg ...Config) (Storage, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	switch c.Backend {
+	case "", "local":
+		return NewLocal(c.Local)
+	case "s3":
+		return NewS3(c.S3)
+	}
+	return nil, fmt.Errorf("storage: unknown backend %q", c.Backend)
+}
+
+// CleanKey validates and normalizes an object key. Keys must be
+// relative, must not contain ".." segments and must not be empty.
+func CleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/")
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
+
+// detect returns r (with sniffed bytes preserved) and its content type.
+// The key extension takes precedence over content sniffing.
+func detect(key string, r io.Reader, contentType string) (io.Reader, string) {
+	if contentType != "" {
+		return r, contentType
+	}
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		return r, ct
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, _ := br.Peek(512)
+	return br, http.DetectContentType(head)
+}
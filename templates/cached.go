@@ -0,0 +1,119 @@
+package templates
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bennof/gobfwebservice/cache"
+)
+
+// AutoKey makes RenderCache.RenderCached derive the cache key from the
+// template name and the data (see DataKey).
+const AutoKey = ""
+
+// RenderCache caches the output of a TemplateSet in a cache.Cache.
+// Concurrent misses for the same key render once. Reloading the set
+// invalidates all entries, so with auto reload (development) nothing
+// is served from the cache.
+//
+// Example:
+//
+//	views := templates.NewRenderCache(tplSet, cache.NewMemory[[]byte](), time.Minute)
+//
+//	// explicit key
+//	views.RenderCached(ctx, w, "note:"+id, "note.html", note)
+//
+//	// key derived from the data: identical renders are deduplicated
+//	views.RenderCached(ctx, w, templates.AutoKey, "list.html", notes)
+type RenderCache struct {
+	set   *TemplateSet
+	pages *cache.Loader[[]byte]
+	ttl   time.Duration
+}
+
+// NewRenderCache creates a cache for the output of ts, storing entries
+// in c for ttl (0 uses the cache's default TTL).
+func NewRenderCache(ts *TemplateSet, c cache.Cache[[]byte], ttl time.Duration) *RenderCache {
+	return &RenderCache{set: ts, pages: cache.NewLoader(c), ttl: ttl}
+}
+
+// RenderCached writes the output of template name rendered with data to
+// w, rendering only if key is not cached. With AutoKey the key is
+// DataKey(name, data); if data cannot be encoded the template is
+// rendered without the cache.
+//
+// The output must depend on name and data only: templates using
+// request-specific functions (e.g. CSRF tokens) must not be cached.
+func (rc *RenderCache) RenderCached(ctx context.Context, w http.ResponseWriter, key, name string, data interface{}) error {
+	if key == AutoKey {
+		k, err := rc.DataKey(name, data)
+		if err != nil {
+			log.Printf("render cache: %s: %v (rendering uncached)", name, err)
+			return rc.set.Render(w, name, data)
+		}
+		key = k
+	} else {
+		key = fmt.Sprintf("tpl:%d:%s", rc.generation(), key)
+	}
+
+	body, err := rc.pages.Get(ctx, key, rc.ttl, func(context.Context) ([]byte, error) {
+		buf, err := rc.set.RenderToBytes(name, data)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	_, err = w.Write(body)
+	return err
+}
+
+// DataKey returns the cache key of rendering name with data: the
+// SHA-256 of the template name and the JSON encoding of data, which is
+// canonical for maps (sorted keys) and structs (field order). Unexported
+// fields and fields tagged json:"-" are not part of the key, so they
+// must not change the output.
+func (rc *RenderCache) DataKey(name string, data interface{}) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(b)
+	return fmt.Sprintf("tpl:%d:auto:%s", rc.generation(), hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// Invalidate removes the entry stored under an explicit key.
+func (rc *RenderCache) Invalidate(ctx context.Context, key string) error {
+	return rc.pages.Cache().Delete(ctx, fmt.Sprintf("tpl:%d:%s", rc.generation(), key))
+}
+
+// Stats returns the statistics of the underlying cache.
+func (rc *RenderCache) Stats() cache.Stats {
+	return rc.pages.Stats()
+}
+
+// generation returns the reload generation of the set.
+func (rc *RenderCache) generation() uint64 {
+	rc.set.mu.RLock()
+	defer rc.set.mu.RUnlock()
+	return rc.set.generation
+}
@@ -0,0 +1,57 @@
+package templates
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ReloadResult is the response of ReloadHandler.
+type ReloadResult struct {
+	OK        bool     `json:"ok"`
+	Templates int      `json:"templates"`        // views loaded after the request
+	Errors    []string `json:"errors,omitempty"` // one entry per broken view or failed hook
+}
+
+// ReloadHandler returns a handler reloading the set from disk, for
+// content editors pushing template updates without a restart. Mount it
+// behind authentication (e.g. POST /admin/templates/reload).
+//
+// The reload is atomic: if any view fails to parse, the loaded templates
+// are kept and every parse error is reported with status 422. After a
+// successful reload the hooks run, e.g. to swap the error page template;
+// their errors are reported the same way.
+//
+// Example response:
+//
+//	{"ok":false,"templates":12,"errors":["failed to parse template note.html: ..."]}
+func (ts *TemplateSet) ReloadHandler(hooks ...func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := ts.Reload()
+		if err == nil {
+			for _, h := range hooks {
+				err = errors.Join(err, h())
+			}
+		}
+
+		res := ReloadResult{OK: err == nil, Templates: len(ts.Names())}
+		status := http.StatusOK
+		if err != nil {
+			log.Printf("template reload failed: %v", err)
+			res.Errors = strings.Split(err.Error(), "\n")
+			status = http.StatusUnprocessableEntity
+		} else {
+			log.Printf("templates reloaded (%d)", res.Templates)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(res)
+	})
+}
@@ -14,6 +14,9 @@ package templates
 //   - Pre-rendering: Render templates to strings or bytes for caching, static site generation, or email
 //   - Template reloading: Hot-reload templates during development
 //   - Layout inheritance: Each view template automatically inherits from shared layouts
+//   - Block rendering: Render a single named block of a view (page fragments)
+//   - Output caching: RenderCache stores rendered output, keyed explicitly or by a data hash
+//   - Reload endpoint: ReloadHandler reloads on request and reports parse errors
 //
 // # Directory Structure
 //
@@ -71,20 +74,32 @@ package templates
 //   - Caching: Render once, serve many times using RenderToBytes
 //   - Layout sharing: Parse layout files once, clone for each view
 //
+// # Template Functions
+//
+// Functions are registered at load time. Request-specific functions
+// (e.g. CSRF tokens) are registered with placeholder implementations and
+// replaced per render with RenderToBytesFuncs:
+//
+//	tplSet, _ := templates.LoadTemplates("templates", csrf.Funcs())
+//	buf, _ := tplSet.RenderToBytesFuncs("login.html", csrf.FuncMap(r), data)
+//
 // # Development vs Production
 //
 //	if devMode {
-//	    tplSet.Reload() // Reload templates on each request
+//	    tplSet.SetAutoReload(true) // Reload templates on each lookup
 //	}
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 )
 
 // TemplateSet manages a collection of templates with shared layouts.
@@ -93,6 +108,12 @@ import (
 type TemplateSet struct {
 	Views   map[string]*template.Template // Map of template name to parsed template
 	baseDir string                        // Base directory for template reloading
+	funcs   template.FuncMap              // Functions registered at load time
+	masters map[string]*template.Template // Never-executed clones for RenderToBytesFuncs
+
+	mu         sync.RWMutex // guards Views during reloads
+	autoReload bool         // reload from disk before every lookup
+	generation uint64       // incremented by Reload (see RenderCache)
 }
 
 // LoadTemplates loads all templates from a directory with shared layouts.
@@ -105,18 +126,31 @@ type TemplateSet struct {
 //	├── layout/*.html  (shared layouts)
 //	└── *.html         (view templates)
 //
-// Returns an error if layouts cannot be loaded or if any view template fails to parse.
-func LoadTemplates(dir string) (*TemplateSet, error) {
+// Optional function maps are merged and registered with every template.
+//
+// Returns an error if layouts cannot be loaded or if any view template fails
+// to parse; the parse errors of all views are joined.
+func LoadTemplates(dir string, funcs ...template.FuncMap) (*TemplateSet, error) {
+	fm := template.FuncMap{}
+	for _, f := range funcs {
+		for k, v := range f {
+			fm[k] = v
+		}
+	}
+
 	// Load layouts
 	layoutPattern := filepath.Join(dir, "layout", "*.html")
-	layouts, err := template.ParseGlob(layoutPattern)
+	layouts, err := template.New("").Funcs(fm).ParseGlob(layoutPattern)
 	if err != nil {
-		log.Printf("failed to load layouts (skip): %w", err)
+		log.Printf("failed to load layouts (skip): %v", err)
+		layouts = nil
 	}
 
 	set := &TemplateSet{
 		Views:   make(map[string]*template.Template),
 		baseDir: dir,
+		funcs:   fm,
+		masters: make(map[string]*template.Template),
 	}
 
 	// Load view templates
@@ -125,6 +159,8 @@ func LoadTemplates(dir string) (*TemplateSet, error) {
 		return nil, fmt.Errorf("failed to read template directory: %w", err)
 	}
 
+	// Parse errors are collected so a reload reports every broken view
+	var errs []error
 	for _, entry := range entries {
 		// Skip directories and non-html files
 		if entry.IsDir() {
@@ -140,22 +176,36 @@ func LoadTemplates(dir string) (*TemplateSet, error) {
 
 		// Clone layouts and add view template
 		if layouts != nil {
-			tpl, err := layouts.Clone()
+			clone, err := layouts.Clone()
 			if err != nil {
 				return nil, fmt.Errorf("failed to clone layout for %s: %w", name, err)
 			}
-			_, err = tpl.ParseFiles(filepath.Join(dir, name))
+			_, err = clone.ParseFiles(filepath.Join(dir, name))
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+				errs = append(errs, fmt.Errorf("failed to parse template %s: %w", name, err))
+				continue
 			}
+			// Execute the view itself, not the first layout file
+			tpl = clone.Lookup(name)
 		} else {
-			tpl, err = template.ParseFiles(filepath.Join(dir, name))
+			tpl, err = template.New(name).Funcs(fm).ParseFiles(filepath.Join(dir, name))
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+				errs = append(errs, fmt.Errorf("failed to parse template %s: %w", name, err))
+				continue
 			}
 		}
 
+		// Keep an unexecuted copy: executed templates cannot be cloned
+		master, err := tpl.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone template %s: %w", name, err)
+		}
+
 		set.Views[name] = tpl
+		set.masters[name] = master
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
 	return set, nil
@@ -173,7 +223,7 @@ func LoadTemplates(dir string) (*TemplateSet, error) {
 //	var buf bytes.Buffer
 //	tpl.Execute(&buf, data)
 func (ts *TemplateSet) Get(name string) (*template.Template, error) {
-	tpl, ok := ts.Views[name]
+	tpl, ok := ts.lookup(name)
 	if !ok {
 		return nil, fmt.Errorf("template %s not found", name)
 	}
@@ -192,7 +242,7 @@ func (ts *TemplateSet) Get(name string) (*template.Template, error) {
 //	    tplSet.Render(w, "home.html", data)
 //	}
 func (ts *TemplateSet) Render(w http.ResponseWriter, name string, data interface{}) error {
-	tpl, ok := ts.Views[name]
+	tpl, ok := ts.lookup(name)
 	if !ok {
 		return fmt.Errorf("template %s not found", name)
 	}
@@ -208,7 +258,7 @@ func (ts *TemplateSet) Render(w http.ResponseWriter, name string, data interface
 //
 //	tplSet.RenderWithLayout(w, "dashboard.html", "admin", data)
 func (ts *TemplateSet) RenderWithLayout(w http.ResponseWriter, templateName, layoutName string, data interface{}) error {
-	tpl, ok := ts.Views[templateName]
+	tpl, ok := ts.lookup(templateName)
 	if !ok {
 		return fmt.Errorf("template %s not found", templateName)
 	}
@@ -257,7 +307,7 @@ func (ts *TemplateSet) RenderToString(name string, data interface{}) (string, er
 //	buf, _ := tplSet.RenderToBytes("sitemap.html", pages)
 //	os.WriteFile("public/sitemap.html", buf.Bytes(), 0644)
 func (ts *TemplateSet) RenderToBytes(name string, data interface{}) (*bytes.Buffer, error) {
-	tpl, ok := ts.Views[name]
+	tpl, ok := ts.lookup(name)
 	if !ok {
 		return nil, fmt.Errorf("template %s not found", name)
 	}
@@ -270,6 +320,74 @@ func (ts *TemplateSet) RenderToBytes(name string, data interface{}) (*bytes.Buff
 	return &buf, nil
 }
 
+// RenderToBytesFuncs renders a template to a byte buffer, replacing
+// template functions for this render only. Only functions registered at
+// load time can be replaced. The view is cloned per call, so prefer
+// RenderToBytes for templates without request-specific functions.
+//
+// Example (CSRF):
+//
+//	buf, _ := tplSet.RenderToBytesFuncs("login.html", csrf.FuncMap(r), data)
+func (ts *TemplateSet) RenderToBytesFuncs(name string, funcs template.FuncMap, data interface{}) (*bytes.Buffer, error) {
+	if _, ok := ts.lookup(name); !ok {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	ts.mu.RLock()
+	master := ts.masters[name]
+	ts.mu.RUnlock()
+
+	tpl, err := master.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Funcs(funcs).Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// RenderBlock renders a single named block (a {{define}} or {{block}})
+// of a view template to an HTTP response, e.g. to return a page fragment
+// for HTMX requests instead of the full page.
+//
+// Example:
+//
+//	tplSet.RenderBlock(w, "notes.html", "content", data)
+func (ts *TemplateSet) RenderBlock(w http.ResponseWriter, name, block string, data interface{}) error {
+	buf, err := ts.RenderBlockToBytes(name, block, data)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// RenderBlockToBytes renders a single named block of a view template to a
+// byte buffer. Rendering into a buffer first means a failing block does not
+// leave a partial response behind.
+func (ts *TemplateSet) RenderBlockToBytes(name, block string, data interface{}) (*bytes.Buffer, error) {
+	tpl, ok := ts.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+	if tpl.Lookup(block) == nil {
+		return nil, fmt.Errorf("block %s not found in template %s", block, name)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.ExecuteTemplate(&buf, block, data); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
 // RenderToStringWithLayout renders a template with a specific layout to a string.
 //
 // Example:
@@ -291,7 +409,7 @@ func (ts *TemplateSet) RenderToStringWithLayout(templateName, layoutName string,
 //	buf, _ := tplSet.RenderToBytesWithLayout("invoice.html", "print-layout", invoice)
 //	cache.Set("invoice-"+id, buf.Bytes(), time.Hour)
 func (ts *TemplateSet) RenderToBytesWithLayout(templateName, layoutName string, data interface{}) (*bytes.Buffer, error) {
-	tpl, ok := ts.Views[templateName]
+	tpl, ok := ts.lookup(templateName)
 	if !ok {
 		return nil, fmt.Errorf("template %s not found", templateName)
 	}
@@ -314,10 +432,30 @@ func (ts *TemplateSet) RenderToBytesWithLayout(templateName, layoutName string,
 //	    tplSet.Render(w, "default.html", data)
 //	}
 func (ts *TemplateSet) Has(name string) bool {
-	_, ok := ts.Views[name]
+	_, ok := ts.lookup(name)
 	return ok
 }
 
+// Names returns the names of all view templates in sorted order.
+//
+// Example (Static Site):
+//
+//	for _, name := range tplSet.Names() {
+//	    html, _ := tplSet.RenderToString(name, nil)
+//	    os.WriteFile(filepath.Join("dist", name), []byte(html), 0644)
+//	}
+func (ts *TemplateSet) Names() []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	names := make([]string, 0, len(ts.Views))
+	for name := range ts.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Reload reloads all templates from disk.
 // Useful in development mode to pick up template changes without restarting the server.
 //
@@ -332,15 +470,51 @@ func (ts *TemplateSet) Has(name string) bool {
 //
 // Note: In production, you typically load templates once at startup.
 func (ts *TemplateSet) Reload() error {
-	newSet, err := LoadTemplates(ts.baseDir)
+	ts.mu.RLock()
+	funcs := ts.funcs
+	ts.mu.RUnlock()
+
+	newSet, err := LoadTemplates(ts.baseDir, funcs)
 	if err != nil {
 		return err
 	}
 
+	ts.mu.Lock()
 	ts.Views = newSet.Views
+	ts.masters = newSet.masters
+	ts.generation++
+	ts.mu.Unlock()
 	return nil
 }
 
+// SetAutoReload enables or disables reloading all templates from disk
+// before every lookup. Intended for development (hot reload); a failed
+// reload is logged and the previously loaded templates are kept.
+func (ts *TemplateSet) SetAutoReload(enabled bool) {
+	ts.mu.Lock()
+	ts.autoReload = enabled
+	ts.mu.Unlock()
+}
+
+// lookup returns the view template by name, reloading first if
+// auto reload is enabled.
+func (ts *TemplateSet) lookup(name string) (*template.Template, bool) {
+	ts.mu.RLock()
+	reload := ts.autoReload
+	ts.mu.RUnlock()
+
+	if reload {
+		if err := ts.Reload(); err != nil {
+			log.Printf("template reload failed: %v", err)
+		}
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	tpl, ok := ts.Views[name]
+	return tpl, ok
+}
+
 type TemplateSetConfig struct {
 	Folder string `json:"Folder"`
 }
@@ -0,0 +1,295 @@
+package timewindow
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package timewindow restricts routes to (or blocks them during) configured
+times of day and days of the week.
+
+Summary
+-------
+- A Rule maps a path pattern (and optionally methods) to time windows:
+  - Allow: the route is only available inside one of these windows
+    (e.g. admin endpoints during business hours).
+  - Deny: the route is unavailable inside any of these windows
+    (e.g. a weekly maintenance window). Deny wins over Allow.
+- Rules are checked in order; the first matching rule applies.
+  Requests matching no rule are allowed.
+- Windows are evaluated in Config.Timezone (IANA name, default UTC).
+  A window whose end is before its start spans midnight; its days refer
+  to the day it starts.
+- Blocked requests are answered via the server error helpers with the
+  rule's status: 403 (default) or 503, which adds a Retry-After header
+  when the request falls into a deny window.
+- Patterns follow the acl package (exact, /prefix/*, path.Match glob).
+
+Example config:
+
+	"time_windows": {
+	  "timezone": "Europe/Berlin",
+	  "rules": [
+	    { "pattern": "/admin/*", "allow": [{ "days": ["mon", "tue", "wed", "thu", "fri"], "from": "08:00", "to": "18:00" }] },
+	    { "pattern": "/api/*", "methods": ["POST", "PUT", "DELETE"], "status": 503,
+	      "deny": [{ "days": ["sun"], "from": "02:00", "to": "04:00" }], "message": "Scheduled maintenance." }
+	  ]
+	}
+*/
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bennof/gobfwebservice/middleware"
+	"github.com/bennof/gobfwebservice/server"
+)
+
+// Window is a daily time range on selected days.
+type Window struct {
+	Days []string `json:"days,omitempty"` // "mon" ... "sun"; empty means every day
+	From string   `json:"from"`           // start "HH:MM" (inclusive)
+	To   string   `json:"to"`             // end "HH:MM" (exclusive); "24:00" is the end of the day
+}
+
+// Rule defines the time windows of matching requests.
+type Rule struct {
+	Pattern string   `json:"pattern"`           // path pattern (see package doc)
+	Methods []string `json:"methods,omitempty"` // restrict the rule to methods; empty means all
+	Allow   []Window `json:"allow,omitempty"`   // only available inside one of these windows
+	Deny    []Window `json:"deny,omitempty"`    // unavailable inside any of these windows
+	Status  int      `json:"status,omitempty"`  // 403 (default) or 503
+	Message string   `json:"message,omitempty"` // shown on the error page
+}
+
+// Config defines the time window rules.
+type Config struct {
+	Timezone string `json:"timezone"` // IANA time zone, e.g. "Europe/Berlin"
+	Rules    []Rule `json:"rules"`
+}
+
+// DefaultConfig returns a configuration without rules (always allowed).
+func DefaultConfig() Config {
+	return Config{
+		Timezone: "UTC",
+		Rules:    []Rule{},
+	}
+}
+
+// Windows is a compiled set of rules.
+type Windows struct {
+	loc   *time.Location
+	rules []rule
+	now   func() time.Time
+}
+
+// rule is a Rule with parsed windows.
+type rule struct {
+	Rule
+	allow, deny []window
+}
+
+// window is a parsed Window; from and to are minutes since midnight.
+type window struct {
+	days     [7]bool
+	from, to int
+}
+
+// New compiles the rules of cfg. It fails on unknown time zones,
+// malformed patterns, days or times and unsupported status codes.
+func New(cfg ...Config) (*Windows, error) {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	loc := time.UTC
+	if c.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.Timezone); err != nil {
+			return nil, fmt.Errorf("timewindow: %w", err)
+		}
+	}
+
+	tw := &Windows{loc: loc, now: time.Now}
+	for i, r := range c.Rules {
+		if !strings.HasPrefix(r.Pattern, "/") {
+			return nil, fmt.Errorf("timewindow: rule %d: pattern %q must start with /", i, r.Pattern)
+		}
+		if _, err := path.Match(r.Pattern, "/"); err != nil {
+			return nil, fmt.Errorf("timewindow: rule %d: %w", i, err)
+		}
+		switch r.Status {
+		case 0:
+			r.Status = http.StatusForbidden
+		case http.StatusForbidden, http.StatusServiceUnavailable:
+		default:
+			return nil, fmt.Errorf("timewindow: rule %d: status must be 403 or 503", i)
+		}
+
+		cr := rule{Rule: r}
+		var err error
+		if cr.allow, err = parseWindows(r.Allow); err != nil {
+			return nil, fmt.Errorf("timewindow: rule %d: %w", i, err)
+		}
+		if cr.deny, err = parseWindows(r.Deny); err != nil {
+			return nil, fmt.Errorf("timewindow: rule %d: %w", i, err)
+		}
+		tw.rules = append(tw.rules, cr)
+	}
+	return tw, nil
+}
+
+// SetClock replaces the time source (default: time.Now).
+func (tw *Windows) SetClock(now func() time.Time) {
+	tw.now = now
+}
+
+// Check reports whether r is allowed now. If not, it returns the
+// blocking rule and, for deny windows, the end of the window.
+func (tw *Windows) Check(r *http.Request) (ok bool, blocked Rule, until time.Time) {
+	now := tw.now().In(tw.loc)
+	for _, ru := range tw.rules {
+		if !ru.matches(r) {
+			continue
+		}
+
+		for _, w := range ru.deny {
+			if end, in := w.contains(now); in {
+				return false, ru.Rule, end
+			}
+		}
+		if len(ru.allow) > 0 && !slices.ContainsFunc(ru.allow, func(w window) bool {
+			_, in := w.contains(now)
+			return in
+		}) {
+			return false, ru.Rule, time.Time{}
+		}
+		return true, Rule{}, time.Time{}
+	}
+	return true, Rule{}, time.Time{}
+}
+
+// Middleware returns a middleware enforcing the rules.
+func (tw *Windows) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if len(tw.rules) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, ru, until := tw.Check(r)
+			if ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ru.Status == http.StatusServiceUnavailable && !until.IsZero() {
+				secs := int(until.Sub(tw.now()).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+			}
+			if ru.Message == "" {
+				if ru.Status == http.StatusServiceUnavailable {
+					server.ServiceUnavailable(w, r)
+				} else {
+					server.Forbidden(w, r)
+				}
+				return
+			}
+			server.RenderError(w, r, ru.Status, http.StatusText(ru.Status), ru.Message)
+		})
+	}
+}
+
+// matches reports whether the rule applies to r.
+func (ru *rule) matches(r *http.Request) bool {
+	if len(ru.Methods) > 0 && !slices.ContainsFunc(ru.Methods, func(m string) bool {
+		return strings.EqualFold(m, r.Method)
+	}) {
+		return false
+	}
+
+	p := path.Clean(r.URL.Path)
+	if base, ok := strings.CutSuffix(ru.Pattern, "/*"); ok {
+		return p == base || strings.HasPrefix(p, base+"/") || (base == "" && p == "/")
+	}
+	ok, _ := path.Match(ru.Pattern, p)
+	return ok
+}
+
+// contains reports whether t lies inside the window and returns the
+// end of the window occurrence containing it.
+func (w window) contains(t time.Time) (time.Time, bool) {
+	mins := t.Hour()*60 + t.Minute()
+	day := int(t.Weekday())
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	if w.from < w.to {
+		if w.days[day] && mins >= w.from && mins < w.to {
+			return midnight.Add(time.Duration(w.to) * time.Minute), true
+		}
+		return time.Time{}, false
+	}
+
+	// Spans midnight: the evening part belongs to today, the morning
+	// part to the window that started yesterday.
+	if w.days[day] && mins >= w.from {
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(w.to) * time.Minute), true
+	}
+	if w.days[(day+6)%7] && mins < w.to {
+		return midnight.Add(time.Duration(w.to) * time.Minute), true
+	}
+	return time.Time{}, false
+}
+
+// weekdays maps day names to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWindows parses the windows of a rule.
+func parseWindows(ws []Window) ([]window, error) {
+	out := make([]window, 0, len(ws))
+	for _, w := range ws {
+		var pw window
+		if len(w.Days) == 0 {
+			pw.days = [7]bool{true, true, true, true, true, true, true}
+		}
+		for _, d := range w.Days {
+			wd, ok := weekdays[strings.ToLower(d)[:min(3, len(d))]]
+			if !ok {
+				return nil, fmt.Errorf("invalid day %q", d)
+			}
+			pw.days[wd] = true
+		}
+
+		var err error
+		if pw.from, err = parseClock(w.From); err != nil {
+			return nil, err
+		}
+		if pw.to, err = parseClock(w.To); err != nil {
+			return nil, err
+		}
+		if pw.from == pw.to {
+			return nil, fmt.Errorf("empty window %s-%s", w.From, w.To)
+		}
+		out = append(out, pw)
+	}
+	return out, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight ("24:00" allowed).
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hh, herr := strconv.Atoi(h)
+	mm, merr := strconv.Atoi(m)
+	if !ok || herr != nil || merr != nil || hh < 0 || mm < 0 || mm > 59 || hh > 24 || (hh == 24 && mm != 0) {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return hh*60 + mm, nil
+}
@@ -0,0 +1,253 @@
+package workerpool
+
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Benjamin Benno Falkner
+
+/*
+Package workerpool runs background tasks on a bounded set of goroutines.
+
+Summary
+-------
+- A fixed number of workers process tasks from a bounded queue, so
+  handlers can hand off work (mails, webhooks, thumbnails, ...) without
+  spawning unbounded goroutines with bare go statements.
+- Submit blocks until the task is queued (or the caller's context is
+  done); TrySubmit fails fast with ErrFull.
+- Tasks run with the pool context, not the request context, so they
+  outlive the request that submitted them.
+- Panics in tasks are recovered and counted; errors are logged.
+- Close stops accepting tasks and drains the queue; once the drain
+  deadline passes the task context is cancelled.
+- Pool implements lifecycle.Runner: added to a lifecycle.Manager before
+  the HTTP server it is drained after the server stopped accepting
+  requests.
+
+Typical usage:
+
+	pool := workerpool.New(workerpool.Config{Workers: 4, Queue: 100, DrainTimeout: 10})
+	m.Add("workers", pool)
+	m.Add("http", lifecycle.Server(srv, 30*time.Second))
+
+	// in a handler
+	err := pool.Submit(r.Context(), func(ctx context.Context) error {
+	    return mailer.Send(ctx, msg)
+	})
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned by Submit and TrySubmit.
+var (
+	ErrClosed = errors.New("workerpool: pool closed")
+	ErrFull   = errors.New("workerpool: queue full")
+)
+
+// Task is a unit of work. ctx is cancelled when the pool is force-stopped.
+type Task func(ctx context.Context) error
+
+// Config defines the pool size and shutdown behavior.
+type Config struct {
+	Workers      int `json:"workers"`       // number of goroutines
+	Queue        int `json:"queue"`         // queued tasks before Submit blocks
+	DrainTimeout int `json:"drain_timeout"` // seconds to finish queued tasks on shutdown
+}
+
+// DefaultConfig returns a small pool suitable for most services.
+func DefaultConfig() Config {
+	return Config{
+		Workers:      4,
+		Queue:        128,
+		DrainTimeout: 10,
+	}
+}
+
+// Stats is a snapshot of the pool counters.
+type Stats struct {
+	Submitted uint64 `json:"submitted"`
+	Completed uint64 `json:"completed"`
+	Failed    uint64 `json:"failed"` // returned an error or panicked
+	Panics    uint64 `json:"panics"`
+	Dropped   uint64 `json:"dropped"` // discarded by a forced stop
+	Queued    int    `json:"queued"`
+	Active    int64  `json:"active"`
+}
+
+// Pool is a bounded worker pool.
+type Pool struct {
+	config Config
+	tasks  chan Task
+
+	ctx    context.Context // task context
+	cancel context.CancelFunc
+
+	quit   chan struct{} // closed first on Close to release blocked Submits
+	mu     sync.RWMutex  // guards closed against concurrent sends
+	closed bool
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	submitted, completed, failed, panics, dropped atomic.Uint64
+	active                                        atomic.Int64
+}
+
+// New creates a pool and starts its workers.
+func New(cfg ...Config) *Pool {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Workers <= 0 {
+		c.Workers = 1
+	}
+	if c.Queue < 0 {
+		c.Queue = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		config: c,
+		tasks:  make(chan Task, c.Queue),
+		quit:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	p.wg.Add(c.Workers)
+	for range c.Workers {
+		go p.worker()
+	}
+	return p
+}
+
+// Submit queues a task, blocking while the queue is full.
+// It returns ctx.Err() if ctx is done first and ErrClosed after Close.
+func (p *Pool) Submit(ctx context.Context, t Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- t:
+		p.submitted.Add(1)
+		return nil
+	case <-p.quit:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit queues a task without blocking.
+func (p *Pool) TrySubmit(t Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- t:
+		p.submitted.Add(1)
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+// Close stops accepting tasks and waits for queued and running tasks.
+// When ctx is done first, the task context is cancelled and Close
+// returns ctx.Err() once the workers have exited.
+func (p *Pool) Close(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.quit)
+		p.mu.Lock()
+		p.closed = true
+		close(p.tasks)
+		p.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return fmt.Errorf("workerpool: drain aborted, %d tasks dropped: %w", p.dropped.Load(), ctx.Err())
+	}
+}
+
+// Run implements lifecycle.Runner: it blocks until ctx is cancelled and
+// then drains the pool within Config.DrainTimeout.
+func (p *Pool) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	dctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.config.DrainTimeout)*time.Second)
+	defer cancel()
+	return p.Close(dctx)
+}
+
+// Stats returns a snapshot of the pool counters.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Panics:    p.panics.Load(),
+		Dropped:   p.dropped.Load(),
+		Queued:    len(p.tasks),
+		Active:    p.active.Load(),
+	}
+}
+
+// worker processes tasks until the queue is closed. After a forced
+// stop remaining tasks are discarded.
+func (p *Pool) worker() {
+	defer p.wg.Done()
+
+	for t := range p.tasks {
+		if p.ctx.Err() != nil {
+			p.dropped.Add(1)
+			continue
+		}
+		p.run(t)
+	}
+}
+
+// run executes a single task with panic recovery.
+func (p *Pool) run(t Task) {
+	p.active.Add(1)
+	defer p.active.Add(-1)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			p.panics.Add(1)
+			p.failed.Add(1)
+			log.Printf("workerpool: task panic: %v\n%s", rec, debug.Stack())
+		}
+	}()
+
+	if err := t(p.ctx); err != nil {
+		p.failed.Add(1)
+		log.Printf("workerpool: task failed: %v", err)
+		return
+	}
+	p.completed.Add(1)
+}